@@ -11,7 +11,7 @@ const useStyles = makeStyles(theme => ({
     marginTop: "8px",
     marginBottom: "8px",
     width: "100%",
-    height: "90px",
+    minHeight: "90px",
     display: "flex",
     flexDirection: "row",
     flexWrap: "nowrap",
@@ -23,8 +23,16 @@ const useStyles = makeStyles(theme => ({
     marginTop: "auto",
     marginBottom: "auto",
   },
+  cover: {
+    width: "80px",
+    height: "80px",
+    objectFit: "contain",
+    marginTop: "auto",
+    marginBottom: "auto",
+  },
   info: {
     padding: "8px",
+    minWidth: 0,
   },
   title: {
     fontSize: "14pt",
@@ -48,6 +56,19 @@ const useStyles = makeStyles(theme => ({
     color: "#a27e39",
     marginLeft: "0.5em",
   },
+  publisher: {
+    fontSize: "10pt",
+    color: "#888888",
+    marginLeft: "0.5em",
+  },
+  description: {
+    fontSize: "10pt",
+    color: "#414141",
+    marginTop: "3px",
+    overflow: "hidden",
+    textOverflow: "ellipsis",
+    whiteSpace: "nowrap",
+  },
   ratingColumn: {
     marginLeft: "auto",
     marginTop: "auto",
@@ -65,7 +86,11 @@ export default function Book(props: { book: models.Book }) {
   const b = props.book;
   return (
     <div className={classes.tile}>
-      <div className={classes.icon} />
+      {b.coverUrl ? (
+        <img className={classes.cover} src={b.coverUrl} alt={b.title} />
+      ) : (
+        <div className={classes.icon} />
+      )}
       <div className={classes.info}>
         <div className={classes.title}>{b.title}</div>
         <div>
@@ -77,7 +102,23 @@ export default function Book(props: { book: models.Book }) {
         <div className={classes.genreRow}>
           <Chip label={b.genre.title} size="small" />
           <span className={classes.pages}>{b.pages} pages</span>
+          {b.language && (
+            <Chip
+              className={classes.pages}
+              label={b.language}
+              size="small"
+              variant="outlined"
+            />
+          )}
+          {b.publisher && (
+            <span className={classes.publisher}>{b.publisher}</span>
+          )}
         </div>
+        {b.description && (
+          <div className={classes.description} title={b.description}>
+            {b.description}
+          </div>
+        )}
       </div>
       <div className={classes.ratingColumn}>
         <Rating
@@ -4,6 +4,12 @@ export type Book = {
   yearPublished: number;
   rating: number;
   pages: number;
+  isbn10?: string;
+  isbn13?: string;
+  language?: string;
+  publisher?: string;
+  description?: string;
+  coverUrl?: string;
   genre: {
     id: number;
     title: string;
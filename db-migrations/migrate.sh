@@ -1,3 +1,7 @@
 #!/bin/bash
-# Creates the database tables and seeds them with sample data
+# Creates the database tables and seeds them with sample data, then applies the
+# incremental migrations in the migrations directory in lexical order
 psql -a -q -f /docker-entrypoint-initdb.d/migrate.psql $DB_URL
+for migration in /docker-entrypoint-initdb.d/migrations/*.psql; do
+  psql -a -q -v ON_ERROR_STOP=1 -f "$migration" $DB_URL
+done
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// Command is a subcommand of the service, run instead of serving the API with the
+// arguments following its name. Its writes are recorded in the audit log as made by an admin.
+type Command func(ctx context.Context, configValues config.Config, storeAdapter stores.Store, args []string, out io.Writer) error
+
+// Commands are the subcommands of the service by name
+var Commands = map[string]Command{
+	"dedupe-authors": DedupeAuthors,
+	"check-data":     CheckData,
+	"ranking-prior":  SetRankingPrior,
+}
+
+// DedupeAuthors prints the candidate duplicate authors for review, or, with -merge and
+// -into, merges an author into another one
+func DedupeAuthors(ctx context.Context, configValues config.Config, storeAdapter stores.Store, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("dedupe-authors", flag.ContinueOnError)
+	flags.SetOutput(out)
+	minSimilarity := flags.String("min-similarity", "", "trigram similarity from which two full names are candidates, from 0 to 1")
+	limit := flags.String("limit", "", "maximum number of candidates to print")
+	merge := flags.Int64("merge", 0, "ID of the author to merge into the one of -into")
+	into := flags.Int64("into", 0, "ID of the author to merge the one of -merge into")
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	mediatorFactory := authorDedupeMediatorFactory(configValues, storeAdapter)
+	if *merge != 0 || *into != 0 {
+		if *merge < 1 {
+			return errors.New("invalid -merge: should be a positive author ID")
+		}
+		req := models.MergeAuthorRequest{Into: *into}
+		if err := req.Validate(); err != nil {
+			return fmt.Errorf("invalid -into: %w", err)
+		}
+
+		author, err := mediatorFactory().Merge(stores.WithAuditSource(ctx, models.AuditAdmin, ""), *merge, req)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "merged author %d into %d %s %s, who has %d books\n",
+			*merge, author.ID, author.FirstName, author.LastName, author.BookCount)
+		return nil
+	}
+
+	req := models.DuplicateAuthorsRequest{MinSimilarity: *minSimilarity, Limit: *limit}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("invalid flags: %w", err)
+	}
+	duplicates, err := mediatorFactory().Duplicates(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeDuplicates(out, duplicates)
+}
+
+// writeDuplicates prints the candidate duplicate authors as a table, along with how to merge them
+func writeDuplicates(out io.Writer, duplicates []models.DuplicateAuthors) error {
+	if len(duplicates) == 0 {
+		_, err := fmt.Fprintln(out, "no candidate duplicate authors")
+		return err
+	}
+
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "KEEP\tMERGE\tSAME NAME\tSIMILARITY\tSHARED TITLES")
+	candidate := func(author models.AuthorCandidate) string {
+		return fmt.Sprintf("%d %s %s (%d books)", author.ID, author.FirstName, author.LastName, author.BookCount)
+	}
+	for _, pair := range duplicates {
+		sameName := "no"
+		if pair.SameNormalizedName {
+			sameName = "yes"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%.2f\t%s\n", candidate(pair.Keep), candidate(pair.Merge),
+			sameName, pair.NameSimilarity, strings.Join(pair.SharedTitles, "; "))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(out, "\nmerge a pair with: dedupe-authors -merge <MERGE ID> -into <KEEP ID>")
+	return err
+}
+
+// CheckData checks the data of the catalog against the rules of -rules, the configured ones
+// when unset, and prints the rows breaking each of them. It fails when any rule is broken,
+// so that it can gate a deployment.
+func CheckData(ctx context.Context, configValues config.Config, storeAdapter stores.Store, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("check-data", flag.ContinueOnError)
+	flags.SetOutput(out)
+	rules := flags.String("rules", "", "comma-delimited rules to check, of: "+strings.Join(models.DataQualityRules, ", "))
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	req := models.DataQualityRequest{Rules: *rules}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("invalid flags: %w", err)
+	}
+	report, err := dataQualityMediatorFactory(configValues, storeAdapter)().Check(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeDataQuality(out, report)
+}
+
+// writeDataQuality prints the outcome of every rule of the report along with the rows
+// breaking it, and returns an error when the report did not pass
+func writeDataQuality(out io.Writer, report models.DataQualityReport) error {
+	failed := 0
+	for _, result := range report.Results {
+		if result.Passed {
+			fmt.Fprintf(out, "PASS  %s: %s\n", result.Rule, result.Description)
+			continue
+		}
+		failed++
+		fmt.Fprintf(out, "FAIL  %s: %s, %d violations\n", result.Rule, result.Description, result.Violations)
+		for _, row := range result.Rows {
+			fmt.Fprintf(out, "      %s %d %s\n", row.Entity, row.ID, row.Detail)
+		}
+		if more := result.Violations - int64(len(result.Rows)); more > 0 {
+			fmt.Fprintf(out, "      and %d more\n", more)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d data-quality rules failed", failed, len(report.Results))
+	}
+	_, err := fmt.Fprintf(out, "all %d data-quality rules passed\n", len(report.Results))
+	return err
+}
+
+// SetRankingPrior stores the prior of RANKING_PRIOR_MEAN and RANKING_PRIOR_WEIGHT used for
+// the weighted rating. Changing it makes the database refresh the weighted rating of every
+// book, so it is run once after the prior is changed rather than on every start.
+func SetRankingPrior(ctx context.Context, configValues config.Config, storeAdapter stores.Store, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("ranking-prior", flag.ContinueOnError)
+	flags.SetOutput(out)
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	bookStore := stores.NewBookStore(log.WithField("*store", "Book"), storeAdapter.GetDB())
+	if err := bookStore.UpdateRankingPrior(ctx, configValues.Ranking.PriorMean, configValues.Ranking.PriorWeight); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "ranking prior set to a mean of %.2f over %d ratings\n",
+		configValues.Ranking.PriorMean, configValues.Ranking.PriorWeight)
+	return err
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDuplicates(t *testing.T) {
+	var out bytes.Buffer
+	err := writeDuplicates(&out, []models.DuplicateAuthors{
+		{
+			Keep:               models.AuthorCandidate{Author: models.Author{ID: 7, FirstName: "Ward", LastName: "Haigh"}, BookCount: 12},
+			Merge:              models.AuthorCandidate{Author: models.Author{ID: 31, FirstName: "Ward", LastName: "haigh"}, BookCount: 1},
+			SameNormalizedName: true,
+			NameSimilarity:     1,
+			SharedTitles:       []string{"Adventures of Kaya"},
+		},
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Regexp(t, `^KEEP\s+MERGE\s+SAME NAME\s+SIMILARITY\s+SHARED TITLES$`, lines[0])
+	assert.Regexp(t, `^7 Ward Haigh \(12 books\)\s+31 Ward haigh \(1 books\)\s+yes\s+1\.00\s+Adventures of Kaya$`, lines[1])
+	assert.Contains(t, out.String(), "dedupe-authors -merge <MERGE ID> -into <KEEP ID>")
+
+	out.Reset()
+	require.NoError(t, writeDuplicates(&out, nil))
+	assert.Equal(t, "no candidate duplicate authors\n", out.String())
+}
+
+func TestDedupeAuthors_InvalidFlags(t *testing.T) {
+	var cases = []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "similarity", args: []string{"-min-similarity", "2"}, err: "should be between 0 and 1"},
+		{name: "merge without into", args: []string{"-merge", "31"}, err: "invalid -into"},
+		{name: "into without merge", args: []string{"-into", "7"}, err: "invalid -merge"},
+		{name: "unknown flag", args: []string{"-all"}, err: "flag provided but not defined"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := DedupeAuthors(context.Background(), config.Config{}, stores.Store{}, c.args, &out)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), c.err)
+		})
+	}
+}
+
+func TestWriteDataQuality(t *testing.T) {
+	var out bytes.Buffer
+	err := writeDataQuality(&out, models.DataQualityReport{
+		Results: []models.DataQualityResult{
+			{Rule: models.RuleBookPages, Description: "pages", Passed: true},
+			{Rule: models.RuleOrphanGenre, Description: "orphans", Violations: 3, Rows: []models.DataQualityRow{
+				{Entity: "genre", ID: 3, Detail: "Poetry has no book nor subgenre"},
+				{Entity: "genre", ID: 8, Detail: "Essay has no book nor subgenre"},
+			}},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "1 of 2 data-quality rules failed", err.Error())
+	assert.Equal(t, []string{
+		"PASS  book-pages: pages",
+		"FAIL  orphan-genre: orphans, 3 violations",
+		"      genre 3 Poetry has no book nor subgenre",
+		"      genre 8 Essay has no book nor subgenre",
+		"      and 1 more",
+		"",
+	}, strings.Split(out.String(), "\n"))
+
+	out.Reset()
+	err = writeDataQuality(&out, models.DataQualityReport{
+		Passed:  true,
+		Results: []models.DataQualityResult{{Rule: models.RuleBookPages, Description: "pages", Passed: true}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "all 1 data-quality rules passed")
+}
+
+func TestCheckData_InvalidFlags(t *testing.T) {
+	var out bytes.Buffer
+	err := CheckData(context.Background(), config.Config{}, stores.Store{}, []string{"-rules", "book-pages,spelling"}, &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid flags")
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/rpc"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// GRPCServer prepares the gRPC server to be served, over the same mediators as the routes
+func GRPCServer(configValues config.Config, storeAdapter stores.Store) *grpc.Server {
+	catalog := &rpc.CatalogServer{
+		Logger:                log.WithField("*server", "Catalog"),
+		BookMediatorFactory:   bookMediatorFactory(configValues, storeAdapter),
+		AuthorMediatorFactory: authorMediatorFactory(configValues, storeAdapter),
+		GenreMediatorFactory:  genreMediatorFactory(storeAdapter),
+		SizeMediatorFactory:   sizeMediatorFactory(storeAdapter),
+		EraMediatorFactory:    eraMediatorFactory(storeAdapter),
+		UserIDSecret:          configValues.UserIDSecret,
+	}
+	return rpc.NewServer(log.WithField("*server", "gRPC"), catalog, configValues.RequestTimeout)
+}
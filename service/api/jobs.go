@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/jobs"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// StartJobs starts the background jobs of the service, they stop when the context is done
+func StartJobs(ctx context.Context, configValues config.Config, storeAdapter stores.Store) {
+	recommendationMediator := recommendationMediatorFactory(configValues, storeAdapter)()
+	go jobs.Schedule(
+		ctx,
+		log.WithField("*job", "BookSimilarity"),
+		configValues.Recommendation.RefreshInterval,
+		recommendationMediator.RefreshSimilarities,
+	)
+
+	webhookMediator := webhookMediatorFactory(configValues, storeAdapter)()
+	go jobs.Schedule(
+		ctx,
+		log.WithField("*job", "WebhookDispatcher"),
+		configValues.Webhook.DispatchInterval,
+		webhookMediator.Dispatch,
+	)
+
+	auditMediator := auditMediatorFactory(configValues, storeAdapter)()
+	go jobs.Schedule(
+		ctx,
+		log.WithField("*job", "AuditRetention"),
+		configValues.Audit.PruneInterval,
+		auditMediator.Prune,
+	)
+}
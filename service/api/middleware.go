@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/stores"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// withRequestID gives every request an ID, the one of its X-Request-ID header when valid,
+// and sets it on the response so that clients and error responses can refer to it
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := translators.ToRequestID(r.Header.Get(translators.RequestIDHeader))
+		r.Header.Set(translators.RequestIDHeader, id)
+		w.Header().Set(translators.RequestIDHeader, id)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withTimeout cancels the context of the requests lasting longer than timeout, no
+// timeout applies when it is zero
+func withTimeout(timeout time.Duration, next http.Handler) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withUserSignature only lets through the requests whose X-User-ID header, if any, bears the
+// signature of the gateway, which alone can authenticate users: a caller setting the header
+// itself is unauthorized rather than served as the user
+func withUserSignature(secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(translators.UserIDHeader)
+		if header != "" && !translators.VerifyUserID(secret, header, r.Header.Get(translators.UserSignatureHeader)) {
+			translators.ParseError(w, http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withAdminToken only lets through the requests bearing the admin token in their
+// Authorization header, every request is unauthorized when no token is configured
+func withAdminToken(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				translators.ParseError(w, http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withAuditSource records the writes of the requests in the audit log as made by the user of
+// their signed X-User-ID header, or by defaultActor when there is none
+func withAuditSource(defaultActor string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := defaultActor
+			if userID, ok := translators.ToUserID(r); ok {
+				actor = "user:" + strconv.FormatInt(userID, 10)
+			}
+			ctx := stores.WithAuditSource(r.Context(), actor, r.Header.Get(translators.RequestIDHeader))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// withContract validates the requests against the OpenAPI specification, answering the
+// invalid ones with the problem listing every invalid parameter or field. The requests
+// of no operation of the specification are left to the router. With checkResponses, the
+// responses are validated as well and the ones breaking the specification replaced by a
+// server error, which is meant for the tests.
+func withContract(router routers.Router, checkResponses bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			// the admin routes check their token themselves
+			Options: &openapi3filter.Options{MultiError: true, AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			if detail, fieldErrs := toContractErrors(err); len(fieldErrs) > 0 {
+				translators.ParseValidationError(w, detail, fieldErrs)
+				return
+			}
+		}
+
+		if !checkResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{header: w.Header().Clone(), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if err := checkResponse(r, input, recorder); err != nil {
+			log.WithField("url", r.URL).WithError(err).Error("response breaking the openapi spec")
+			translators.ParseError(w, http.StatusInternalServerError)
+			return
+		}
+
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+	})
+}
+
+// toContractErrors returns the messages of every invalid parameter or field of the
+// request, along with the detail of the problem. The controllers answer the requests
+// without a valid user ID themselves, as unauthorized rather than bad requests.
+func toContractErrors(err error) (string, validation.Errors) {
+	detail := translators.ErrBadRequest
+	fieldErrs := validation.Errors{}
+
+	var requestErrs openapi3.MultiError
+	if !errors.As(err, &requestErrs) {
+		requestErrs = openapi3.MultiError{err}
+	}
+	for _, requestErr := range requestErrs {
+		var paramErr *openapi3filter.RequestError
+		if !errors.As(requestErr, &paramErr) {
+			fieldErrs["request"] = requestErr
+			continue
+		}
+
+		switch {
+		case paramErr.Parameter != nil:
+			if paramErr.Parameter.In == openapi3.ParameterInHeader && paramErr.Parameter.Name == translators.UserIDHeader {
+				continue
+			}
+			if paramErr.Parameter.In == openapi3.ParameterInPath && detail != translators.ErrBadBody {
+				detail = translators.ErrBadPath
+			}
+			fieldErrs[paramErr.Parameter.Name] = errors.New(reasonOf(paramErr))
+		case paramErr.RequestBody != nil:
+			detail = translators.ErrBadBody
+			var schemaErrs openapi3.MultiError
+			if !errors.As(paramErr.Err, &schemaErrs) {
+				schemaErrs = openapi3.MultiError{paramErr.Err}
+			}
+			for _, schemaErr := range schemaErrs {
+				field, message := fieldOf(schemaErr)
+				fieldErrs[field] = errors.New(message)
+			}
+		default:
+			fieldErrs["request"] = errors.New(reasonOf(paramErr))
+		}
+	}
+	return detail, fieldErrs
+}
+
+// reasonOf returns the message of the error of a parameter, without the schema it breaks
+func reasonOf(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err.Reason != "" {
+		return err.Reason
+	}
+	return err.Err.Error()
+}
+
+// unknownFieldPattern matches the reason of the schema errors about unknown fields
+var unknownFieldPattern = regexp.MustCompile(`^property "(.+)" is unsupported$`)
+
+// fieldOf returns the path of the field of the body a schema error is about, dot
+// separated as the translators name nested fields, along with its message
+func fieldOf(err error) (string, string) {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return "body", err.Error()
+	}
+
+	path := schemaErr.JSONPointer()
+	message := schemaErr.Reason
+	if match := unknownFieldPattern.FindStringSubmatch(message); match != nil {
+		path = append(path, match[1])
+		message = "is not a known field"
+	}
+	if len(path) == 0 {
+		return "body", message
+	}
+	return strings.Join(path, "."), message
+}
+
+// checkResponse validates a recorded response against the specification. The server
+// errors are left out, they are problems any operation may answer.
+func checkResponse(r *http.Request, input *openapi3filter.RequestValidationInput, recorder *responseRecorder) error {
+	if recorder.status >= http.StatusInternalServerError {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(recorder.header.Get("Content-Type"))
+	return openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 recorder.status,
+		Header:                 recorder.header,
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+			// only the JSON bodies have a schema worth checking
+			ExcludeResponseBody: mediaType != "" && mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json"),
+		},
+	})
+}
+
+// responseRecorder holds a response until it is checked
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(content []byte) (int, error) {
+	return r.body.Write(content)
+}
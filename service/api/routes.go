@@ -5,102 +5,401 @@ import (
 
 	"github.com/book-recommendations/service/config"
 	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/graph"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/openapi"
 	"github.com/book-recommendations/service/stores"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 	"github.com/rs/cors"
 	log "github.com/sirupsen/logrus"
 )
 
+// appControllers groups the controllers serving the routes
+type appControllers struct {
+	book           controllers.BookController
+	author         controllers.AuthorController
+	genre          controllers.GenreController
+	size           controllers.SizeController
+	era            controllers.EraController
+	event          controllers.EventController
+	recommendation controllers.RecommendationController
+	shelf          controllers.ShelfController
+	series         controllers.SeriesController
+	webhook        controllers.WebhookController
+	audit          controllers.AuditController
+	authorDedupe   controllers.AuthorDedupeController
+	dataQuality    controllers.DataQualityController
+	graphql        controllers.GraphQLController
+	openAPI        controllers.OpenAPIController
+}
+
 // Routes prepares the mux router to be served
-func Routes() http.Handler {
+func Routes(configValues config.Config, storeAdapter stores.Store) http.Handler {
+	doc, err := openapi.Load()
+	if err != nil {
+		log.WithField("error", err).Fatal("error loading openapi spec")
+	}
+	contract, err := openapi.NewRouter(doc)
+	if err != nil {
+		log.WithField("error", err).Fatal("error routing openapi spec")
+	}
+
 	// initialize controllers
-	bookController, authorController, genreController, sizeController, eraController := generateControllers()
+	c := generateControllers(configValues, storeAdapter, doc)
 
-	router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+	root := mux.NewRouter()
+	root.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		translators.ParseError(w, http.StatusNotFound)
+	})
+	root.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		translators.ParseError(w, http.StatusMethodNotAllowed)
+	})
+	registerRoutes(root, c, configValues.AdminToken)
 
-	// routes
-	router.HandleFunc("/books", bookController.Get).Methods(http.MethodGet)
-	router.HandleFunc("/authors", authorController.Get).Methods(http.MethodGet)
-	router.HandleFunc("/genres", genreController.Get).Methods(http.MethodGet)
-	router.HandleFunc("/sizes", sizeController.Get).Methods(http.MethodGet)
-	router.HandleFunc("/eras", eraController.Get).Methods(http.MethodGet)
+	return cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{translators.ExperimentHeader, translators.RequestIDHeader},
+	}).Handler(withRequestID(withUserSignature(configValues.UserIDSecret,
+		withTimeout(configValues.RequestTimeout, withContract(contract, configValues.CheckResponses, root)))))
+}
+
+// registerRoutes registers the routes of every version of the API, every one of them is
+// in the OpenAPI spec. The lists of v2 are wrapped in an envelope, on the same mediators,
+// and the admin routes are only open to the requests bearing the admin token. The writes of
+// every request are recorded in the audit log as made by its user.
+func registerRoutes(root *mux.Router, c appControllers, adminToken string) {
+	router := root.PathPrefix("/api/v1").Subrouter()
+	router.Use(withAuditSource(models.AuditAnonymous))
+	router.HandleFunc("/books", c.book.Get).Methods(http.MethodGet)
+	router.HandleFunc("/books/search", c.book.Search).Methods(http.MethodPost)
+	router.HandleFunc("/books/{id}", c.book.GetBook).Methods(http.MethodGet)
+	router.HandleFunc("/books/{id}/similar", c.book.Similar).Methods(http.MethodGet)
+	router.HandleFunc("/books/{id}/history", c.book.History).Methods(http.MethodGet)
+	router.HandleFunc("/authors", c.author.Get).Methods(http.MethodGet)
+	router.HandleFunc("/authors/{id}", c.author.GetAuthor).Methods(http.MethodGet)
+	router.HandleFunc("/authors/{id}/books", c.author.GetBooks).Methods(http.MethodGet)
+	router.HandleFunc("/genres", c.genre.Get).Methods(http.MethodGet)
+	router.HandleFunc("/sizes", c.size.Get).Methods(http.MethodGet)
+	router.HandleFunc("/eras", c.era.Get).Methods(http.MethodGet)
+	router.HandleFunc("/series", c.series.Get).Methods(http.MethodGet)
+	router.HandleFunc("/series/{id}/books", c.series.GetBooks).Methods(http.MethodGet)
+	router.HandleFunc("/events", c.event.Post).Methods(http.MethodPost)
+	router.HandleFunc("/me/recommendations", c.recommendation.Get).Methods(http.MethodGet)
+	router.HandleFunc("/me/shelves", c.shelf.GetAll).Methods(http.MethodGet)
+	router.HandleFunc("/me/shelves", c.shelf.Post).Methods(http.MethodPost)
+	router.HandleFunc("/me/shelves/{shelfId}", c.shelf.Get).Methods(http.MethodGet)
+	router.HandleFunc("/me/shelves/{shelfId}", c.shelf.Patch).Methods(http.MethodPatch)
+	router.HandleFunc("/me/shelves/{shelfId}", c.shelf.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/me/shelves/{shelfId}/books", c.shelf.PostBook).Methods(http.MethodPost)
+	router.HandleFunc("/me/shelves/{shelfId}/books/{bookId}", c.shelf.PatchBook).Methods(http.MethodPatch)
+	router.HandleFunc("/me/shelves/{shelfId}/books/{bookId}", c.shelf.DeleteBook).Methods(http.MethodDelete)
+	router.HandleFunc("/me/shelves/{shelfId}/export", c.shelf.Export).Methods(http.MethodGet)
+	router.HandleFunc("/me/shelves/{shelfId}/share", c.shelf.Share).Methods(http.MethodPost)
+	router.HandleFunc("/me/shelves/{shelfId}/share", c.shelf.Unshare).Methods(http.MethodDelete)
+	router.HandleFunc("/shared/shelves/{token}", c.shelf.GetShared).Methods(http.MethodGet)
+	router.HandleFunc("/graphql", c.graphql.Post).Methods(http.MethodPost)
+	router.HandleFunc("/openapi.yaml", c.openAPI.GetSpec).Methods(http.MethodGet)
+	router.HandleFunc("/docs", c.openAPI.GetDocs).Methods(http.MethodGet)
 
-	return cors.AllowAll().Handler(router)
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(withAdminToken(adminToken), withAuditSource(models.AuditAdmin))
+	admin.HandleFunc("/authors/duplicates", c.authorDedupe.Duplicates).Methods(http.MethodGet)
+	admin.HandleFunc("/authors/{id}/merge", c.authorDedupe.Merge).Methods(http.MethodPost)
+	admin.HandleFunc("/data-quality", c.dataQuality.Get).Methods(http.MethodGet)
+	admin.HandleFunc("/books/{id}", c.book.Delete).Methods(http.MethodDelete)
+	admin.HandleFunc("/books/{id}/restore", c.book.Restore).Methods(http.MethodPost)
+	admin.HandleFunc("/sizes", c.size.GetAll).Methods(http.MethodGet)
+	admin.HandleFunc("/sizes", c.size.Post).Methods(http.MethodPost)
+	admin.HandleFunc("/sizes/{id}", c.size.Put).Methods(http.MethodPut)
+	admin.HandleFunc("/sizes/{id}", c.size.Delete).Methods(http.MethodDelete)
+	admin.HandleFunc("/eras", c.era.GetAll).Methods(http.MethodGet)
+	admin.HandleFunc("/eras", c.era.Post).Methods(http.MethodPost)
+	admin.HandleFunc("/eras/{id}", c.era.Put).Methods(http.MethodPut)
+	admin.HandleFunc("/eras/{id}", c.era.Delete).Methods(http.MethodDelete)
+	admin.HandleFunc("/webhooks", c.webhook.GetAll).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks", c.webhook.Post).Methods(http.MethodPost)
+	admin.HandleFunc("/webhooks/{id}", c.webhook.Get).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks/{id}", c.webhook.Put).Methods(http.MethodPut)
+	admin.HandleFunc("/webhooks/{id}", c.webhook.Delete).Methods(http.MethodDelete)
+	admin.HandleFunc("/webhooks/{id}/ping", c.webhook.Ping).Methods(http.MethodPost)
+	admin.HandleFunc("/webhooks/{id}/deliveries", c.webhook.GetDeliveries).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/retry", c.webhook.Retry).Methods(http.MethodPost)
+	admin.HandleFunc("/audit", c.audit.Get).Methods(http.MethodGet)
+	admin.HandleFunc("/audit/export", c.audit.Export).Methods(http.MethodGet)
+
+	v2 := root.PathPrefix("/api/v2").Subrouter()
+	v2.HandleFunc("/books", c.book.GetV2).Methods(http.MethodGet)
+	v2.HandleFunc("/books/search", c.book.SearchV2).Methods(http.MethodPost)
+	v2.HandleFunc("/authors", c.author.GetV2).Methods(http.MethodGet)
+	v2.HandleFunc("/genres", c.genre.GetV2).Methods(http.MethodGet)
+	v2.HandleFunc("/sizes", c.size.GetV2).Methods(http.MethodGet)
+	v2.HandleFunc("/eras", c.era.GetV2).Methods(http.MethodGet)
 }
 
 // generateControllers constructs the needed controller with dependency injected mediators
-func generateControllers() (controllers.BookController, controllers.AuthorController, controllers.GenreController, controllers.SizeController, controllers.EraController) {
-	configValues, err := config.LoadConfig()
+func generateControllers(configValues config.Config, storeAdapter stores.Store, doc *openapi3.T) appControllers {
+	// ------------------------ experiment ------------------------
+	experimentMediatorFactory := func() mediators.ExperimentMediator {
+		storeLog := log.WithField("*store", "Event")
+		eventStore := stores.NewEventStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Experiment")
+		return mediators.NewExperimentMediator(mediatorLog, eventStore, configValues.Experiments)
+	}
+	eventController := controllers.EventController{
+		Logger:                    log.WithField("*controller", "Event"),
+		ExperimentMediatorFactory: experimentMediatorFactory,
+	}
+
+	// ------------------------ book ------------------------
+	bookMediatorFactory := bookMediatorFactory(configValues, storeAdapter)
+	bookController := controllers.BookController{
+		Logger:                    log.WithField("*controller", "Book"),
+		BookMediatorFactory:       bookMediatorFactory,
+		ExperimentMediatorFactory: experimentMediatorFactory,
+	}
+
+	// ------------------------ recommendation ------------------------
+	recommendationController := controllers.RecommendationController{
+		Logger:                        log.WithField("*controller", "Recommendation"),
+		RecommendationMediatorFactory: recommendationMediatorFactory(configValues, storeAdapter),
+	}
+
+	// ------------------------ shelf ------------------------
+	shelfMediatorFactory := func() mediators.ShelfMediator {
+		storeLog := log.WithField("*store", "Shelf")
+		shelfStore := stores.NewShelfStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Shelf")
+		return mediators.NewShelfMediator(mediatorLog, shelfStore)
+	}
+	shelfController := controllers.ShelfController{
+		Logger:               log.WithField("*controller", "Shelf"),
+		ShelfMediatorFactory: shelfMediatorFactory,
+	}
+
+	// ------------------------ author ------------------------
+	authorMediatorFactory := authorMediatorFactory(configValues, storeAdapter)
+	authorController := controllers.AuthorController{
+		Logger:                log.WithField("*controller", "Author"),
+		AuthorMediatorFactory: authorMediatorFactory,
+	}
+
+	// ------------------------ genre ------------------------
+	genrerMediatorFactory := genreMediatorFactory(storeAdapter)
+	genrerController := controllers.GenreController{
+		Logger:               log.WithField("*controller", "Genre"),
+		GenreMediatorFactory: genrerMediatorFactory,
+	}
+
+	// ------------------------ series ------------------------
+	seriesMediatorFactory := func() mediators.SeriesMediator {
+		storeLog := log.WithField("*store", "Series")
+		seriesStore := stores.NewSeriesStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Series")
+		return mediators.NewSeriesMediator(mediatorLog, seriesStore)
+	}
+	seriesController := controllers.SeriesController{
+		Logger:                log.WithField("*controller", "Series"),
+		SeriesMediatorFactory: seriesMediatorFactory,
+	}
+
+	// ------------------------ size ------------------------
+	sizeMediatorFactory := sizeMediatorFactory(storeAdapter)
+	sizeController := controllers.SizeController{
+		Logger:              log.WithField("*controller", "Size"),
+		SizeMediatorFactory: sizeMediatorFactory,
+	}
+
+	// ------------------------ era ------------------------
+	eraMediatorFactory := eraMediatorFactory(storeAdapter)
+	eraController := controllers.EraController{
+		Logger:             log.WithField("*controller", "Era"),
+		EraMediatorFactory: eraMediatorFactory,
+	}
+
+	// ------------------------ webhook ------------------------
+	webhookController := controllers.WebhookController{
+		Logger:                 log.WithField("*controller", "Webhook"),
+		WebhookMediatorFactory: webhookMediatorFactory(configValues, storeAdapter),
+	}
+
+	// ------------------------ audit ------------------------
+	auditController := controllers.AuditController{
+		Logger:               log.WithField("*controller", "Audit"),
+		AuditMediatorFactory: auditMediatorFactory(configValues, storeAdapter),
+	}
+
+	// ------------------------ author dedupe ------------------------
+	authorDedupeController := controllers.AuthorDedupeController{
+		Logger:                      log.WithField("*controller", "AuthorDedupe"),
+		AuthorDedupeMediatorFactory: authorDedupeMediatorFactory(configValues, storeAdapter),
+	}
+
+	// ------------------------ data quality ------------------------
+	dataQualityController := controllers.DataQualityController{
+		Logger:                     log.WithField("*controller", "DataQuality"),
+		DataQualityMediatorFactory: dataQualityMediatorFactory(configValues, storeAdapter),
+	}
+
+	// ------------------------ graphql ------------------------
+	executor, err := graph.NewExecutor(graph.Resolver{
+		Logger:                log.WithField("*resolver", "GraphQL"),
+		BookMediatorFactory:   bookMediatorFactory,
+		AuthorMediatorFactory: authorMediatorFactory,
+		GenreMediatorFactory:  genrerMediatorFactory,
+		SizeMediatorFactory:   sizeMediatorFactory,
+		EraMediatorFactory:    eraMediatorFactory,
+	}, configValues.GraphQL)
 	if err != nil {
-		log.WithField("error", err).Error("error init config application")
+		log.WithField("error", err).Fatal("error building graphql executor")
+	}
+	graphQLController := controllers.GraphQLController{
+		Logger:   log.WithField("*controller", "GraphQL"),
+		Executor: executor,
 	}
 
-	storeAdapter, err := stores.NewStore(configValues.DatabaseURL)
+	// ------------------------ openapi ------------------------
+	docs, err := openapi.Docs(doc)
 	if err != nil {
-		log.WithField("error", err).Error("error initializing database")
+		log.WithField("error", err).Fatal("error rendering openapi docs")
+	}
+	openAPIController := controllers.OpenAPIController{
+		Logger: log.WithField("*controller", "OpenAPI"),
+		Spec:   openapi.Spec,
+		Docs:   docs,
 	}
 
-	// ------------------------ book ------------------------
-	bookMediatorFactory := func() mediators.BookMediator {
+	return appControllers{
+		book:           bookController,
+		author:         authorController,
+		genre:          genrerController,
+		size:           sizeController,
+		era:            eraController,
+		event:          eventController,
+		recommendation: recommendationController,
+		shelf:          shelfController,
+		series:         seriesController,
+		webhook:        webhookController,
+		audit:          auditController,
+		authorDedupe:   authorDedupeController,
+		dataQuality:    dataQualityController,
+		graphql:        graphQLController,
+		openAPI:        openAPIController,
+	}
+}
+
+// bookMediatorFactory and the factories of the authors, genres, sizes and eras mediators are
+// shared by the controllers, the GraphQL resolvers and the gRPC server
+func bookMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.BookMediator {
+	return func() mediators.BookMediator {
 		storeLog := log.WithField("*store", "Book")
 		bookStore := stores.NewBookStore(storeLog, storeAdapter.GetDB())
 		mediatorLog := log.WithField("*mediator", "Book")
-		return mediators.NewBookMediator(mediatorLog, bookStore)
-	}
-	bookController := controllers.BookController{
-		Logger:              log.WithField("*controller", "Book"),
-		BookMediatorFactory: bookMediatorFactory,
+		return mediators.NewBookMediator(mediatorLog, bookStore, configValues.Similarity, configValues.Ranker)
 	}
+}
 
-	// ------------------------ author ------------------------
-	authorMediatorFactory := func() mediators.AuthorMediator {
+func authorMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.AuthorMediator {
+	bookMediatorFactory := bookMediatorFactory(configValues, storeAdapter)
+	return func() mediators.AuthorMediator {
 		storeLog := log.WithField("*store", "Author")
 		authorStore := stores.NewAuthorStore(storeLog, storeAdapter.GetDB())
 		mediatorLog := log.WithField("*mediator", "Author")
-		return mediators.NewAuthorMediator(mediatorLog, authorStore)
-	}
-	authorController := controllers.AuthorController{
-		Logger:                log.WithField("*controller", "Author"),
-		AuthorMediatorFactory: authorMediatorFactory,
+		return mediators.NewAuthorMediator(mediatorLog, authorStore, bookMediatorFactory())
 	}
+}
 
-	// ------------------------ genre ------------------------
-	genrerMediatorFactory := func() mediators.GenreMediator {
+func genreMediatorFactory(storeAdapter stores.Store) func() mediators.GenreMediator {
+	return func() mediators.GenreMediator {
 		storeLog := log.WithField("*store", "Genre")
 		genreStore := stores.NewGenreStore(storeLog, storeAdapter.GetDB())
 		mediatorLog := log.WithField("*mediator", "Genre")
 		return mediators.NewGenreMediator(mediatorLog, genreStore)
 	}
-	genrerController := controllers.GenreController{
-		Logger:               log.WithField("*controller", "Genre"),
-		GenreMediatorFactory: genrerMediatorFactory,
-	}
+}
 
-	// ------------------------ size ------------------------
-	sizeMediatorFactory := func() mediators.SizeMediator {
+func sizeMediatorFactory(storeAdapter stores.Store) func() mediators.SizeMediator {
+	return func() mediators.SizeMediator {
 		storeLog := log.WithField("*store", "Size")
 		sizeStore := stores.NewSizeStore(storeLog, storeAdapter.GetDB())
 		mediatorLog := log.WithField("*mediator", "Size")
 		return mediators.NewSizeMediator(mediatorLog, sizeStore)
 	}
-	sizeController := controllers.SizeController{
-		Logger:              log.WithField("*controller", "Size"),
-		SizeMediatorFactory: sizeMediatorFactory,
-	}
+}
 
-	// ------------------------ era ------------------------
-	eraMediatorFactory := func() mediators.EraMediator {
+func eraMediatorFactory(storeAdapter stores.Store) func() mediators.EraMediator {
+	return func() mediators.EraMediator {
 		storeLog := log.WithField("*store", "Era")
 		eraStore := stores.NewEraStore(storeLog, storeAdapter.GetDB())
 		mediatorLog := log.WithField("*mediator", "Era")
 		return mediators.NewEraMediator(mediatorLog, eraStore)
 	}
-	eraController := controllers.EraController{
-		Logger:             log.WithField("*controller", "Era"),
-		EraMediatorFactory: eraMediatorFactory,
+}
+
+// webhookMediatorFactory is shared by the webhook controller and the webhook dispatcher job.
+// The receivers have to answer the deliveries themselves, their redirects are not followed.
+func webhookMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.WebhookMediator {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return func() mediators.WebhookMediator {
+		storeLog := log.WithField("*store", "Webhook")
+		webhookStore := stores.NewWebhookStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Webhook")
+		return mediators.NewWebhookMediator(mediatorLog, webhookStore, client, configValues.Webhook)
 	}
+}
+
+// auditMediatorFactory is shared by the audit controller and the audit retention job
+func auditMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.AuditMediator {
+	return func() mediators.AuditMediator {
+		storeLog := log.WithField("*store", "Audit")
+		auditStore := stores.NewAuditStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Audit")
+		return mediators.NewAuditMediator(mediatorLog, auditStore, configValues.Audit)
+	}
+}
 
-	return bookController, authorController, genrerController, sizeController, eraController
+// authorDedupeMediatorFactory is shared by the author dedupe controller and the dedupe-authors command
+func authorDedupeMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.AuthorDedupeMediator {
+	return func() mediators.AuthorDedupeMediator {
+		storeLog := log.WithField("*store", "Author")
+		authorStore := stores.NewAuthorStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "AuthorDedupe")
+		return mediators.NewAuthorDedupeMediator(mediatorLog, authorStore, configValues.Dedupe)
+	}
+}
+
+// dataQualityMediatorFactory is shared by the data quality controller and the check-data command
+func dataQualityMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.DataQualityMediator {
+	return func() mediators.DataQualityMediator {
+		storeLog := log.WithField("*store", "DataQuality")
+		dataQualityStore := stores.NewDataQualityStore(storeLog, storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "DataQuality")
+		return mediators.NewDataQualityMediator(mediatorLog, dataQualityStore, configValues.DataQuality)
+	}
+}
+
+// recommendationMediatorFactory is shared by the recommendation controller and the similarity job
+func recommendationMediatorFactory(configValues config.Config, storeAdapter stores.Store) func() mediators.RecommendationMediator {
+	return func() mediators.RecommendationMediator {
+		bookStore := stores.NewBookStore(log.WithField("*store", "Book"), storeAdapter.GetDB())
+		ratingStore := stores.NewRatingStore(log.WithField("*store", "Rating"), storeAdapter.GetDB())
+		mediatorLog := log.WithField("*mediator", "Recommendation")
+		return mediators.NewRecommendationMediator(mediatorLog, bookStore, ratingStore, configValues.Recommendation, configValues.Ranker.PoolSize)
+	}
 }
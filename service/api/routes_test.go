@@ -0,0 +1,339 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/openapi"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutes_MatchSpec(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	registerRoutes(router, appControllers{}, "")
+
+	var registered []string
+	err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// the path prefixes of the subrouters have no method
+			return nil
+		}
+		for _, method := range methods {
+			registered = append(registered, method+" "+strings.TrimPrefix(path, "/api"))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	var specified []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			specified = append(specified, method+" "+path)
+		}
+	}
+
+	sort.Strings(registered)
+	sort.Strings(specified)
+	assert.Equal(t, specified, registered, "the routes of api/routes.go and openapi/open-api.yaml differ")
+}
+
+func TestWithContract_Requests(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	contract, err := openapi.NewRouter(doc)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		header map[string]string
+		assert func(t *testing.T, res *httptest.ResponseRecorder, reached bool)
+	}{
+		{
+			name:   "valid request",
+			method: http.MethodGet,
+			url:    "/api/v1/books?limit=5&genres=1,2",
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				assert.True(t, reached)
+				assert.Equal(t, http.StatusOK, res.Code)
+			},
+		},
+		{
+			name:   "invalid query parameters",
+			method: http.MethodGet,
+			url:    "/api/v1/books?limit=0",
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				problem := decodeProblem(t, res, reached)
+				assert.Equal(t, "invalid query parameters", problem.Detail)
+				assert.Contains(t, problem.Errors, "limit")
+			},
+		},
+		{
+			name:   "invalid path parameters",
+			method: http.MethodGet,
+			url:    "/api/v1/books/0/similar",
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				problem := decodeProblem(t, res, reached)
+				assert.Equal(t, "invalid path parameters", problem.Detail)
+				assert.Contains(t, problem.Errors, "id")
+			},
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPost,
+			url:    "/api/v1/events",
+			body:   `{"type":"view","experiment":"e","variant":"v","bookId":1,"extra":true}`,
+			header: map[string]string{"Content-Type": "application/json", "X-User-ID": "1"},
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				problem := decodeProblem(t, res, reached)
+				assert.Equal(t, "invalid request body", problem.Detail)
+				assert.Contains(t, problem.Errors, "type")
+				assert.Equal(t, []string{"is not a known field"}, problem.Errors["extra"])
+			},
+		},
+		{
+			name:   "admin token left to the admin routes",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/eras",
+			body:   `{"title":"Contemporary (2000+)","minYear":2000}`,
+			header: map[string]string{"Content-Type": "application/json"},
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				assert.True(t, reached)
+			},
+		},
+		{
+			name:   "missing user ID left to the controller",
+			method: http.MethodGet,
+			url:    "/api/v1/me/shelves",
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				assert.True(t, reached)
+			},
+		},
+		{
+			name:   "route out of the spec left to the router",
+			method: http.MethodGet,
+			url:    "/api/v1/unknown",
+			assert: func(t *testing.T, res *httptest.ResponseRecorder, reached bool) {
+				assert.True(t, reached)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			res := httptest.NewRecorder()
+			withContract(contract, false, next).ServeHTTP(res, req)
+
+			tc.assert(t, res, reached)
+		})
+	}
+}
+
+func TestWithContract_Responses(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	contract, err := openapi.NewRouter(doc)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		url         string
+		contentType string
+		status      int
+		body        string
+		assert      func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "response matching the spec",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `[{"id":1,"firstName":"Abraham","lastName":"Stackhouse"}]`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+				assert.JSONEq(t, `[{"id":1,"firstName":"Abraham","lastName":"Stackhouse"}]`, res.Body.String())
+			},
+		},
+		{
+			name:        "body breaking the spec",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `[{"id":"1","name":"Abraham Stackhouse"}]`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+		{
+			name:        "status out of the spec",
+			contentType: "application/json",
+			status:      http.StatusCreated,
+			body:        `[]`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+		{
+			name:        "envelope matching the spec",
+			url:         "/api/v2/books?fields=title&limit=1",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `{"data":[{"id":1,"title":"Alanna Saves the Day"}],"meta":{"total":3,"limit":1,"offset":0},"links":{"self":"/api/v2/books?fields=title&limit=1"}}`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+			},
+		},
+		{
+			name:        "envelope breaking the spec",
+			url:         "/api/v2/books",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `[{"id":1,"title":"Alanna Saves the Day"}]`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+		{
+			name:        "server error left as is",
+			contentType: "application/problem+json",
+			status:      http.StatusServiceUnavailable,
+			body:        `{}`,
+			assert: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			})
+
+			url := tc.url
+			if url == "" {
+				url = "/api/v1/authors"
+			}
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			res := httptest.NewRecorder()
+			withContract(contract, true, next).ServeHTTP(res, req)
+
+			tc.assert(t, res)
+		})
+	}
+}
+
+func TestWithAdminToken(t *testing.T) {
+	cases := []struct {
+		name          string
+		token         string
+		authorization string
+		reached       bool
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", reached: true},
+		{name: "wrong token", token: "secret", authorization: "Bearer guess"},
+		{name: "missing token", token: "secret"},
+		{name: "token of another scheme", token: "secret", authorization: "Basic secret"},
+		{name: "no token configured", authorization: "Bearer "},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/sizes", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			res := httptest.NewRecorder()
+			withAdminToken(tc.token)(next).ServeHTTP(res, req)
+
+			assert.Equal(t, tc.reached, reached)
+			if !tc.reached {
+				assert.Equal(t, http.StatusUnauthorized, res.Code)
+			}
+		})
+	}
+}
+
+func TestWithUserSignature(t *testing.T) {
+	cases := []struct {
+		name      string
+		secret    string
+		userID    string
+		signature string
+		reached   bool
+	}{
+		{name: "signed user", secret: "secret", userID: "42", signature: translators.SignUserID("secret", "42"), reached: true},
+		{name: "anonymous", secret: "secret", reached: true},
+		{name: "unsigned user", secret: "secret", userID: "42"},
+		{name: "signature of another user", secret: "secret", userID: "42", signature: translators.SignUserID("secret", "7")},
+		{name: "signature of another secret", secret: "secret", userID: "42", signature: translators.SignUserID("guess", "42")},
+		{name: "no secret configured", userID: "42", signature: translators.SignUserID("", "42")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/shelves", nil)
+			if tc.userID != "" {
+				req.Header.Set(translators.UserIDHeader, tc.userID)
+				req.Header.Set(translators.UserSignatureHeader, tc.signature)
+			}
+			res := httptest.NewRecorder()
+			withUserSignature(tc.secret, next).ServeHTTP(res, req)
+
+			assert.Equal(t, tc.reached, reached)
+			if !tc.reached {
+				assert.Equal(t, http.StatusUnauthorized, res.Code)
+			}
+		})
+	}
+}
+
+// decodeProblem checks that the request was answered with a bad request problem and
+// returns it
+func decodeProblem(t *testing.T, res *httptest.ResponseRecorder, reached bool) models.Problem {
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+
+	var problem models.Problem
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+	return problem
+}
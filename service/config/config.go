@@ -2,13 +2,129 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/book-recommendations/service/models"
 )
 
 type Config struct {
 	HTTPPort    string
+	GRPCPort    string
 	DatabaseURL string
+	// RequestTimeout cancels the work of the requests lasting longer, never when zero
+	RequestTimeout time.Duration
+	// CheckResponses validates the responses against the OpenAPI specification, for the tests
+	CheckResponses bool
+	// AdminToken is the bearer token of the admin routes, which are closed when it is empty
+	AdminToken string
+	// UserIDSecret is the secret the gateway signs the user ID headers with, no user is
+	// authenticated when it is empty
+	UserIDSecret   string
+	Ranking        RankingConfig
+	Similarity     SimilarityConfig
+	Ranker         RankerConfig
+	Experiments    []models.Experiment
+	Recommendation RecommendationConfig
+	GraphQL        GraphQLConfig
+	Webhook        WebhookConfig
+	Audit          AuditConfig
+	Dedupe         DedupeConfig
+	DataQuality    DataQualityConfig
+}
+
+// RankingConfig holds the prior used to compute the confidence-weighted rating
+// of the books: a book with few ratings is pulled towards PriorMean as if it had
+// PriorWeight extra ratings of that value.
+type RankingConfig struct {
+	PriorMean   float64
+	PriorWeight int64
+}
+
+// SimilarityConfig holds the weights of each criterion of the content-similarity score
+// between two books. The year and pages criteria decrease linearly with the distance
+// between both books and reach zero at YearWindow and PagesWindow respectively. The
+// candidates scored are the Candidates closest books of each criterion.
+type SimilarityConfig struct {
+	GenreWeight  float64
+	AuthorWeight float64
+	YearWeight   float64
+	PagesWeight  float64
+	YearWindow   int64
+	PagesWindow  int64
+	Candidates   int64
+}
+
+// Rankers are the names of the rankers of /books
+var Rankers = []string{"rating", "weighted", "recency", "random"}
+
+// RankerConfig holds the settings of the rankers of /books. The store fetches PoolFactor times
+// the books asked, up to PoolSize, as the candidates the ranker reorders before the limit of
+// the request applies. The recency ranker boosts the books by up to RecencyBoost stars, fading
+// away for the books older than RecencyWindow years. Default names one of Rankers.
+type RankerConfig struct {
+	Default       string
+	PoolSize      int64
+	PoolFactor    int64
+	RecencyBoost  float64
+	RecencyWindow int64
+}
+
+// RecommendationConfig holds the settings of the collaborative filtering. The similarity
+// between books is refreshed every RefreshInterval (never when zero), keeping for each book
+// its Neighbours most similar books rated by at least MinCoRaters common users. Users with
+// fewer than MinRatings ratings get recommendations based on their preferences instead.
+type RecommendationConfig struct {
+	RefreshInterval time.Duration
+	MinRatings      int64
+	Neighbours      int64
+	MinCoRaters     int64
+}
+
+// GraphQLConfig holds the limits of the queries of /graphql. The depth is the deepest
+// nesting of fields, the complexity the number of fields to resolve, the fields under a
+// field with a limit counting once per item.
+type GraphQLConfig struct {
+	MaxDepth      int64
+	MaxComplexity int64
+}
+
+// WebhookConfig holds the settings of the dispatcher of the webhooks. Every DispatchInterval
+// (never when zero), it delivers up to BatchSize events at once, each attempt lasting at most
+// Timeout. A failed delivery is attempted again after BackoffBase, doubling at every attempt
+// up to BackoffMax, and is dead after MaxAttempts attempts.
+type WebhookConfig struct {
+	DispatchInterval time.Duration
+	Timeout          time.Duration
+	MaxAttempts      int64
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	BatchSize        int64
+}
+
+// AuditConfig holds the retention of the audit log: every PruneInterval (never when zero),
+// the entries older than Retention are deleted, unless it is zero and they are kept forever.
+type AuditConfig struct {
+	Retention     time.Duration
+	PruneInterval time.Duration
+}
+
+// DedupeConfig holds the trigram similarity, from 0 to 1, from which the full names of two
+// authors make them candidate duplicates, unless a request asks for another one
+type DedupeConfig struct {
+	MinSimilarity float64
+}
+
+// DataQualityConfig holds the rules of the data-quality checks run unless a request names
+// others, and the number of rows breaking a rule reported along with their count
+type DataQualityConfig struct {
+	Rules   []string
+	MaxRows int64
 }
 
 type postgresConfig struct {
@@ -20,11 +136,60 @@ type postgresConfig struct {
 	SSL      bool   `json:"ssl"`
 }
 
+const (
+	defaultPriorMean   = 3.0
+	defaultPriorWeight = 10
+
+	defaultGenreWeight  = 3.0
+	defaultAuthorWeight = 2.0
+	defaultYearWeight   = 1.0
+	defaultPagesWeight  = 1.0
+	defaultYearWindow   = 20
+	defaultPagesWindow  = 200
+	defaultCandidates   = 250
+
+	defaultRanker        = "weighted"
+	defaultPoolSize      = 1000
+	defaultPoolFactor    = 3
+	defaultRecencyBoost  = 1.0
+	defaultRecencyWindow = 10
+
+	defaultExperimentsFile = "config/experiments.json"
+
+	defaultRequestTimeout = 5 * time.Second
+
+	defaultGraphQLMaxDepth      = 8
+	defaultGraphQLMaxComplexity = 5000
+
+	defaultDispatchInterval = 5 * time.Second
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultMaxAttempts      = 8
+	defaultBackoffBase      = 30 * time.Second
+	defaultBackoffMax       = 6 * time.Hour
+	defaultWebhookBatchSize = 20
+
+	defaultAuditRetention     = 365 * 24 * time.Hour
+	defaultAuditPruneInterval = 24 * time.Hour
+
+	defaultDedupeMinSimilarity = 0.6
+
+	defaultDataQualityMaxRows = 20
+
+	defaultRefreshInterval = time.Hour
+	defaultMinRatings      = 5
+	defaultNeighbours      = 20
+	defaultMinCoRaters     = 2
+)
+
 func LoadConfig() (Config, error) {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "5001"
 	}
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "5002"
+	}
 
 	databaseURL := os.Getenv("DB_URL")
 	if databaseURL == "" {
@@ -51,8 +216,363 @@ func LoadConfig() (Config, error) {
 		)
 	}
 
+	requestTimeout, err := getEnvDuration("REQUEST_TIMEOUT", defaultRequestTimeout)
+	if err != nil {
+		return Config{}, err
+	}
+
+	priorMean, err := getEnvFloat("RANKING_PRIOR_MEAN", defaultPriorMean)
+	if err != nil {
+		return Config{}, err
+	}
+	priorWeight, err := getEnvInt("RANKING_PRIOR_WEIGHT", defaultPriorWeight)
+	if err != nil {
+		return Config{}, err
+	}
+
+	similarity, err := loadSimilarityConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	ranker, err := loadRankerConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	experiments, err := loadExperiments()
+	if err != nil {
+		return Config{}, err
+	}
+
+	recommendation, err := loadRecommendationConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	checkResponses, err := getEnvBool("OPENAPI_CHECK_RESPONSES", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	userIDSecret := os.Getenv("USER_ID_SECRET")
+
+	webhook, err := loadWebhookConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	audit, err := loadAuditConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	dedupeMinSimilarity, err := getEnvFloat("DEDUPE_MIN_SIMILARITY", defaultDedupeMinSimilarity)
+	if err != nil {
+		return Config{}, err
+	}
+	if dedupeMinSimilarity < 0 || dedupeMinSimilarity > 1 {
+		return Config{}, errors.New("invalid DEDUPE_MIN_SIMILARITY: should be between 0 and 1")
+	}
+
+	dataQuality, err := loadDataQualityConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	graphQL, err := loadGraphQLConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
-		HTTPPort:    port,
-		DatabaseURL: databaseURL,
+		HTTPPort:       port,
+		GRPCPort:       grpcPort,
+		DatabaseURL:    databaseURL,
+		RequestTimeout: requestTimeout,
+		CheckResponses: checkResponses,
+		AdminToken:     adminToken,
+		UserIDSecret:   userIDSecret,
+		Ranking: RankingConfig{
+			PriorMean:   priorMean,
+			PriorWeight: priorWeight,
+		},
+		Similarity:     similarity,
+		Ranker:         ranker,
+		Experiments:    experiments,
+		Recommendation: recommendation,
+		GraphQL:        graphQL,
+		Webhook:        webhook,
+		Audit:          audit,
+		Dedupe:         DedupeConfig{MinSimilarity: dedupeMinSimilarity},
+		DataQuality:    dataQuality,
 	}, nil
 }
+
+func loadSimilarityConfig() (SimilarityConfig, error) {
+	var (
+		cfg SimilarityConfig
+		err error
+	)
+	if cfg.GenreWeight, err = getEnvFloat("SIMILARITY_GENRE_WEIGHT", defaultGenreWeight); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.AuthorWeight, err = getEnvFloat("SIMILARITY_AUTHOR_WEIGHT", defaultAuthorWeight); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.YearWeight, err = getEnvFloat("SIMILARITY_YEAR_WEIGHT", defaultYearWeight); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.PagesWeight, err = getEnvFloat("SIMILARITY_PAGES_WEIGHT", defaultPagesWeight); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.YearWindow, err = getEnvInt("SIMILARITY_YEAR_WINDOW", defaultYearWindow); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.PagesWindow, err = getEnvInt("SIMILARITY_PAGES_WINDOW", defaultPagesWindow); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.Candidates, err = getEnvInt("SIMILARITY_CANDIDATES", defaultCandidates); err != nil {
+		return SimilarityConfig{}, err
+	}
+	if cfg.Candidates < 1 {
+		return SimilarityConfig{}, errors.New("invalid SIMILARITY_CANDIDATES: should be positive")
+	}
+
+	return cfg, nil
+}
+
+func loadRankerConfig() (RankerConfig, error) {
+	var (
+		cfg RankerConfig
+		err error
+	)
+	cfg.Default = os.Getenv("RANKER_DEFAULT")
+	if cfg.Default == "" {
+		cfg.Default = defaultRanker
+	}
+	if !slices.Contains(Rankers, cfg.Default) {
+		return RankerConfig{}, fmt.Errorf("invalid RANKER_DEFAULT: unknown ranker %q", cfg.Default)
+	}
+	if cfg.PoolSize, err = getEnvInt("RANKER_POOL_SIZE", defaultPoolSize); err != nil {
+		return RankerConfig{}, err
+	}
+	if cfg.PoolFactor, err = getEnvInt("RANKER_POOL_FACTOR", defaultPoolFactor); err != nil {
+		return RankerConfig{}, err
+	}
+	if cfg.PoolFactor < 1 {
+		return RankerConfig{}, errors.New("invalid RANKER_POOL_FACTOR: should be positive")
+	}
+	if cfg.RecencyBoost, err = getEnvFloat("RANKER_RECENCY_BOOST", defaultRecencyBoost); err != nil {
+		return RankerConfig{}, err
+	}
+	if cfg.RecencyWindow, err = getEnvInt("RANKER_RECENCY_WINDOW", defaultRecencyWindow); err != nil {
+		return RankerConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+func loadRecommendationConfig() (RecommendationConfig, error) {
+	var (
+		cfg RecommendationConfig
+		err error
+	)
+	if cfg.RefreshInterval, err = getEnvDuration("CF_REFRESH_INTERVAL", defaultRefreshInterval); err != nil {
+		return RecommendationConfig{}, err
+	}
+	if cfg.MinRatings, err = getEnvInt("CF_MIN_RATINGS", defaultMinRatings); err != nil {
+		return RecommendationConfig{}, err
+	}
+	if cfg.Neighbours, err = getEnvInt("CF_NEIGHBOURS", defaultNeighbours); err != nil {
+		return RecommendationConfig{}, err
+	}
+	if cfg.MinCoRaters, err = getEnvInt("CF_MIN_CO_RATERS", defaultMinCoRaters); err != nil {
+		return RecommendationConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+func loadGraphQLConfig() (GraphQLConfig, error) {
+	var (
+		cfg GraphQLConfig
+		err error
+	)
+	if cfg.MaxDepth, err = getEnvInt("GRAPHQL_MAX_DEPTH", defaultGraphQLMaxDepth); err != nil {
+		return GraphQLConfig{}, err
+	}
+	if cfg.MaxComplexity, err = getEnvInt("GRAPHQL_MAX_COMPLEXITY", defaultGraphQLMaxComplexity); err != nil {
+		return GraphQLConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+func loadWebhookConfig() (WebhookConfig, error) {
+	var (
+		cfg WebhookConfig
+		err error
+	)
+	if cfg.DispatchInterval, err = getEnvDuration("WEBHOOK_DISPATCH_INTERVAL", defaultDispatchInterval); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.Timeout, err = getEnvDuration("WEBHOOK_TIMEOUT", defaultWebhookTimeout); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.MaxAttempts, err = getEnvInt("WEBHOOK_MAX_ATTEMPTS", defaultMaxAttempts); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.BackoffBase, err = getEnvDuration("WEBHOOK_BACKOFF_BASE", defaultBackoffBase); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.BackoffMax, err = getEnvDuration("WEBHOOK_BACKOFF_MAX", defaultBackoffMax); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.BatchSize, err = getEnvInt("WEBHOOK_BATCH_SIZE", defaultWebhookBatchSize); err != nil {
+		return WebhookConfig{}, err
+	}
+	if cfg.MaxAttempts < 1 || cfg.BatchSize < 1 {
+		return WebhookConfig{}, errors.New("invalid WEBHOOK_MAX_ATTEMPTS or WEBHOOK_BATCH_SIZE: should be positive")
+	}
+
+	return cfg, nil
+}
+
+func loadAuditConfig() (AuditConfig, error) {
+	var (
+		cfg AuditConfig
+		err error
+	)
+	if cfg.Retention, err = getEnvDuration("AUDIT_RETENTION", defaultAuditRetention); err != nil {
+		return AuditConfig{}, err
+	}
+	if cfg.PruneInterval, err = getEnvDuration("AUDIT_PRUNE_INTERVAL", defaultAuditPruneInterval); err != nil {
+		return AuditConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadDataQualityConfig reads the comma-delimited rules of DATA_QUALITY_RULES, every rule
+// when it is unset
+func loadDataQualityConfig() (DataQualityConfig, error) {
+	var (
+		cfg DataQualityConfig
+		err error
+	)
+	cfg.Rules = models.ParseDataQualityRules(os.Getenv("DATA_QUALITY_RULES"))
+	if cfg.Rules == nil {
+		cfg.Rules = models.DataQualityRules
+	}
+	for _, rule := range cfg.Rules {
+		if !slices.Contains(models.DataQualityRules, rule) {
+			return DataQualityConfig{}, fmt.Errorf("invalid DATA_QUALITY_RULES: unknown rule %q", rule)
+		}
+	}
+	if cfg.MaxRows, err = getEnvInt("DATA_QUALITY_MAX_ROWS", defaultDataQualityMaxRows); err != nil {
+		return DataQualityConfig{}, err
+	}
+	if cfg.MaxRows < 1 {
+		return DataQualityConfig{}, errors.New("invalid DATA_QUALITY_MAX_ROWS: should be positive")
+	}
+
+	return cfg, nil
+}
+
+// loadExperiments reads the running experiments from the file given by EXPERIMENTS_FILE,
+// there are no experiments when the file does not exist
+func loadExperiments() ([]models.Experiment, error) {
+	path := os.Getenv("EXPERIMENTS_FILE")
+	if path == "" {
+		path = defaultExperimentsFile
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var experiments []models.Experiment
+	if err := json.Unmarshal(content, &experiments); err != nil {
+		return nil, fmt.Errorf("invalid experiments file %s: %w", path, err)
+	}
+	// every experiment picks the ranker of its subjects, and the subjects are hashed into each
+	// one independently, so only one of them can run at a time
+	names := make(map[string]bool, len(experiments))
+	running := ""
+	for _, experiment := range experiments {
+		if err := experiment.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid experiment %q: %w", experiment.Name, err)
+		}
+		if names[experiment.Name] {
+			return nil, fmt.Errorf("invalid experiment %q: duplicated name", experiment.Name)
+		}
+		names[experiment.Name] = true
+		if !experiment.Running() {
+			continue
+		}
+		if running != "" {
+			return nil, fmt.Errorf("invalid experiment %q: overlaps experiment %q, only one ranker experiment can run at a time", experiment.Name, running)
+		}
+		running = experiment.Name
+	}
+
+	return experiments, nil
+}
+
+// getEnvFloat reads a float from the environment, falling back to defaultValue when unset
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvInt reads an integer from the environment, falling back to defaultValue when unset
+func getEnvInt(key string, defaultValue int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvBool reads a boolean such as "true" from the environment, falling back to defaultValue when unset
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvDuration reads a duration such as "30m" from the environment, falling back to defaultValue when unset
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
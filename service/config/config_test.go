@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExperiments(t *testing.T) {
+	var cases = []struct {
+		name    string
+		content string
+		assert  func(err error)
+	}{
+		{
+			name: "success - paused experiment",
+			content: `[{"name": "a", "variants": [{"name": "control", "ranker": "weighted", "traffic": 50}]},
+				{"name": "b", "variants": [{"name": "control", "ranker": "recency", "traffic": 0}]}]`,
+			assert: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "failure - overlapping experiments",
+			content: `[{"name": "a", "variants": [{"name": "control", "ranker": "weighted", "traffic": 50}]},
+				{"name": "b", "variants": [{"name": "control", "ranker": "recency", "traffic": 10}]}]`,
+			assert: func(err error) {
+				assert.ErrorContains(t, err, `overlaps experiment "a"`)
+			},
+		},
+		{
+			name: "failure - duplicated variant",
+			content: `[{"name": "a", "variants": [{"name": "control", "ranker": "weighted", "traffic": 50},
+				{"name": "control", "ranker": "recency", "traffic": 50}]}]`,
+			assert: func(err error) {
+				assert.ErrorContains(t, err, `variant "control" is duplicated`)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "experiments.json")
+			if err := os.WriteFile(path, []byte(c.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("EXPERIMENTS_FILE", path)
+			_, err := loadExperiments()
+			c.assert(err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuditController defines the controller for the audit log of the catalog writes
+type AuditController struct {
+	Logger               *log.Entry
+	AuditMediatorFactory func() mediators.AuditMediator
+}
+
+// Get retrieves the latest entries of the audit log matching the request
+func (c *AuditController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, ok := c.toAuditRequest(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := c.AuditMediatorFactory().Get(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// Export streams every entry of the audit log matching the request as newline-delimited JSON.
+// The errors happening once the first entry is written can only end the response early.
+func (c *AuditController) Export(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, ok := c.toAuditRequest(w, r)
+	if !ok {
+		return
+	}
+
+	written := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+		w.WriteHeader(http.StatusOK)
+		written = true
+	}
+	encoder := json.NewEncoder(w)
+	err := c.AuditMediatorFactory().Export(r.Context(), req, func(entry models.AuditEntry) error {
+		if !written {
+			start()
+		}
+		return encoder.Encode(entry)
+	})
+	switch {
+	case err != nil && written:
+		c.Logger.WithError(err).Error("error exporting audit log")
+	case err != nil:
+		writeError(c.Logger, w, err)
+	case !written:
+		start()
+	}
+}
+
+// toAuditRequest returns the validated parameters of the request, answering the request
+// itself when they are invalid
+func (c *AuditController) toAuditRequest(w http.ResponseWriter, r *http.Request) (models.AuditRequest, bool) {
+	req := translators.ToAuditRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithError(err).Error("invalid request params for audit")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return models.AuditRequest{}, false
+	}
+	return req, true
+}
@@ -0,0 +1,165 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditMediatorMock struct {
+	EntryField   []models.AuditEntry
+	RequestField *models.AuditRequest
+	ErrorField   error
+}
+
+func (m *AuditMediatorMock) Get(ctx context.Context, req models.AuditRequest) ([]models.AuditEntry, error) {
+	m.RequestField = &req
+	return m.EntryField, m.ErrorField
+}
+
+func (m *AuditMediatorMock) Export(ctx context.Context, req models.AuditRequest, fn func(models.AuditEntry) error) error {
+	m.RequestField = &req
+	for _, entry := range m.EntryField {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return m.ErrorField
+}
+
+func (m *AuditMediatorMock) Prune(ctx context.Context) error {
+	return m.ErrorField
+}
+
+func TestAuditController_Get(t *testing.T) {
+	var cases = []struct {
+		name     string
+		url      string
+		mediator *AuditMediatorMock
+		assert   func(resp *http.Response, body string, mediator *AuditMediatorMock)
+	}{
+		{
+			name: "entity history",
+			url:  "http://test.com/api/v1/admin/audit?entity=size&id=3",
+			mediator: &AuditMediatorMock{EntryField: []models.AuditEntry{
+				{ID: 918, Actor: "user:42", Action: models.AuditUpdate, Entity: models.AuditSize, EntityID: 3, Diff: []byte(`{"active":{"before":true,"after":false}}`)},
+			}},
+			assert: func(resp *http.Response, body string, mediator *AuditMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "3", mediator.RequestField.ID)
+				assert.Contains(t, body, `"diff":{"active":{"before":true,"after":false}}`)
+				assert.NotContains(t, body, `"before":null`, "a missing entity is left out")
+			},
+		},
+		{
+			name:     "id without entity",
+			url:      "http://test.com/api/v1/admin/audit?id=3",
+			mediator: &AuditMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *AuditMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "needs the entity")
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "invalid since",
+			url:      "http://test.com/api/v1/admin/audit?since=yesterday",
+			mediator: &AuditMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *AuditMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be an RFC 3339 timestamp")
+			},
+		},
+		{
+			name:     "failure",
+			url:      "http://test.com/api/v1/admin/audit",
+			mediator: &AuditMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(resp *http.Response, body string, mediator *AuditMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.AuditController{
+				Logger:               log.NewEntry(log.New()),
+				AuditMediatorFactory: func() mediators.AuditMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			controller.Get(recorder, httptest.NewRequest(http.MethodGet, c.url, nil))
+
+			resp := recorder.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err, "should return a readable response body")
+			c.assert(resp, string(body), c.mediator)
+		})
+	}
+}
+
+func TestAuditController_Export(t *testing.T) {
+	var cases = []struct {
+		name     string
+		mediator *AuditMediatorMock
+		assert   func(resp *http.Response, body string)
+	}{
+		{
+			name: "one entry per line",
+			mediator: &AuditMediatorMock{EntryField: []models.AuditEntry{
+				{ID: 2, Diff: []byte(`{}`)},
+				{ID: 1, Diff: []byte(`{}`)},
+			}},
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+				lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+				require.Len(t, lines, 2)
+				assert.True(t, strings.HasPrefix(lines[0], `{"id":2,`))
+				assert.True(t, strings.HasPrefix(lines[1], `{"id":1,`))
+			},
+		},
+		{
+			name:     "nothing to export",
+			mediator: &AuditMediatorMock{},
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+				assert.Empty(t, body)
+			},
+		},
+		{
+			name:     "failure before the first entry",
+			mediator: &AuditMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.AuditController{
+				Logger:               log.NewEntry(log.New()),
+				AuditMediatorFactory: func() mediators.AuditMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			controller.Export(recorder, httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/admin/audit/export?entity=era", nil))
+
+			resp := recorder.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err, "should return a readable response body")
+			c.assert(resp, string(body))
+		})
+	}
+}
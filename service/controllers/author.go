@@ -1,12 +1,12 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -21,13 +21,86 @@ func (c *AuthorController) Get(w http.ResponseWriter, r *http.Request) {
 	c.Logger.WithField("url", r.URL).Info("request")
 
 	authorMediator := c.AuthorMediatorFactory()
-	authors, err := authorMediator.Get(context.Background())
+	authors, err := authorMediator.Get(r.Context())
 	if err != nil {
-		c.Logger.WithError(err).Error("internal server error")
-		translators.ParseError(w, http.StatusInternalServerError)
+		writeError(c.Logger, w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(authors)
 }
+
+// GetAuthor retrieves an author along with the figures of their books
+func (c *AuthorController) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToAuthorRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("id", req.ID).WithError(err).Error("invalid request params for get author")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	authorMediator := c.AuthorMediatorFactory()
+	author, err := authorMediator.GetAuthor(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(author)
+}
+
+// GetBooks retrieves the books of an author, filtered and ranked as the list of books
+func (c *AuthorController) GetBooks(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToAuthorBooksRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("id", req.ID).WithField("query", r.URL.RawQuery).WithError(err).Error("invalid request params for get author books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+	if req.Books.ExcludeRead == "true" {
+		userID, ok := translators.ToUserID(r)
+		if !ok {
+			translators.ParseError(w, http.StatusUnauthorized)
+			return
+		}
+		req.Books.UserID = userID
+	}
+
+	authorMediator := c.AuthorMediatorFactory()
+	books, err := authorMediator.GetBooks(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, books)
+}
+
+// GetV2 retrieves authors from the books backend in the envelope of the v2 API, only
+// holding the fields asked by the fields parameter
+func (c *AuthorController) GetV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	fieldsReq := translators.ToFieldsRequest(r, models.Author{})
+	if err := fieldsReq.Validate(); err != nil {
+		c.Logger.WithField("fields", fieldsReq.Fields).WithError(err).Error("invalid request params for get authors")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	authorMediator := c.AuthorMediatorFactory()
+	authors, err := authorMediator.Get(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	meta := models.Meta{Total: int64(len(authors))}
+	translators.EncodeEnvelope(w, r, http.StatusOK, authors, meta, fieldsReq.Names())
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuthorDedupeController defines the controller to find and merge duplicate authors
+type AuthorDedupeController struct {
+	Logger                      *log.Entry
+	AuthorDedupeMediatorFactory func() mediators.AuthorDedupeMediator
+}
+
+// Duplicates retrieves the candidate duplicate authors for review
+func (c *AuthorDedupeController) Duplicates(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToDuplicateAuthorsRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("min-similarity", req.MinSimilarity).WithField("limit", req.Limit).
+			WithError(err).Error("invalid request params for get duplicate authors")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	duplicates, err := c.AuthorDedupeMediatorFactory().Duplicates(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(duplicates)
+}
+
+// Merge merges an author into another one
+func (c *AuthorDedupeController) Merge(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToAuthorID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for merge author")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+	req, err := translators.ToMergeAuthorRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for merge author")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	author, err := c.AuthorDedupeMediatorFactory().Merge(r.Context(), id, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(author)
+}
@@ -0,0 +1,123 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type AuthorDedupeMediatorMock struct {
+	RequestField *models.DuplicateAuthorsRequest
+	MergeField   *models.MergeAuthorRequest
+	ErrorField   error
+}
+
+func (m *AuthorDedupeMediatorMock) Duplicates(ctx context.Context, req models.DuplicateAuthorsRequest) ([]models.DuplicateAuthors, error) {
+	m.RequestField = &req
+	return []models.DuplicateAuthors{}, m.ErrorField
+}
+
+func (m *AuthorDedupeMediatorMock) Merge(ctx context.Context, id int64, req models.MergeAuthorRequest) (models.AuthorDetail, error) {
+	m.MergeField = &req
+	return models.AuthorDetail{Author: models.Author{ID: req.Into}}, m.ErrorField
+}
+
+func TestAuthorDedupeController(t *testing.T) {
+	var cases = []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		mediator *AuthorDedupeMediatorMock
+		assert   func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock)
+	}{
+		{
+			name:     "duplicates",
+			method:   http.MethodGet,
+			url:      "http://test.com/api/v1/admin/authors/duplicates?min-similarity=0.8&limit=20",
+			mediator: &AuthorDedupeMediatorMock{},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, models.DuplicateAuthorsRequest{MinSimilarity: "0.8", Limit: "20"}, *mediator.RequestField)
+			},
+		},
+		{
+			name:     "invalid similarity",
+			method:   http.MethodGet,
+			url:      "http://test.com/api/v1/admin/authors/duplicates?min-similarity=80",
+			mediator: &AuthorDedupeMediatorMock{},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), "should be between 0 and 1")
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "merge",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/authors/131/merge",
+			body:     `{"into":40}`,
+			mediator: &AuthorDedupeMediatorMock{},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), `"id":40`)
+			},
+		},
+		{
+			name:     "merge without target",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/authors/131/merge",
+			body:     `{}`,
+			mediator: &AuthorDedupeMediatorMock{},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Nil(t, mediator.MergeField)
+			},
+		},
+		{
+			name:     "merge unknown author",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/authors/131/merge",
+			body:     `{"into":400}`,
+			mediator: &AuthorDedupeMediatorMock{ErrorField: models.NewError(models.KindNotFound, "author 400 not found")},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "merge into themselves",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/authors/40/merge",
+			body:     `{"into":40}`,
+			mediator: &AuthorDedupeMediatorMock{ErrorField: models.NewError(models.KindInvalid, "author 40 cannot be merged into themselves")},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *AuthorDedupeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.AuthorDedupeController{
+				Logger:                      log.NewEntry(log.New()),
+				AuthorDedupeMediatorFactory: func() mediators.AuthorDedupeMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			router := mux.NewRouter().PathPrefix("/api/v1/admin").Subrouter()
+			router.HandleFunc("/authors/duplicates", controller.Duplicates).Methods(http.MethodGet)
+			router.HandleFunc("/authors/{id}/merge", controller.Merge).Methods(http.MethodPost)
+			router.ServeHTTP(recorder, httptest.NewRequest(c.method, c.url, strings.NewReader(c.body)))
+
+			c.assert(recorder, c.mediator)
+		})
+	}
+}
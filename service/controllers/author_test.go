@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
 	"github.com/book-recommendations/service/models"
 	"github.com/gorilla/mux"
@@ -19,14 +20,26 @@ import (
 )
 
 type AuthorMediatorMock struct {
-	AuthorField []models.Author
-	ErrorField  error
+	AuthorField  []models.Author
+	DetailField  models.AuthorDetail
+	BookField    []models.Book
+	ErrorField   error
+	RequestField models.AuthorBooksRequest
 }
 
 func (m *AuthorMediatorMock) Get(ctx context.Context) ([]models.Author, error) {
 	return m.AuthorField, m.ErrorField
 }
 
+func (m *AuthorMediatorMock) GetAuthor(ctx context.Context, req models.AuthorRequest) (models.AuthorDetail, error) {
+	return m.DetailField, m.ErrorField
+}
+
+func (m *AuthorMediatorMock) GetBooks(ctx context.Context, req models.AuthorBooksRequest) ([]models.Book, error) {
+	m.RequestField = req
+	return m.BookField, m.ErrorField
+}
+
 func TestAuthorController_Get(t *testing.T) {
 	var cases = []struct {
 		name            string
@@ -102,3 +115,246 @@ func TestAuthorController_Get(t *testing.T) {
 		c.assert(resp, responseBody)
 	}
 }
+
+func TestAuthorController_GetV2(t *testing.T) {
+	authors := []models.Author{
+		{ID: 1, FirstName: "Abraham", LastName: "Stackhouse"},
+		{ID: 2, FirstName: "Amelia", LastName: "Wangerin, Jr."},
+	}
+
+	var cases = []struct {
+		name            string
+		authorMediators mediators.AuthorMediator
+		request         string
+		assert          func(resp *http.Response, body string)
+	}{
+		{
+			name:            "success",
+			authorMediators: &AuthorMediatorMock{AuthorField: authors},
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.JSONEq(t, `{
+					"data": [
+						{"id": 1, "firstName": "Abraham", "lastName": "Stackhouse"},
+						{"id": 2, "firstName": "Amelia", "lastName": "Wangerin, Jr."}
+					],
+					"meta": {"total": 2, "offset": 0},
+					"links": {"self": "/api/v2/authors"}
+				}`, body)
+			},
+		},
+		{
+			name:            "success - sparse fieldset",
+			authorMediators: &AuthorMediatorMock{AuthorField: authors},
+			request:         "fields=lastName",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.JSONEq(t, `{
+					"data": [{"id": 1, "lastName": "Stackhouse"}, {"id": 2, "lastName": "Wangerin, Jr."}],
+					"meta": {"total": 2, "offset": 0},
+					"links": {"self": "/api/v2/authors?fields=lastName"}
+				}`, body)
+			},
+		},
+		{
+			name:            "bad request",
+			authorMediators: &AuthorMediatorMock{AuthorField: authors},
+			request:         "fields=name",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be a list of: id, firstName, lastName")
+			},
+		},
+		{
+			name:            "failure",
+			authorMediators: &AuthorMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.AuthorController{
+			Logger: log.NewEntry(log.New()),
+			AuthorMediatorFactory: func() mediators.AuthorMediator {
+				return c.authorMediators
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		url := "http://test.com/api/v2/authors"
+		if c.request != "" {
+			url += "?" + c.request
+		}
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v2").Subrouter()
+		router.HandleFunc("/authors", controller.GetV2).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+
+		c.assert(resp, string(body))
+	}
+}
+
+func TestAuthorController_GetAuthor(t *testing.T) {
+	var cases = []struct {
+		name            string
+		authorMediators mediators.AuthorMediator
+		id              string
+		assert          func(resp *http.Response, author models.AuthorDetail)
+	}{
+		{
+			name: "success",
+			authorMediators: &AuthorMediatorMock{
+				DetailField: models.AuthorDetail{
+					Author:        models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"},
+					BookCount:     2,
+					AverageRating: 3.4,
+					YearsActive:   &models.YearsActive{From: 1972, To: 1999},
+					MainGenres:    []models.GenreCount{{Genre: models.Genre{ID: 8, Title: "Childrens"}, BookCount: 2}},
+				},
+			},
+			id: "6",
+			assert: func(resp *http.Response, author models.AuthorDetail) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "Hopf", author.LastName)
+				assert.Equal(t, int64(2), author.BookCount)
+				assert.Equal(t, &models.YearsActive{From: 1972, To: 1999}, author.YearsActive)
+				assert.Equal(t, "Childrens", author.MainGenres[0].Title)
+			},
+		},
+		{
+			name:            "not found",
+			authorMediators: &AuthorMediatorMock{ErrorField: models.NewError(models.KindNotFound, "author 7 not found")},
+			id:              "7",
+			assert: func(resp *http.Response, author models.AuthorDetail) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+			},
+		},
+		{
+			name:            "bad request",
+			authorMediators: &AuthorMediatorMock{},
+			id:              "abc",
+			assert: func(resp *http.Response, author models.AuthorDetail) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.AuthorController{
+			Logger: log.NewEntry(log.New()),
+			AuthorMediatorFactory: func() mediators.AuthorMediator {
+				return c.authorMediators
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/authors/"+c.id, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/authors/{id}", controller.GetAuthor).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		author := models.AuthorDetail{}
+		json.NewDecoder(resp.Body).Decode(&author)
+
+		c.assert(resp, author)
+	}
+}
+
+func TestAuthorController_GetBooks(t *testing.T) {
+	var cases = []struct {
+		name            string
+		authorMediators *AuthorMediatorMock
+		url             string
+		header          map[string]string
+		assert          func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1)
+	}{
+		{
+			name: "success",
+			authorMediators: &AuthorMediatorMock{
+				BookField: []models.Book{{
+					ID:      1,
+					Title:   "Alanna Saves the Day",
+					Authors: []models.BookAuthor{{Author: models.Author{ID: 6, LastName: "Hopf"}, Role: models.RoleAuthor}},
+				}},
+			},
+			url: "/api/v1/authors/6/books?genres=8&limit=5&ranker=recency",
+			assert: func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "6", mediator.RequestField.ID)
+				assert.Equal(t, "8", mediator.RequestField.Books.Genres)
+				assert.Equal(t, models.RankerRecency, mediator.RequestField.Books.Ranker)
+				assert.Len(t, books, 1)
+				assert.Equal(t, int64(6), books[0].Author.ID)
+			},
+		},
+		{
+			name:            "exclude read",
+			authorMediators: &AuthorMediatorMock{},
+			url:             "/api/v1/authors/6/books?exclude-read=true",
+			header:          map[string]string{translators.UserIDHeader: "42"},
+			assert: func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(42), mediator.RequestField.Books.UserID)
+			},
+		},
+		{
+			name:            "unauthorized",
+			authorMediators: &AuthorMediatorMock{},
+			url:             "/api/v1/authors/6/books?exclude-read=true",
+			assert: func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1) {
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:            "bad request",
+			authorMediators: &AuthorMediatorMock{},
+			url:             "/api/v1/authors/0/books?limit=0",
+			assert: func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:            "not found",
+			authorMediators: &AuthorMediatorMock{ErrorField: models.NewError(models.KindNotFound, "author 7 not found")},
+			url:             "/api/v1/authors/7/books",
+			assert: func(resp *http.Response, mediator *AuthorMediatorMock, books []translators.BookV1) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.AuthorController{
+			Logger: log.NewEntry(log.New()),
+			AuthorMediatorFactory: func() mediators.AuthorMediator {
+				return c.authorMediators
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com"+c.url, nil)
+		for key, value := range c.header {
+			request.Header.Set(key, value)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/authors/{id}/books", controller.GetBooks).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		books := []translators.BookV1{}
+		json.NewDecoder(resp.Body).Decode(&books)
+
+		c.assert(resp, c.authorMediators, books)
+	}
+}
@@ -1,19 +1,20 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
 	log "github.com/sirupsen/logrus"
 )
 
 // BookController defines the controller for books data
 type BookController struct {
-	Logger              *log.Entry
-	BookMediatorFactory func() mediators.BookMediator
+	Logger                    *log.Entry
+	BookMediatorFactory       func() mediators.BookMediator
+	ExperimentMediatorFactory func() mediators.ExperimentMediator
 }
 
 // Get retrieves books from the books backend
@@ -25,19 +26,264 @@ func (c *BookController) Get(w http.ResponseWriter, r *http.Request) {
 		c.Logger.WithField("authors", req.Authors).WithField("genres", req.Genres).
 			WithField("min-pages", req.MinPages).WithField("max-pages", req.MaxPages).
 			WithField("min-year", req.MinYear).WithField("max-year", req.MaxYear).
-			WithField("limit", req.Limit).WithError(err).Error("invalid request params for get books")
-		translators.ParseError(w, http.StatusBadRequest)
+			WithField("limit", req.Limit).WithField("offset", req.Offset).WithField("ranking", req.Ranking).WithField("ranker", req.Ranker).
+			WithField("exclude-authors", req.ExcludeAuthors).WithField("exclude-genres", req.ExcludeGenres).
+			WithField("min-rating", req.MinRating).WithField("genre-match", req.GenreMatch).WithField("facets", req.Facets).
+			WithField("isbn", req.ISBN).WithField("language", req.Languages).
+			WithField("first-in-series-only", req.FirstInSeriesOnly).WithField("exclude-read", req.ExcludeRead).WithError(err).Error("invalid request params for get books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	c.getBooks(w, r, req)
+}
+
+// Search retrieves books matching the criteria of a JSON body, as Get does for the query string
+func (c *BookController) Search(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	searchReq, err := translators.ToBookSearchRequest(w, r)
+	if err == nil {
+		err = searchReq.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for search books")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	c.getBooks(w, r, searchReq.ToBookRequest())
+}
+
+// GetV2 retrieves books from the books backend in the envelope of the v2 API, only
+// holding the fields asked by the fields parameter
+func (c *BookController) GetV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToBooksRequest(r)
+	fieldsReq := translators.ToFieldsRequest(r, models.Book{})
+	err := req.Validate()
+	if err == nil {
+		err = fieldsReq.Validate()
+	}
+	if err != nil {
+		c.Logger.WithField("query", r.URL.RawQuery).WithError(err).Error("invalid request params for get books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	c.getBooksV2(w, r, req, fieldsReq)
+}
+
+// SearchV2 retrieves books matching the criteria of a JSON body, as GetV2 does for the query string
+func (c *BookController) SearchV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	fieldsReq := translators.ToFieldsRequest(r, models.Book{})
+	if err := fieldsReq.Validate(); err != nil {
+		c.Logger.WithField("fields", fieldsReq.Fields).WithError(err).Error("invalid request params for search books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	searchReq, err := translators.ToBookSearchRequest(w, r)
+	if err == nil {
+		err = searchReq.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for search books")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	c.getBooksV2(w, r, searchReq.ToBookRequest(), fieldsReq)
+}
+
+// getBooks writes the books matching a valid request, along with their facets when asked
+func (c *BookController) getBooks(w http.ResponseWriter, r *http.Request, req models.BookRequest) {
+	req, ok := c.prepareBooks(w, r, req)
+	if !ok {
 		return
 	}
 
 	bookMediator := c.BookMediatorFactory()
-	books, err := bookMediator.Get(context.Background(), req)
+	books, err := bookMediator.Get(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	if req.Facets == "" {
+		translators.EncodeBooks(w, r, http.StatusOK, books)
+		return
+	}
+
+	facets, err := bookMediator.Facets(r.Context(), req)
 	if err != nil {
-		c.Logger.WithError(err).Error("internal server error ")
-		translators.ParseError(w, http.StatusInternalServerError)
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, models.FacetedBooks{Books: books, Facets: facets})
+}
+
+// getBooksV2 writes the envelope of the books matching a valid request, along with
+// their total and their facets when asked
+func (c *BookController) getBooksV2(w http.ResponseWriter, r *http.Request, req models.BookRequest, fieldsReq models.FieldsRequest) {
+	req, ok := c.prepareBooks(w, r, req)
+	if !ok {
+		return
+	}
+
+	bookMediator := c.BookMediatorFactory()
+	books, err := bookMediator.Get(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	total, err := bookMediator.Count(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	var facets models.Facets
+	if req.Facets != "" {
+		if facets, err = bookMediator.Facets(r.Context(), req); err != nil {
+			writeError(c.Logger, w, err)
+			return
+		}
+	}
+
+	meta := translators.ToBooksMeta(req, total, facets)
+	translators.EncodeEnvelope(w, r, http.StatusOK, books, meta, fieldsReq.Names())
+}
+
+// prepareBooks completes a valid request with the user asking it and the ranker of their
+// experiment. It writes the error and returns false when the request needs a user and
+// has none.
+func (c *BookController) prepareBooks(w http.ResponseWriter, r *http.Request, req models.BookRequest) (models.BookRequest, bool) {
+	if req.ExcludeRead == "true" {
+		userID, ok := translators.ToUserID(r)
+		if !ok {
+			translators.ParseError(w, http.StatusUnauthorized)
+			return req, false
+		}
+		req.UserID = userID
+	}
+
+	if c.ExperimentMediatorFactory != nil {
+		assignments := c.ExperimentMediatorFactory().Assign(translators.ToSubject(w, r))
+		translators.SetExperimentHeader(w, assignments)
+		// the ranker of the experiment only applies when the client did not ask for one. The
+		// config runs a single ranker experiment at a time, so a subject has one at most.
+		if req.Ranker == "" && req.Ranking == "" && len(assignments) > 0 {
+			req.Ranker = assignments[0].Ranker
+		}
+	}
+
+	return req, true
+}
+
+// Similar retrieves the books most similar to a given one
+func (c *BookController) Similar(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToSimilarBooksRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("id", req.ID).WithField("limit", req.Limit).
+			WithError(err).Error("invalid request params for get similar books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	bookMediator := c.BookMediatorFactory()
+	books, err := bookMediator.Similar(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, books)
+}
+
+// GetBook retrieves a book, telling apart the deleted books from the ones that never existed
+func (c *BookController) GetBook(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for get book")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	book, err := c.BookMediatorFactory().GetBook(r.Context(), id)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, book)
+}
+
+// History retrieves every version of a book, deleted or not, latest first
+func (c *BookController) History(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for get book history")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	versions, err := c.BookMediatorFactory().GetHistory(r.Context(), id)
+	if err != nil {
+		writeError(c.Logger, w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(books)
+	json.NewEncoder(w).Encode(versions)
+}
+
+// Delete deletes a book, which can be restored
+func (c *BookController) Delete(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for delete book")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	if err := c.BookMediatorFactory().Delete(r.Context(), id); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore restores a deleted book
+func (c *BookController) Restore(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for restore book")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	book, err := c.BookMediatorFactory().Restore(r.Context(), id)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, book)
 }
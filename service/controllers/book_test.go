@@ -7,9 +7,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
 	"github.com/book-recommendations/service/models"
 	"github.com/gorilla/mux"
@@ -19,20 +21,53 @@ import (
 )
 
 type BookMediatorMock struct {
-	BookField  []models.Book
-	ErrorField error
+	BookField    []models.Book
+	SimilarField []models.SimilarBook
+	FacetsField  models.Facets
+	TotalField   int64
+	ErrorField   error
+	RequestField models.BookRequest
 }
 
 func (m *BookMediatorMock) Get(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
+	m.RequestField = req
 	return m.BookField, m.ErrorField
 }
 
+func (m *BookMediatorMock) Count(ctx context.Context, req models.BookRequest) (int64, error) {
+	return m.TotalField, m.ErrorField
+}
+
+func (m *BookMediatorMock) Similar(ctx context.Context, req models.SimilarBooksRequest) ([]models.SimilarBook, error) {
+	return m.SimilarField, m.ErrorField
+}
+
+func (m *BookMediatorMock) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{}, m.ErrorField
+}
+
+func (m *BookMediatorMock) GetHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	return []models.BookVersion{{Version: 1}}, m.ErrorField
+}
+
+func (m *BookMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+func (m *BookMediatorMock) Restore(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{ID: id}, m.ErrorField
+}
+
+func (m *BookMediatorMock) Facets(ctx context.Context, req models.BookRequest) (models.Facets, error) {
+	return m.FacetsField, m.ErrorField
+}
+
 func TestBookController_Get(t *testing.T) {
 	var cases = []struct {
 		name          string
 		bookMediators mediators.BookMediator
 		request       string
-		assert        func(resp *http.Response, books []models.Book)
+		assert        func(resp *http.Response, books []translators.BookV1)
 	}{
 		{
 			name: "success",
@@ -44,15 +79,8 @@ func TestBookController_Get(t *testing.T) {
 						YearPublished: 1972,
 						Rating:        1.62,
 						Pages:         169,
-						Genre: models.Genre{
-							ID:    8,
-							Title: "Childrens",
-						},
-						Author: models.Author{
-							ID:        6,
-							FirstName: "Bernard",
-							LastName:  "Hopf",
-						},
+						Genres:        []models.Genre{{ID: 8, Title: "Childrens"}},
+						Authors:       []models.BookAuthor{{Author: models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"}, Role: models.RoleAuthor}},
 					},
 					{
 						ID:            2,
@@ -60,21 +88,14 @@ func TestBookController_Get(t *testing.T) {
 						YearPublished: 1999,
 						Rating:        2.13,
 						Pages:         619,
-						Genre: models.Genre{
-							ID:    8,
-							Title: "Young Adult",
-						},
-						Author: models.Author{
-							ID:        40,
-							FirstName: "Ward",
-							LastName:  "Haigh",
-						},
+						Genres:        []models.Genre{{ID: 8, Title: "Young Adult"}},
+						Authors:       []models.BookAuthor{{Author: models.Author{ID: 40, FirstName: "Ward", LastName: "Haigh"}, Role: models.RoleAuthor}},
 					},
 				},
 				ErrorField: nil,
 			},
 			request: "limit=10",
-			assert: func(resp *http.Response, books []models.Book) {
+			assert: func(resp *http.Response, books []translators.BookV1) {
 				assert.Equal(t, http.StatusOK, resp.StatusCode)
 				assert.Len(t, books, 2)
 				assert.Equal(t, books[0].ID, int64(1))
@@ -95,7 +116,7 @@ func TestBookController_Get(t *testing.T) {
 				BookField:  []models.Book{},
 				ErrorField: errors.New("Error"),
 			},
-			assert: func(resp *http.Response, books []models.Book) {
+			assert: func(resp *http.Response, books []translators.BookV1) {
 				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 			},
 		},
@@ -106,7 +127,7 @@ func TestBookController_Get(t *testing.T) {
 				ErrorField: errors.New("Error"),
 			},
 			request: "limit=0",
-			assert: func(resp *http.Response, books []models.Book) {
+			assert: func(resp *http.Response, books []translators.BookV1) {
 				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 			},
 		},
@@ -132,9 +153,683 @@ func TestBookController_Get(t *testing.T) {
 
 		body, err := ioutil.ReadAll(resp.Body)
 		require.NoError(t, err, "should return a readable response body")
-		responseBody := []models.Book{}
+		responseBody := []translators.BookV1{}
 		err = json.Unmarshal(body, &responseBody)
 
 		c.assert(resp, responseBody)
 	}
 }
+
+func TestBookController_GetVersion(t *testing.T) {
+	book := models.Book{
+		ID:    3,
+		Title: "Good Omens",
+		Genres: []models.Genre{
+			{ID: 2, Title: "SciFi/Fantasy"},
+			{ID: 9, Title: "Humor"},
+		},
+		Authors: []models.BookAuthor{
+			{Author: models.Author{ID: 11, FirstName: "Terry", LastName: "Pratchett"}, Role: models.RoleAuthor},
+			{Author: models.Author{ID: 12, FirstName: "Neil", LastName: "Gaiman"}, Role: models.RoleAuthor},
+		},
+	}
+
+	var cases = []struct {
+		name   string
+		accept string
+		assert func(resp *http.Response, body map[string]interface{})
+	}{
+		{
+			name: "success - v1 by default",
+			assert: func(resp *http.Response, body map[string]interface{}) {
+				assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+				assert.Equal(t, map[string]interface{}{"id": float64(2), "title": "SciFi/Fantasy"}, body["genre"])
+				assert.Equal(t, "Pratchett", body["author"].(map[string]interface{})["lastName"])
+				assert.NotContains(t, body, "genres")
+				assert.NotContains(t, body, "authors")
+			},
+		},
+		{
+			name:   "success - v2",
+			accept: "application/vnd.readcommend.v2+json, application/json;q=0.5",
+			assert: func(resp *http.Response, body map[string]interface{}) {
+				assert.Equal(t, translators.MediaTypeV2, resp.Header.Get("Content-Type"))
+				assert.Len(t, body["genres"], 2)
+				assert.Len(t, body["authors"], 2)
+				assert.Equal(t, models.RoleAuthor, body["authors"].([]interface{})[1].(map[string]interface{})["role"])
+				assert.NotContains(t, body, "genre")
+				assert.NotContains(t, body, "author")
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return &BookMediatorMock{BookField: []models.Book{book}}
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/books", nil)
+		if c.accept != "" {
+			request.Header.Set("Accept", c.accept)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		var books []map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&books))
+		require.Len(t, books, 1)
+		c.assert(resp, books[0])
+	}
+}
+
+func TestBookController_GetExcludeRead(t *testing.T) {
+	var cases = []struct {
+		name    string
+		request string
+		userID  string
+		assert  func(resp *http.Response, mediator *BookMediatorMock)
+	}{
+		{
+			name:    "success",
+			request: "exclude-read=true",
+			userID:  "42",
+			assert: func(resp *http.Response, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(42), mediator.RequestField.UserID)
+			},
+		},
+		{
+			name:    "success - not excluded",
+			request: "exclude-read=false",
+			assert: func(resp *http.Response, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Zero(t, mediator.RequestField.UserID)
+			},
+		},
+		{
+			name:    "unauthorized",
+			request: "exclude-read=true",
+			assert: func(resp *http.Response, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		bookMediator := &BookMediatorMock{BookField: []models.Book{}}
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return bookMediator
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/books?"+c.request, nil)
+		if c.userID != "" {
+			request.Header.Set("X-User-ID", c.userID)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		c.assert(recorder.Result(), bookMediator)
+	}
+}
+
+func TestBookController_GetFacets(t *testing.T) {
+	var cases = []struct {
+		name    string
+		request string
+		assert  func(resp *http.Response, body map[string]json.RawMessage)
+	}{
+		{
+			name:    "success",
+			request: "facets=genres,sizes",
+			assert: func(resp *http.Response, body map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				var books []translators.BookV1
+				assert.Nil(t, json.Unmarshal(body["books"], &books))
+				assert.Len(t, books, 1)
+				var facets models.Facets
+				assert.Nil(t, json.Unmarshal(body["facets"], &facets))
+				assert.Equal(t, []models.FacetCount{{ID: 4, Count: 12}}, facets[models.FacetGenres])
+			},
+		},
+		{
+			name:    "success - no facets",
+			request: "",
+			assert: func(resp *http.Response, body map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Nil(t, body, "the books should not be wrapped when no facet is asked")
+			},
+		},
+		{
+			name:    "invalid request",
+			request: "facets=publishers",
+			assert: func(resp *http.Response, body map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		bookMediator := &BookMediatorMock{
+			BookField: []models.Book{{ID: 1, Title: "Ender's Game"}},
+			FacetsField: models.Facets{
+				models.FacetGenres: {{ID: 4, Count: 12}},
+				models.FacetSizes:  {{ID: 1, Count: 30}},
+			},
+		}
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return bookMediator
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/books?"+c.request, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		var body map[string]json.RawMessage
+		json.Unmarshal(recorder.Body.Bytes(), &body)
+		c.assert(recorder.Result(), body)
+	}
+}
+
+func TestBookController_Search(t *testing.T) {
+	var cases = []struct {
+		name   string
+		body   string
+		assert func(resp *http.Response, body models.Problem, mediator *BookMediatorMock)
+	}{
+		{
+			name: "success",
+			body: `{"authors": [1, 11], "genres": [3], "pages": {"min": 100}, "minRating": 3.5, "sort": "recency", "limit": 5, "offset": 10}`,
+			assert: func(resp *http.Response, body models.Problem, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, models.BookRequest{
+					Authors:   "1,11",
+					Genres:    "3",
+					MinPages:  "100",
+					MinRating: "3.5",
+					Ranker:    models.RankerRecency,
+					Limit:     "5",
+					Offset:    "10",
+				}, mediator.RequestField)
+			},
+		},
+		{
+			name: "unknown field",
+			body: `{"author": [1]}`,
+			assert: func(resp *http.Response, body models.Problem, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, translators.ErrBadBody, body.Detail)
+				assert.Equal(t, map[string][]string{"author": {"is not a known field"}}, body.Errors)
+			},
+		},
+		{
+			name: "wrong type",
+			body: `{"authors": "1,11"}`,
+			assert: func(resp *http.Response, body models.Problem, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, map[string][]string{"authors": {"should be of type array"}}, body.Errors)
+			},
+		},
+		{
+			name: "invalid fields",
+			body: `{"pages": {"min": 300, "max": 100}, "years": {"max": 3000}, "sort": "popular", "facets": ["years"]}`,
+			assert: func(resp *http.Response, body models.Problem, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, map[string][]string{
+					"pages":  {"min should not be greater than max"},
+					"years":  {"should be between 1800 and 2100"},
+					"sort":   {"should be one of: rating, weighted, recency, random"},
+					"facets": {"should be a list of: authors, genres, sizes, eras"},
+				}, body.Errors)
+			},
+		},
+		{
+			name: "malformed body",
+			body: `{"authors": [1`,
+			assert: func(resp *http.Response, body models.Problem, mediator *BookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Empty(t, body.Errors)
+			},
+		},
+	}
+	for _, c := range cases {
+		bookMediator := &BookMediatorMock{BookField: []models.Book{}}
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return bookMediator
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v1/books/search", strings.NewReader(c.body))
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books/search", controller.Search).Methods(http.MethodPost)
+
+		router.ServeHTTP(recorder, request)
+
+		var body models.Problem
+		json.Unmarshal(recorder.Body.Bytes(), &body)
+		c.assert(recorder.Result(), body, bookMediator)
+	}
+}
+
+func TestBookController_GetProblem(t *testing.T) {
+	controller := controllers.BookController{
+		Logger: log.NewEntry(log.New()),
+		BookMediatorFactory: func() mediators.BookMediator {
+			return &BookMediatorMock{}
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set(translators.RequestIDHeader, "req-42")
+	request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/books?max-pages=10001&genres=x", nil)
+
+	router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+	router.HandleFunc("/books", controller.Get).Methods(http.MethodGet)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, translators.ProblemMediaType, recorder.Header().Get("Content-Type"))
+	var problem models.Problem
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, "req-42", problem.Instance)
+	assert.Equal(t, map[string][]string{
+		"max-pages": {"should be between 1 and 10000"},
+		"genres":    {"should be for exaple: 123,456,789"},
+	}, problem.Errors)
+}
+
+func TestBookController_GetExperiment(t *testing.T) {
+	var cases = []struct {
+		name    string
+		request string
+		assert  func(resp *http.Response, req models.BookRequest)
+	}{
+		{
+			name: "success - ranker of the variant",
+			assert: func(resp *http.Response, req models.BookRequest) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "ranker=recency", resp.Header.Get("X-Experiment-Variant"))
+				assert.Equal(t, models.RankerRecency, req.Ranker)
+			},
+		},
+		{
+			name:    "success - requested ranker wins",
+			request: "ranker=rating",
+			assert: func(resp *http.Response, req models.BookRequest) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "ranker=recency", resp.Header.Get("X-Experiment-Variant"))
+				assert.Equal(t, models.RankerRating, req.Ranker)
+			},
+		},
+	}
+	for _, c := range cases {
+		bookMediator := &BookMediatorMock{}
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return bookMediator
+			},
+			ExperimentMediatorFactory: func() mediators.ExperimentMediator {
+				return &ExperimentMediatorMock{
+					AssignmentField: []models.Assignment{
+						{Experiment: "ranker", Variant: "recency", Ranker: models.RankerRecency},
+					},
+				}
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/books?"+c.request, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		c.assert(recorder.Result(), bookMediator.RequestField)
+	}
+}
+
+func TestBookController_Similar(t *testing.T) {
+	var cases = []struct {
+		name          string
+		bookMediators mediators.BookMediator
+		path          string
+		assert        func(resp *http.Response, books []models.SimilarBook)
+	}{
+		{
+			name: "success",
+			bookMediators: &BookMediatorMock{
+				SimilarField: []models.SimilarBook{
+					{
+						Book: models.Book{
+							ID:    2,
+							Title: "Adventures of Kaya",
+						},
+						Score: 4.5,
+					},
+				},
+			},
+			path: "/books/1/similar?limit=5",
+			assert: func(resp *http.Response, books []models.SimilarBook) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Len(t, books, 1)
+				assert.Equal(t, int64(2), books[0].ID)
+				assert.Equal(t, "Adventures of Kaya", books[0].Title)
+				assert.Equal(t, 4.5, books[0].Score)
+			},
+		},
+		{
+			name: "not found",
+			bookMediators: &BookMediatorMock{
+				ErrorField: models.ErrNotFound,
+			},
+			path: "/books/42/similar",
+			assert: func(resp *http.Response, books []models.SimilarBook) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name: "failure",
+			bookMediators: &BookMediatorMock{
+				ErrorField: errors.New("Error"),
+			},
+			path: "/books/1/similar",
+			assert: func(resp *http.Response, books []models.SimilarBook) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+		{
+			name:          "bad request",
+			bookMediators: &BookMediatorMock{},
+			path:          "/books/abc/similar",
+			assert: func(resp *http.Response, books []models.SimilarBook) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		bookMediatorFactory := func() mediators.BookMediator {
+			return c.bookMediators
+		}
+		controller := controllers.BookController{
+			Logger:              log.NewEntry(log.New()),
+			BookMediatorFactory: bookMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1"+c.path, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/books/{id}/similar", controller.Similar).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+		responseBody := []models.SimilarBook{}
+		err = json.Unmarshal(body, &responseBody)
+
+		c.assert(resp, responseBody)
+	}
+}
+
+func TestBookController_GetV2(t *testing.T) {
+	books := []models.Book{
+		{
+			ID:      1,
+			Title:   "Alanna Saves the Day",
+			Rating:  1.62,
+			Genres:  []models.Genre{{ID: 8, Title: "Childrens"}},
+			Authors: []models.BookAuthor{{Author: models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"}, Role: models.RoleAuthor}},
+		},
+		{
+			ID:     2,
+			Title:  "Adventures of Kaya",
+			Rating: 2.13,
+		},
+	}
+
+	var cases = []struct {
+		name          string
+		bookMediators *BookMediatorMock
+		request       string
+		assert        func(resp *http.Response, envelope map[string]json.RawMessage)
+	}{
+		{
+			name:          "success",
+			bookMediators: &BookMediatorMock{BookField: books, TotalField: 12},
+			request:       "limit=2&offset=2",
+			assert: func(resp *http.Response, envelope map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+				var data []models.Book
+				require.NoError(t, json.Unmarshal(envelope["data"], &data))
+				assert.Equal(t, books, data)
+
+				var meta models.Meta
+				require.NoError(t, json.Unmarshal(envelope["meta"], &meta))
+				assert.Equal(t, models.Meta{Total: 12, Limit: 2, Offset: 2}, meta)
+
+				var links models.Links
+				require.NoError(t, json.Unmarshal(envelope["links"], &links))
+				assert.Equal(t, "/api/v2/books?limit=2&offset=2", links.Self)
+				assert.Equal(t, "/api/v2/books?limit=2&offset=0", links.Prev)
+				assert.Equal(t, "/api/v2/books?limit=2&offset=4", links.Next)
+			},
+		},
+		{
+			name:          "success - sparse fieldset",
+			bookMediators: &BookMediatorMock{BookField: books, TotalField: 2},
+			request:       "fields=title,authors",
+			assert: func(resp *http.Response, envelope map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.JSONEq(t, `[
+					{"id": 1, "title": "Alanna Saves the Day", "authors": [{"id": 6, "firstName": "Bernard", "lastName": "Hopf", "role": "author"}]},
+					{"id": 2, "title": "Adventures of Kaya", "authors": null}
+				]`, string(envelope["data"]))
+			},
+		},
+		{
+			name: "success - facets",
+			bookMediators: &BookMediatorMock{
+				BookField:   books,
+				TotalField:  2,
+				FacetsField: models.Facets{models.FacetGenres: {{ID: 8, Count: 2}}},
+			},
+			request: "facets=genres",
+			assert: func(resp *http.Response, envelope map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				var meta models.Meta
+				require.NoError(t, json.Unmarshal(envelope["meta"], &meta))
+				assert.Equal(t, []models.FacetCount{{ID: 8, Count: 2}}, meta.Facets[models.FacetGenres])
+			},
+		},
+		{
+			name:          "bad request - unknown field",
+			bookMediators: &BookMediatorMock{BookField: books},
+			request:       "fields=title,author",
+			assert: func(resp *http.Response, envelope map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, string(envelope["errors"]), "fields")
+			},
+		},
+		{
+			name:          "failure",
+			bookMediators: &BookMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(resp *http.Response, envelope map[string]json.RawMessage) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.BookController{
+			Logger: log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator {
+				return c.bookMediators
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v2/books?"+c.request, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v2").Subrouter()
+		router.HandleFunc("/books", controller.GetV2).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+		envelope := map[string]json.RawMessage{}
+		require.NoError(t, json.Unmarshal(body, &envelope))
+
+		c.assert(resp, envelope)
+	}
+}
+
+func TestBookController_SearchV2(t *testing.T) {
+	mediator := &BookMediatorMock{BookField: []models.Book{{ID: 1, Title: "Alanna Saves the Day"}}, TotalField: 30}
+	controller := controllers.BookController{
+		Logger: log.NewEntry(log.New()),
+		BookMediatorFactory: func() mediators.BookMediator {
+			return mediator
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v2/books/search?fields=title",
+		strings.NewReader(`{"genres": [3], "limit": 1, "offset": 10}`))
+
+	router := mux.NewRouter().PathPrefix("/api/v2").Subrouter()
+	router.HandleFunc("/books/search", controller.SearchV2).Methods(http.MethodPost)
+	router.ServeHTTP(recorder, request)
+
+	resp := recorder.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "3", mediator.RequestField.Genres)
+
+	var envelope models.Envelope
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(1), "title": "Alanna Saves the Day"}}, envelope.Data)
+	assert.Equal(t, models.Meta{Total: 30, Limit: 1, Offset: 10}, envelope.Meta)
+	assert.Equal(t, models.Links{Self: "/api/v2/books/search?fields=title"}, envelope.Links)
+}
+
+func TestBookController_GetBook(t *testing.T) {
+	var cases = []struct {
+		name     string
+		method   string
+		path     string
+		mediator *BookMediatorMock
+		status   int
+	}{
+		{
+			name:     "success",
+			method:   http.MethodGet,
+			path:     "/books/1",
+			mediator: &BookMediatorMock{},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "deleted",
+			method:   http.MethodGet,
+			path:     "/books/1",
+			mediator: &BookMediatorMock{ErrorField: models.NewError(models.KindGone, "book 1 was deleted")},
+			status:   http.StatusGone,
+		},
+		{
+			name:     "not found",
+			method:   http.MethodGet,
+			path:     "/books/42",
+			mediator: &BookMediatorMock{ErrorField: models.NewError(models.KindNotFound, "book 42 not found")},
+			status:   http.StatusNotFound,
+		},
+		{
+			name:     "bad request",
+			method:   http.MethodGet,
+			path:     "/books/0",
+			mediator: &BookMediatorMock{},
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "history",
+			method:   http.MethodGet,
+			path:     "/books/1/history",
+			mediator: &BookMediatorMock{},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "delete",
+			method:   http.MethodDelete,
+			path:     "/admin/books/1",
+			mediator: &BookMediatorMock{},
+			status:   http.StatusNoContent,
+		},
+		{
+			name:     "delete deleted",
+			method:   http.MethodDelete,
+			path:     "/admin/books/1",
+			mediator: &BookMediatorMock{ErrorField: models.NewError(models.KindGone, "book 1 was deleted")},
+			status:   http.StatusGone,
+		},
+		{
+			name:     "restore",
+			method:   http.MethodPost,
+			path:     "/admin/books/1/restore",
+			mediator: &BookMediatorMock{},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "restore not deleted",
+			method:   http.MethodPost,
+			path:     "/admin/books/1/restore",
+			mediator: &BookMediatorMock{ErrorField: models.NewError(models.KindConflict, "book 1 is not deleted")},
+			status:   http.StatusConflict,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.BookController{
+				Logger:              log.NewEntry(log.New()),
+				BookMediatorFactory: func() mediators.BookMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+			router.HandleFunc("/books/{id}", controller.GetBook).Methods(http.MethodGet)
+			router.HandleFunc("/books/{id}/history", controller.History).Methods(http.MethodGet)
+			router.HandleFunc("/admin/books/{id}", controller.Delete).Methods(http.MethodDelete)
+			router.HandleFunc("/admin/books/{id}/restore", controller.Restore).Methods(http.MethodPost)
+			router.ServeHTTP(recorder, httptest.NewRequest(c.method, "http://test.com/api/v1"+c.path, nil))
+
+			assert.Equal(t, c.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// DataQualityController defines the controller for the data-quality report of the catalog
+type DataQualityController struct {
+	Logger                     *log.Entry
+	DataQualityMediatorFactory func() mediators.DataQualityMediator
+}
+
+// Get checks the data of the catalog and reports the rows breaking each rule. A report that did
+// not pass is returned with a conflict status, as the check-data command exits with a nonzero
+// status, so that either can gate a deployment.
+func (c *DataQualityController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToDataQualityRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("rules", req.Rules).WithError(err).Error("invalid request params for get data quality")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	report, err := c.DataQualityMediatorFactory().Check(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Passed {
+		w.WriteHeader(http.StatusConflict)
+	}
+	json.NewEncoder(w).Encode(report)
+}
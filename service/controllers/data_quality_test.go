@@ -0,0 +1,96 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type DataQualityMediatorMock struct {
+	RequestField *models.DataQualityRequest
+	ReportField  models.DataQualityReport
+	ErrorField   error
+}
+
+func (m *DataQualityMediatorMock) Check(ctx context.Context, req models.DataQualityRequest) (models.DataQualityReport, error) {
+	m.RequestField = &req
+	return m.ReportField, m.ErrorField
+}
+
+func TestDataQualityController_Get(t *testing.T) {
+	var cases = []struct {
+		name     string
+		url      string
+		mediator *DataQualityMediatorMock
+		assert   func(recorder *httptest.ResponseRecorder, mediator *DataQualityMediatorMock)
+	}{
+		{
+			name: "failed report",
+			url:  "http://test.com/api/v1/admin/data-quality?rules=book-pages,orphan-genre",
+			mediator: &DataQualityMediatorMock{ReportField: models.DataQualityReport{
+				Results: []models.DataQualityResult{{Rule: models.RuleOrphanGenre, Violations: 1}},
+			}},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *DataQualityMediatorMock) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assert.Equal(t, models.DataQualityRequest{Rules: "book-pages,orphan-genre"}, *mediator.RequestField)
+				assert.Contains(t, recorder.Body.String(), `"passed":false`)
+				assert.Contains(t, recorder.Body.String(), `"rule":"orphan-genre"`)
+			},
+		},
+		{
+			name: "passed report",
+			url:  "http://test.com/api/v1/admin/data-quality",
+			mediator: &DataQualityMediatorMock{ReportField: models.DataQualityReport{
+				Passed:  true,
+				Results: []models.DataQualityResult{{Rule: models.RuleBookPages, Passed: true}},
+			}},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *DataQualityMediatorMock) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, models.DataQualityRequest{}, *mediator.RequestField)
+				assert.Contains(t, recorder.Body.String(), `"passed":true`)
+			},
+		},
+		{
+			name:     "unknown rule",
+			url:      "http://test.com/api/v1/admin/data-quality?rules=spelling",
+			mediator: &DataQualityMediatorMock{},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *DataQualityMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), "should be a list of")
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "failure",
+			url:      "http://test.com/api/v1/admin/data-quality",
+			mediator: &DataQualityMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(recorder *httptest.ResponseRecorder, mediator *DataQualityMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.DataQualityController{
+				Logger:                     log.NewEntry(log.New()),
+				DataQualityMediatorFactory: func() mediators.DataQualityMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			router := mux.NewRouter().PathPrefix("/api/v1/admin").Subrouter()
+			router.HandleFunc("/data-quality", controller.Get).Methods(http.MethodGet)
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.url, nil))
+
+			c.assert(recorder, c.mediator)
+		})
+	}
+}
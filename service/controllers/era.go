@@ -1,12 +1,12 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -21,13 +21,115 @@ func (c *EraController) Get(w http.ResponseWriter, r *http.Request) {
 	c.Logger.WithField("url", r.URL).Info("request")
 
 	eraMediator := c.EraMediatorFactory()
-	eras, err := eraMediator.Get(context.Background())
+	eras, err := eraMediator.Get(r.Context())
 	if err != nil {
-		c.Logger.WithError(err).Error("internal server error")
-		translators.ParseError(w, http.StatusInternalServerError)
+		writeError(c.Logger, w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(eras)
 }
+
+// GetV2 retrieves eras from the books backend in the envelope of the v2 API
+func (c *EraController) GetV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	eraMediator := c.EraMediatorFactory()
+	eras, err := eraMediator.Get(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	meta := models.Meta{Total: int64(len(eras))}
+	translators.EncodeEnvelope(w, r, http.StatusOK, eras, meta, nil)
+}
+
+// GetAll retrieves every era, the retired ones included, for the admins
+func (c *EraController) GetAll(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	eras, err := c.EraMediatorFactory().GetAll(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(eras)
+}
+
+// Post creates an era
+func (c *EraController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, err := translators.ToEraRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post era")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	era, err := c.EraMediatorFactory().Create(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(era)
+}
+
+// Put replaces an era
+func (c *EraController) Put(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBucketID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for put era")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+	req, err := translators.ToEraRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for put era")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	era, err := c.EraMediatorFactory().Update(r.Context(), id, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(era)
+}
+
+// Delete deletes an era
+func (c *EraController) Delete(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBucketID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for delete era")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	if err := c.EraMediatorFactory().Delete(r.Context(), id); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
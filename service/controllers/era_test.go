@@ -27,6 +27,22 @@ func (m *EraMediatorMock) Get(ctx context.Context) ([]models.Era, error) {
 	return m.EraField, m.ErrorField
 }
 
+func (m *EraMediatorMock) GetAll(ctx context.Context) ([]models.Era, error) {
+	return m.EraField, m.ErrorField
+}
+
+func (m *EraMediatorMock) Create(ctx context.Context, req models.EraRequest) (models.Era, error) {
+	return req.Era(9), m.ErrorField
+}
+
+func (m *EraMediatorMock) Update(ctx context.Context, id int64, req models.EraRequest) (models.Era, error) {
+	return req.Era(id), m.ErrorField
+}
+
+func (m *EraMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
 func TestEraController_Get(t *testing.T) {
 	MaxYear := int64(1969)
 	MinYear := int64(1970)
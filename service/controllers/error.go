@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	log "github.com/sirupsen/logrus"
+)
+
+// writeError writes the problem matching a domain error, logging the server errors
+// along with the ID of the request
+func writeError(logger *log.Entry, w http.ResponseWriter, err error) {
+	if status := translators.ParseDomainError(w, err); status >= http.StatusInternalServerError {
+		logger.WithField("requestId", w.Header().Get(translators.RequestIDHeader)).WithError(err).Error("internal server error")
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// EventController defines the controller for experiment events
+type EventController struct {
+	Logger                    *log.Entry
+	ExperimentMediatorFactory func() mediators.ExperimentMediator
+}
+
+// Post records an impression or a click of the caller
+func (c *EventController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	event, err := translators.ToEvent(w, r)
+	if err == nil {
+		err = event.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post event")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+	event.Subject = translators.ToSubject(w, r)
+
+	experimentMediator := c.ExperimentMediatorFactory()
+	err = experimentMediator.LogEvent(r.Context(), event)
+	if errors.Is(err, models.ErrUnknownVariant) {
+		c.Logger.WithField("experiment", event.Experiment).WithField("variant", event.Variant).
+			WithError(err).Error("invalid request body for post event")
+	}
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type ExperimentMediatorMock struct {
+	AssignmentField []models.Assignment
+	EventField      models.Event
+	ErrorField      error
+}
+
+func (m *ExperimentMediatorMock) Assign(subject string) []models.Assignment {
+	return m.AssignmentField
+}
+
+func (m *ExperimentMediatorMock) LogEvent(ctx context.Context, event models.Event) error {
+	m.EventField = event
+	return m.ErrorField
+}
+
+func TestEventController_Post(t *testing.T) {
+	var cases = []struct {
+		name                string
+		experimentMediators *ExperimentMediatorMock
+		body                string
+		userID              string
+		assert              func(resp *http.Response, mediator *ExperimentMediatorMock)
+	}{
+		{
+			name:                "success",
+			experimentMediators: &ExperimentMediatorMock{},
+			body:                `{"type":"click","experiment":"ranker","variant":"recency","bookId":12}`,
+			userID:              "42",
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.Equal(t, models.EventClick, mediator.EventField.Type)
+				assert.Equal(t, int64(12), mediator.EventField.BookID)
+				assert.Equal(t, "user:42", mediator.EventField.Subject)
+			},
+		},
+		{
+			name:                "success - anonymous",
+			experimentMediators: &ExperimentMediatorMock{},
+			body:                `{"type":"impression","experiment":"ranker","variant":"recency","bookId":12}`,
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.True(t, strings.HasPrefix(mediator.EventField.Subject, "anon:"))
+				assert.Len(t, resp.Cookies(), 1)
+			},
+		},
+		{
+			name:                "bad request - unknown field",
+			experimentMediators: &ExperimentMediatorMock{},
+			body:                `{"type":"click","experiment":"ranker","variant":"recency","bookId":12,"subject":"user:1"}`,
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:                "bad request - invalid type",
+			experimentMediators: &ExperimentMediatorMock{},
+			body:                `{"type":"purchase","experiment":"ranker","variant":"recency","bookId":12}`,
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:                "bad request - unknown variant",
+			experimentMediators: &ExperimentMediatorMock{ErrorField: models.ErrUnknownVariant},
+			body:                `{"type":"click","experiment":"ranker","variant":"other","bookId":12}`,
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:                "failure",
+			experimentMediators: &ExperimentMediatorMock{ErrorField: errors.New("Error")},
+			body:                `{"type":"click","experiment":"ranker","variant":"recency","bookId":12}`,
+			assert: func(resp *http.Response, mediator *ExperimentMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		experimentMediatorFactory := func() mediators.ExperimentMediator {
+			return c.experimentMediators
+		}
+		controller := controllers.EventController{
+			Logger:                    log.NewEntry(log.New()),
+			ExperimentMediatorFactory: experimentMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v1/events", strings.NewReader(c.body))
+		if c.userID != "" {
+			request.Header.Set("X-User-ID", c.userID)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/events", controller.Post).Methods(http.MethodPost)
+
+		router.ServeHTTP(recorder, request)
+
+		c.assert(recorder.Result(), c.experimentMediators)
+	}
+}
@@ -1,12 +1,12 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -16,18 +16,60 @@ type GenreController struct {
 	GenreMediatorFactory func() mediators.GenreMediator
 }
 
-// Get retrieves genre from the books backend
+// Get retrieves genre from the books backend, along with the figures of their books
+// when the stats parameter is true
 func (c *GenreController) Get(w http.ResponseWriter, r *http.Request) {
 	c.Logger.WithField("url", r.URL).Info("request")
 
+	req := translators.ToGenresRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("stats", req.Stats).WithError(err).Error("invalid request params for get genres")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
 	genreMediator := c.GenreMediatorFactory()
-	genres, err := genreMediator.Get(context.Background())
+	if req.Stats == "true" {
+		stats, err := genreMediator.GetStats(r.Context())
+		if err != nil {
+			writeError(c.Logger, w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+		return
+	}
+
+	genres, err := genreMediator.Get(r.Context())
 	if err != nil {
-		c.Logger.WithError(err).Error("internal server error")
-		translators.ParseError(w, http.StatusInternalServerError)
+		writeError(c.Logger, w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(genres)
 }
+
+// GetV2 retrieves genres from the books backend in the envelope of the v2 API, only
+// holding the fields asked by the fields parameter
+func (c *GenreController) GetV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	fieldsReq := translators.ToFieldsRequest(r, models.Genre{})
+	if err := fieldsReq.Validate(); err != nil {
+		c.Logger.WithField("fields", fieldsReq.Fields).WithError(err).Error("invalid request params for get genres")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	genreMediator := c.GenreMediatorFactory()
+	genres, err := genreMediator.Get(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	meta := models.Meta{Total: int64(len(genres))}
+	translators.EncodeEnvelope(w, r, http.StatusOK, genres, meta, fieldsReq.Names())
+}
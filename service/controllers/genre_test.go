@@ -20,6 +20,7 @@ import (
 
 type GenreMediatorMock struct {
 	GenreField []models.Genre
+	StatsField []models.GenreStats
 	ErrorField error
 }
 
@@ -27,6 +28,10 @@ func (m *GenreMediatorMock) Get(ctx context.Context) ([]models.Genre, error) {
 	return m.GenreField, m.ErrorField
 }
 
+func (m *GenreMediatorMock) GetStats(ctx context.Context) ([]models.GenreStats, error) {
+	return m.StatsField, m.ErrorField
+}
+
 func TestGenreController_Get(t *testing.T) {
 	var cases = []struct {
 		name           string
@@ -98,3 +103,73 @@ func TestGenreController_Get(t *testing.T) {
 		c.assert(resp, responseBody)
 	}
 }
+
+func TestGenreController_GetStats(t *testing.T) {
+	var cases = []struct {
+		name           string
+		genreMediators mediators.GenreMediator
+		request        string
+		assert         func(resp *http.Response, body string)
+	}{
+		{
+			name: "success",
+			genreMediators: &GenreMediatorMock{
+				StatsField: []models.GenreStats{
+					{Genre: models.Genre{ID: 2, Title: "SciFi/Fantasy"}, BookCount: 12, AverageRating: 3.2},
+				},
+			},
+			request: "stats=true",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.JSONEq(t, `[{"id": 2, "title": "SciFi/Fantasy", "bookCount": 12, "averageRating": 3.2}]`, body)
+			},
+		},
+		{
+			name:           "success - without stats",
+			genreMediators: &GenreMediatorMock{GenreField: []models.Genre{{ID: 2, Title: "SciFi/Fantasy"}}},
+			request:        "stats=false",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.JSONEq(t, `[{"id": 2, "title": "SciFi/Fantasy"}]`, body)
+			},
+		},
+		{
+			name:           "bad request",
+			genreMediators: &GenreMediatorMock{},
+			request:        "stats=yes",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:           "failure",
+			genreMediators: &GenreMediatorMock{ErrorField: errors.New("Error")},
+			request:        "stats=true",
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.GenreController{
+			Logger: log.NewEntry(log.New()),
+			GenreMediatorFactory: func() mediators.GenreMediator {
+				return c.genreMediators
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/genres?"+c.request, nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/genres", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+
+		c.assert(resp, string(body))
+	}
+}
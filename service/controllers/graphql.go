@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	"github.com/graphql-go/graphql"
+	log "github.com/sirupsen/logrus"
+)
+
+// GraphQLExecutor runs the GraphQL requests on behalf of a user, zero for anonymous callers
+type GraphQLExecutor interface {
+	Execute(ctx context.Context, req models.GraphQLRequest, userID int64) *graphql.Result
+}
+
+// GraphQLController defines the controller for the GraphQL endpoint
+type GraphQLController struct {
+	Logger   *log.Entry
+	Executor GraphQLExecutor
+}
+
+// Post runs a GraphQL query. The errors of the fields are reported in the result along
+// with the data, the queries rejected before being executed have no data and a 400.
+func (c *GraphQLController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, err := translators.ToGraphQLRequest(w, r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for graphql")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	// anonymous callers can run every query but the ones about their own books
+	userID, _ := translators.ToUserID(r)
+	result := c.Executor.Execute(r.Context(), req, userID)
+
+	status := http.StatusOK
+	if result.Data == nil {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,121 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type GraphQLExecutorMock struct {
+	ResultField  *graphql.Result
+	RequestField models.GraphQLRequest
+	UserIDField  int64
+}
+
+func (m *GraphQLExecutorMock) Execute(ctx context.Context, req models.GraphQLRequest, userID int64) *graphql.Result {
+	m.RequestField = req
+	m.UserIDField = userID
+	return m.ResultField
+}
+
+func TestGraphQLController_Post(t *testing.T) {
+	var cases = []struct {
+		name     string
+		executor *GraphQLExecutorMock
+		body     string
+		userID   string
+		assert   func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock)
+	}{
+		{
+			name: "success",
+			executor: &GraphQLExecutorMock{ResultField: &graphql.Result{
+				Data: map[string]interface{}{"books": []interface{}{map[string]interface{}{"id": 1}}},
+			}},
+			body:   `{"query": "query($limit: Int) { books(limit: $limit) { id } }", "variables": {"limit": 1}}`,
+			userID: "42",
+			assert: func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+				assert.Contains(t, body, "data")
+				assert.Equal(t, "query($limit: Int) { books(limit: $limit) { id } }", executor.RequestField.Query)
+				assert.Equal(t, float64(1), executor.RequestField.Variables["limit"])
+				assert.Equal(t, int64(42), executor.UserIDField)
+			},
+		},
+		{
+			name: "anonymous",
+			executor: &GraphQLExecutorMock{ResultField: &graphql.Result{
+				Data: map[string]interface{}{"genres": []interface{}{}},
+			}},
+			body: `{"query": "{ genres { id } }"}`,
+			assert: func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(0), executor.UserIDField)
+			},
+		},
+		{
+			name: "rejected",
+			executor: &GraphQLExecutorMock{ResultField: &graphql.Result{
+				Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError("the query is 9 levels deep, more than the maximum of 8")},
+			}},
+			body: `{"query": "{ books { id } }"}`,
+			assert: func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "errors")
+				assert.Nil(t, body["data"])
+			},
+		},
+		{
+			name:     "missing query",
+			executor: &GraphQLExecutorMock{},
+			body:     `{"variables": {}}`,
+			assert: func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, translators.ProblemMediaType, resp.Header.Get("Content-Type"))
+				assert.Equal(t, map[string]interface{}{"query": []interface{}{"cannot be blank"}}, body["errors"])
+			},
+		},
+		{
+			name:     "malformed body",
+			executor: &GraphQLExecutorMock{},
+			body:     `{"query": "{ books { id } }", "operation": "Books"}`,
+			assert: func(resp *http.Response, body map[string]interface{}, executor *GraphQLExecutorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, translators.ErrBadBody, body["detail"])
+			},
+		},
+	}
+	for _, c := range cases {
+		controller := controllers.GraphQLController{
+			Logger:   log.NewEntry(log.New()),
+			Executor: c.executor,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v1/graphql", strings.NewReader(c.body))
+		if c.userID != "" {
+			request.Header.Set(translators.UserIDHeader, c.userID)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/graphql", controller.Post).Methods(http.MethodPost)
+
+		router.ServeHTTP(recorder, request)
+
+		var body map[string]interface{}
+		json.Unmarshal(recorder.Body.Bytes(), &body)
+		c.assert(recorder.Result(), body, c.executor)
+	}
+}
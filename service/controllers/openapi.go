@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// OpenAPIController defines the controller serving the OpenAPI specification of the API
+type OpenAPIController struct {
+	Logger *log.Entry
+	Spec   []byte
+	Docs   []byte
+}
+
+// GetSpec writes the specification
+func (c *OpenAPIController) GetSpec(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(c.Spec)
+}
+
+// GetDocs writes the documentation page of the specification
+func (c *OpenAPIController) GetDocs(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(c.Docs)
+}
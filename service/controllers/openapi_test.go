@@ -0,0 +1,67 @@
+package controllers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/openapi"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIController(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	docs, err := openapi.Docs(doc)
+	require.NoError(t, err)
+
+	c := controllers.OpenAPIController{
+		Logger: log.WithField("test", "OpenAPIController"),
+		Spec:   openapi.Spec,
+		Docs:   docs,
+	}
+
+	var cases = []struct {
+		name    string
+		handler http.HandlerFunc
+		assert  func(resp *http.Response, body string)
+	}{
+		{
+			name:    "spec",
+			handler: c.GetSpec,
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+				assert.Equal(t, string(openapi.Spec), body)
+			},
+		},
+		{
+			name:    "docs",
+			handler: c.GetDocs,
+			assert: func(resp *http.Response, body string) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+				assert.Contains(t, body, "<title>"+doc.Info.Title+" API</title>")
+				assert.Contains(t, body, `"/v1/books/{id}/similar"`)
+				assert.False(t, strings.Contains(body, "{{"), "the page holds unrendered template actions")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			tc.handler(res, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			resp := res.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			tc.assert(resp, string(body))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// RecommendationController defines the controller for personalized recommendations
+type RecommendationController struct {
+	Logger                        *log.Entry
+	RecommendationMediatorFactory func() mediators.RecommendationMediator
+}
+
+// Get retrieves the books recommended to the caller
+func (c *RecommendationController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, ok := translators.ToUserID(r)
+	if !ok {
+		translators.ParseError(w, http.StatusUnauthorized)
+		return
+	}
+
+	req := translators.ToBooksRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("authors", req.Authors).WithField("genres", req.Genres).
+			WithField("min-pages", req.MinPages).WithField("max-pages", req.MaxPages).
+			WithField("min-year", req.MinYear).WithField("max-year", req.MaxYear).
+			WithField("limit", req.Limit).WithError(err).Error("invalid request params for get recommendations")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+	req.UserID = userID
+
+	recommendationMediator := c.RecommendationMediatorFactory()
+	recommendations, err := recommendationMediator.Recommend(r.Context(), userID, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, recommendations)
+}
@@ -0,0 +1,119 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RecommendationMediatorMock struct {
+	RecommendationField []models.Recommendation
+	ErrorField          error
+	UserIDField         int64
+}
+
+func (m *RecommendationMediatorMock) Recommend(ctx context.Context, userID int64, req models.BookRequest) ([]models.Recommendation, error) {
+	m.UserIDField = userID
+	return m.RecommendationField, m.ErrorField
+}
+
+func (m *RecommendationMediatorMock) RefreshSimilarities(ctx context.Context) error {
+	return m.ErrorField
+}
+
+func TestRecommendationController_Get(t *testing.T) {
+	var cases = []struct {
+		name                    string
+		recommendationMediators *RecommendationMediatorMock
+		request                 string
+		userID                  string
+		assert                  func(resp *http.Response, recommendations []models.Recommendation, mediator *RecommendationMediatorMock)
+	}{
+		{
+			name: "success",
+			recommendationMediators: &RecommendationMediatorMock{
+				RecommendationField: []models.Recommendation{
+					{
+						Book:   models.Book{ID: 5, Title: "Adventures of Kaya"},
+						Score:  4.4,
+						Reason: models.ReasonCollaborative,
+					},
+				},
+			},
+			request: "genres=1,2&limit=10",
+			userID:  "42",
+			assert: func(resp *http.Response, recommendations []models.Recommendation, mediator *RecommendationMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(42), mediator.UserIDField)
+				assert.Len(t, recommendations, 1)
+				assert.Equal(t, int64(5), recommendations[0].ID)
+				assert.Equal(t, models.ReasonCollaborative, recommendations[0].Reason)
+			},
+		},
+		{
+			name:                    "unauthorized",
+			recommendationMediators: &RecommendationMediatorMock{},
+			assert: func(resp *http.Response, recommendations []models.Recommendation, mediator *RecommendationMediatorMock) {
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:                    "bad request",
+			recommendationMediators: &RecommendationMediatorMock{},
+			request:                 "min-pages=0",
+			userID:                  "42",
+			assert: func(resp *http.Response, recommendations []models.Recommendation, mediator *RecommendationMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:                    "failure",
+			recommendationMediators: &RecommendationMediatorMock{ErrorField: errors.New("Error")},
+			userID:                  "42",
+			assert: func(resp *http.Response, recommendations []models.Recommendation, mediator *RecommendationMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		recommendationMediatorFactory := func() mediators.RecommendationMediator {
+			return c.recommendationMediators
+		}
+		controller := controllers.RecommendationController{
+			Logger:                        log.NewEntry(log.New()),
+			RecommendationMediatorFactory: recommendationMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/me/recommendations?"+c.request, nil)
+		if c.userID != "" {
+			request.Header.Set("X-User-ID", c.userID)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/me/recommendations", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+		responseBody := []models.Recommendation{}
+		err = json.Unmarshal(body, &responseBody)
+
+		c.assert(resp, responseBody, c.recommendationMediators)
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// SeriesController defines the controller for series data
+type SeriesController struct {
+	Logger                *log.Entry
+	SeriesMediatorFactory func() mediators.SeriesMediator
+}
+
+// Get retrieves series from the books backend
+func (c *SeriesController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	seriesMediator := c.SeriesMediatorFactory()
+	series, err := seriesMediator.Get(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// GetBooks retrieves the books of a series in reading order
+func (c *SeriesController) GetBooks(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req := translators.ToSeriesBooksRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithField("id", req.ID).WithError(err).Error("invalid request params for get series books")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	seriesMediator := c.SeriesMediatorFactory()
+	books, err := seriesMediator.GetBooks(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, books)
+}
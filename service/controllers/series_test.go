@@ -0,0 +1,170 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SeriesMediatorMock struct {
+	SeriesField  []models.Series
+	BookField    []models.Book
+	RequestField models.SeriesBooksRequest
+	ErrorField   error
+}
+
+func (m *SeriesMediatorMock) Get(ctx context.Context) ([]models.Series, error) {
+	return m.SeriesField, m.ErrorField
+}
+
+func (m *SeriesMediatorMock) GetBooks(ctx context.Context, req models.SeriesBooksRequest) ([]models.Book, error) {
+	m.RequestField = req
+	return m.BookField, m.ErrorField
+}
+
+func TestSeriesController_Get(t *testing.T) {
+	var cases = []struct {
+		name            string
+		seriesMediators mediators.SeriesMediator
+		assert          func(resp *http.Response, series []models.Series)
+	}{
+		{
+			name: "success",
+			seriesMediators: &SeriesMediatorMock{
+				SeriesField: []models.Series{
+					{ID: 1, Name: "Discworld", Description: "Comic fantasy", BookCount: 41},
+				},
+			},
+			assert: func(resp *http.Response, series []models.Series) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Len(t, series, 1)
+				assert.Equal(t, series[0].Name, "Discworld")
+				assert.Equal(t, series[0].BookCount, int64(41))
+			},
+		},
+		{
+			name: "failure",
+			seriesMediators: &SeriesMediatorMock{
+				ErrorField: errors.New("Error"),
+			},
+			assert: func(resp *http.Response, series []models.Series) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		seriesMediatorFactory := func() mediators.SeriesMediator {
+			return c.seriesMediators
+		}
+		controller := controllers.SeriesController{
+			Logger:                log.NewEntry(log.New()),
+			SeriesMediatorFactory: seriesMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/series", nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/series", controller.Get).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+		responseBody := []models.Series{}
+		err = json.Unmarshal(body, &responseBody)
+
+		c.assert(resp, responseBody)
+	}
+}
+
+func TestSeriesController_GetBooks(t *testing.T) {
+	var cases = []struct {
+		name            string
+		seriesMediators *SeriesMediatorMock
+		id              string
+		assert          func(resp *http.Response, books []translators.BookV1, mediator *SeriesMediatorMock)
+	}{
+		{
+			name: "success",
+			seriesMediators: &SeriesMediatorMock{
+				BookField: []models.Book{
+					{ID: 4, Title: "The Colour of Magic", Series: &models.BookSeries{ID: 1, Name: "Discworld", Position: 1}},
+					{ID: 5, Title: "The Light Fantastic", Series: &models.BookSeries{ID: 1, Name: "Discworld", Position: 2}},
+				},
+			},
+			id: "1",
+			assert: func(resp *http.Response, books []translators.BookV1, mediator *SeriesMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "1", mediator.RequestField.ID)
+				require.Len(t, books, 2)
+				assert.Equal(t, &models.BookSeries{ID: 1, Name: "Discworld", Position: 2}, books[1].Series)
+			},
+		},
+		{
+			name:            "not found",
+			seriesMediators: &SeriesMediatorMock{ErrorField: models.ErrNotFound},
+			id:              "99",
+			assert: func(resp *http.Response, books []translators.BookV1, mediator *SeriesMediatorMock) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:            "failure",
+			seriesMediators: &SeriesMediatorMock{ErrorField: errors.New("Error")},
+			id:              "1",
+			assert: func(resp *http.Response, books []translators.BookV1, mediator *SeriesMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+		{
+			name:            "bad request",
+			seriesMediators: &SeriesMediatorMock{},
+			id:              "first",
+			assert: func(resp *http.Response, books []translators.BookV1, mediator *SeriesMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		seriesMediatorFactory := func() mediators.SeriesMediator {
+			return c.seriesMediators
+		}
+		controller := controllers.SeriesController{
+			Logger:                log.NewEntry(log.New()),
+			SeriesMediatorFactory: seriesMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://test.com/api/v1/series/"+c.id+"/books", nil)
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/series/{id}/books", controller.GetBooks).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		resp := recorder.Result()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err, "should return a readable response body")
+		responseBody := []translators.BookV1{}
+		err = json.Unmarshal(body, &responseBody)
+
+		c.assert(resp, responseBody, c.seriesMediators)
+	}
+}
@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// ShelfController defines the controller for the shelves of the users
+type ShelfController struct {
+	Logger               *log.Entry
+	ShelfMediatorFactory func() mediators.ShelfMediator
+}
+
+// GetAll retrieves the shelves of the caller
+func (c *ShelfController) GetAll(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, ok := translators.ToUserID(r)
+	if !ok {
+		translators.ParseError(w, http.StatusUnauthorized)
+		return
+	}
+
+	shelves, err := c.ShelfMediatorFactory().GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shelves)
+}
+
+// Get retrieves a shelf of the caller along with its books
+func (c *ShelfController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().Get(r.Context(), userID, shelfID)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, shelf)
+}
+
+// Post creates a custom shelf for the caller
+func (c *ShelfController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, ok := translators.ToUserID(r)
+	if !ok {
+		translators.ParseError(w, http.StatusUnauthorized)
+		return
+	}
+
+	req, err := translators.ToShelfRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post shelf")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().Create(r.Context(), userID, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusCreated, shelf)
+}
+
+// Patch renames a custom shelf of the caller
+func (c *ShelfController) Patch(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := translators.ToShelfRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for patch shelf")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().Rename(r.Context(), userID, shelfID, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, shelf)
+}
+
+// Delete deletes a custom shelf of the caller
+func (c *ShelfController) Delete(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.ShelfMediatorFactory().Delete(r.Context(), userID, shelfID); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PostBook puts a book on a shelf of the caller
+func (c *ShelfController) PostBook(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := translators.ToShelfEntryRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post shelf book")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().AddBook(r.Context(), userID, shelfID, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusCreated, shelf)
+}
+
+// PatchBook moves a book of a shelf of the caller to another position
+func (c *ShelfController) PatchBook(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := translators.ToShelfEntryRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err == nil && req.Position == 0 {
+		err = errors.New("position is required")
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for patch shelf book")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().MoveBook(r.Context(), userID, shelfID, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	translators.EncodeBooks(w, r, http.StatusOK, shelf)
+}
+
+// DeleteBook takes a book off a shelf of the caller
+func (c *ShelfController) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+	bookID, err := translators.ToShelfBookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for delete shelf book")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	if err := c.ShelfMediatorFactory().RemoveBook(r.Context(), userID, shelfID, bookID); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Export downloads a shelf of the caller as JSON or CSV
+func (c *ShelfController) Export(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+	format, err := translators.ToExportFormat(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for export shelf")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	shelf, err := c.ShelfMediatorFactory().Get(r.Context(), userID, shelfID)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	if err := translators.WriteShelfExport(w, r, shelf, format); err != nil {
+		c.Logger.WithError(err).Error("error exporting shelf")
+	}
+}
+
+// Share creates the public read-only link of a shelf of the caller
+func (c *ShelfController) Share(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := c.ShelfMediatorFactory().Share(r.Context(), userID, shelfID)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translators.ToShelfShare(r, token))
+}
+
+// Unshare revokes the public link of a shelf of the caller
+func (c *ShelfController) Unshare(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	userID, shelfID, ok := c.toShelf(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.ShelfMediatorFactory().Unshare(r.Context(), userID, shelfID); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetShared retrieves a shared shelf, anyone holding its link can read it
+func (c *ShelfController) GetShared(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	shelf, err := c.ShelfMediatorFactory().GetShared(r.Context(), translators.ToShareToken(r))
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+	shelf.ShareToken = nil
+
+	translators.EncodeBooks(w, r, http.StatusOK, shelf)
+}
+
+// toShelf returns the caller and the shelf in the path, it writes the error response when ok is false
+func (c *ShelfController) toShelf(w http.ResponseWriter, r *http.Request) (userID, shelfID int64, ok bool) {
+	userID, ok = translators.ToUserID(r)
+	if !ok {
+		translators.ParseError(w, http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	shelfID, err := translators.ToShelfID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for shelf")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return 0, 0, false
+	}
+
+	return userID, shelfID, true
+}
@@ -0,0 +1,371 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ShelfMediatorMock struct {
+	ShelvesField []models.Shelf
+	ShelfField   models.Shelf
+	TokenField   string
+	UserIDField  int64
+	ShelfIDField int64
+	RequestField models.ShelfRequest
+	EntryField   models.ShelfEntryRequest
+	ErrorField   error
+}
+
+func (m *ShelfMediatorMock) GetAll(ctx context.Context, userID int64) ([]models.Shelf, error) {
+	m.UserIDField = userID
+	return m.ShelvesField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Get(ctx context.Context, userID, shelfID int64) (models.Shelf, error) {
+	m.UserIDField, m.ShelfIDField = userID, shelfID
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) GetShared(ctx context.Context, token string) (models.Shelf, error) {
+	m.TokenField = token
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Create(ctx context.Context, userID int64, req models.ShelfRequest) (models.Shelf, error) {
+	m.UserIDField, m.RequestField = userID, req
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Rename(ctx context.Context, userID, shelfID int64, req models.ShelfRequest) (models.Shelf, error) {
+	m.UserIDField, m.ShelfIDField, m.RequestField = userID, shelfID, req
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Delete(ctx context.Context, userID, shelfID int64) error {
+	m.UserIDField, m.ShelfIDField = userID, shelfID
+	return m.ErrorField
+}
+
+func (m *ShelfMediatorMock) AddBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error) {
+	m.UserIDField, m.ShelfIDField, m.EntryField = userID, shelfID, req
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) MoveBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error) {
+	m.UserIDField, m.ShelfIDField, m.EntryField = userID, shelfID, req
+	return m.ShelfField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) RemoveBook(ctx context.Context, userID, shelfID, bookID int64) error {
+	m.UserIDField, m.ShelfIDField, m.EntryField = userID, shelfID, models.ShelfEntryRequest{BookID: bookID}
+	return m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Share(ctx context.Context, userID, shelfID int64) (string, error) {
+	m.UserIDField, m.ShelfIDField = userID, shelfID
+	return m.TokenField, m.ErrorField
+}
+
+func (m *ShelfMediatorMock) Unshare(ctx context.Context, userID, shelfID int64) error {
+	m.UserIDField, m.ShelfIDField = userID, shelfID
+	return m.ErrorField
+}
+
+func TestShelfController(t *testing.T) {
+	token := "secret"
+	shelf := models.Shelf{
+		ID:         3,
+		Name:       "Favourites",
+		Kind:       models.ShelfCustom,
+		ShareToken: &token,
+		Books: []models.ShelfEntry{
+			{
+				Book: models.Book{
+					ID:      5,
+					Title:   "Adventures of Kaya, Part 2",
+					Authors: []models.BookAuthor{{Author: models.Author{FirstName: "Kaya", LastName: "Smith"}, Role: models.RoleAuthor}},
+					Genres:  []models.Genre{{Title: "Fiction"}},
+				},
+				Position: 1,
+			},
+		},
+	}
+
+	var cases = []struct {
+		name           string
+		shelfMediators *ShelfMediatorMock
+		method         string
+		path           string
+		body           string
+		userID         string
+		assert         func(resp *http.Response, mediator *ShelfMediatorMock)
+	}{
+		{
+			name:           "get all - success",
+			shelfMediators: &ShelfMediatorMock{ShelvesField: []models.Shelf{shelf}},
+			method:         http.MethodGet,
+			path:           "/me/shelves",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(42), mediator.UserIDField)
+				var shelves []models.Shelf
+				require.Nil(t, json.NewDecoder(resp.Body).Decode(&shelves))
+				assert.Len(t, shelves, 1)
+			},
+		},
+		{
+			name:           "get all - unauthorized",
+			shelfMediators: &ShelfMediatorMock{},
+			method:         http.MethodGet,
+			path:           "/me/shelves",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:           "get - not found",
+			shelfMediators: &ShelfMediatorMock{ErrorField: models.ErrNotFound},
+			method:         http.MethodGet,
+			path:           "/me/shelves/9",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, int64(9), mediator.ShelfIDField)
+			},
+		},
+		{
+			name:           "get - invalid shelf",
+			shelfMediators: &ShelfMediatorMock{},
+			method:         http.MethodGet,
+			path:           "/me/shelves/abc",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:           "post - success",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodPost,
+			path:           "/me/shelves",
+			body:           `{"name":"Favourites"}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, "Favourites", mediator.RequestField.Name)
+			},
+		},
+		{
+			name:           "post - conflict",
+			shelfMediators: &ShelfMediatorMock{ErrorField: models.ErrConflict},
+			method:         http.MethodPost,
+			path:           "/me/shelves",
+			body:           `{"name":"Read"}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:           "post - missing name",
+			shelfMediators: &ShelfMediatorMock{},
+			method:         http.MethodPost,
+			path:           "/me/shelves",
+			body:           `{"title":"Favourites"}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:           "patch - success",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodPatch,
+			path:           "/me/shelves/3",
+			body:           `{"name":"Classics"}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, int64(3), mediator.ShelfIDField)
+				assert.Equal(t, "Classics", mediator.RequestField.Name)
+			},
+		},
+		{
+			name:           "delete - built-in shelf",
+			shelfMediators: &ShelfMediatorMock{ErrorField: models.ErrConflict},
+			method:         http.MethodDelete,
+			path:           "/me/shelves/1",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:           "post book - success",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodPost,
+			path:           "/me/shelves/3/books",
+			body:           `{"bookId":5,"position":1}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, models.ShelfEntryRequest{BookID: 5, Position: 1}, mediator.EntryField)
+			},
+		},
+		{
+			name:           "post book - unknown book",
+			shelfMediators: &ShelfMediatorMock{ErrorField: models.ErrNotFound},
+			method:         http.MethodPost,
+			path:           "/me/shelves/3/books",
+			body:           `{"bookId":999}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:           "patch book - success",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodPatch,
+			path:           "/me/shelves/3/books/5",
+			body:           `{"position":2}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, models.ShelfEntryRequest{BookID: 5, Position: 2}, mediator.EntryField)
+			},
+		},
+		{
+			name:           "patch book - missing position",
+			shelfMediators: &ShelfMediatorMock{},
+			method:         http.MethodPatch,
+			path:           "/me/shelves/3/books/5",
+			body:           `{}`,
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:           "delete book - success",
+			shelfMediators: &ShelfMediatorMock{},
+			method:         http.MethodDelete,
+			path:           "/me/shelves/3/books/5",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.Equal(t, int64(5), mediator.EntryField.BookID)
+			},
+		},
+		{
+			name:           "export - csv",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodGet,
+			path:           "/me/shelves/3/export?format=csv",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="shelf-3.csv"`, resp.Header.Get("Content-Disposition"))
+				records, err := csv.NewReader(resp.Body).ReadAll()
+				require.Nil(t, err)
+				require.Len(t, records, 2)
+				assert.Equal(t, []string{"1", "5", "Adventures of Kaya, Part 2", "Kaya Smith", "Fiction"}, records[1][:5])
+			},
+		},
+		{
+			name:           "export - unknown format",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodGet,
+			path:           "/me/shelves/3/export?format=xml",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:           "share - success",
+			shelfMediators: &ShelfMediatorMock{TokenField: token},
+			method:         http.MethodPost,
+			path:           "/me/shelves/3/share",
+			userID:         "42",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				var share models.ShelfShare
+				require.Nil(t, json.NewDecoder(resp.Body).Decode(&share))
+				assert.Equal(t, models.ShelfShare{Token: token, URL: "http://test.com/api/v1/shared/shelves/secret"}, share)
+			},
+		},
+		{
+			name:           "get shared - success",
+			shelfMediators: &ShelfMediatorMock{ShelfField: shelf},
+			method:         http.MethodGet,
+			path:           "/shared/shelves/secret",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, token, mediator.TokenField)
+				var shared models.Shelf
+				require.Nil(t, json.NewDecoder(resp.Body).Decode(&shared))
+				assert.Nil(t, shared.ShareToken, "the token should not be exposed")
+				assert.Len(t, shared.Books, 1)
+			},
+		},
+		{
+			name:           "get shared - failure",
+			shelfMediators: &ShelfMediatorMock{ErrorField: errors.New("Error")},
+			method:         http.MethodGet,
+			path:           "/shared/shelves/secret",
+			assert: func(resp *http.Response, mediator *ShelfMediatorMock) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+	for _, c := range cases {
+		shelfMediatorFactory := func() mediators.ShelfMediator {
+			return c.shelfMediators
+		}
+		controller := controllers.ShelfController{
+			Logger:               log.NewEntry(log.New()),
+			ShelfMediatorFactory: shelfMediatorFactory,
+		}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(c.method, "http://test.com/api/v1"+c.path, strings.NewReader(c.body))
+		if c.userID != "" {
+			request.Header.Set("X-User-ID", c.userID)
+		}
+
+		router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+		router.HandleFunc("/me/shelves", controller.GetAll).Methods(http.MethodGet)
+		router.HandleFunc("/me/shelves", controller.Post).Methods(http.MethodPost)
+		router.HandleFunc("/me/shelves/{shelfId}", controller.Get).Methods(http.MethodGet)
+		router.HandleFunc("/me/shelves/{shelfId}", controller.Patch).Methods(http.MethodPatch)
+		router.HandleFunc("/me/shelves/{shelfId}", controller.Delete).Methods(http.MethodDelete)
+		router.HandleFunc("/me/shelves/{shelfId}/books", controller.PostBook).Methods(http.MethodPost)
+		router.HandleFunc("/me/shelves/{shelfId}/books/{bookId}", controller.PatchBook).Methods(http.MethodPatch)
+		router.HandleFunc("/me/shelves/{shelfId}/books/{bookId}", controller.DeleteBook).Methods(http.MethodDelete)
+		router.HandleFunc("/me/shelves/{shelfId}/export", controller.Export).Methods(http.MethodGet)
+		router.HandleFunc("/me/shelves/{shelfId}/share", controller.Share).Methods(http.MethodPost)
+		router.HandleFunc("/me/shelves/{shelfId}/share", controller.Unshare).Methods(http.MethodDelete)
+		router.HandleFunc("/shared/shelves/{token}", controller.GetShared).Methods(http.MethodGet)
+
+		router.ServeHTTP(recorder, request)
+
+		c.assert(recorder.Result(), c.shelfMediators)
+	}
+}
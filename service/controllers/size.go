@@ -1,12 +1,12 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -21,13 +21,115 @@ func (c *SizeController) Get(w http.ResponseWriter, r *http.Request) {
 	c.Logger.WithField("url", r.URL).Info("request")
 
 	sizeMediator := c.SizeMediatorFactory()
-	sizes, err := sizeMediator.Get(context.Background())
+	sizes, err := sizeMediator.Get(r.Context())
 	if err != nil {
-		c.Logger.WithError(err).Error("internal server error")
-		translators.ParseError(w, http.StatusInternalServerError)
+		writeError(c.Logger, w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(sizes)
 }
+
+// GetV2 retrieves sizes from the books backend in the envelope of the v2 API
+func (c *SizeController) GetV2(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	sizeMediator := c.SizeMediatorFactory()
+	sizes, err := sizeMediator.Get(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	meta := models.Meta{Total: int64(len(sizes))}
+	translators.EncodeEnvelope(w, r, http.StatusOK, sizes, meta, nil)
+}
+
+// GetAll retrieves every size, the retired ones included, for the admins
+func (c *SizeController) GetAll(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	sizes, err := c.SizeMediatorFactory().GetAll(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sizes)
+}
+
+// Post creates a size
+func (c *SizeController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, err := translators.ToSizeRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post size")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	size, err := c.SizeMediatorFactory().Create(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(size)
+}
+
+// Put replaces a size
+func (c *SizeController) Put(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBucketID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for put size")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+	req, err := translators.ToSizeRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for put size")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	size, err := c.SizeMediatorFactory().Update(r.Context(), id, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(size)
+}
+
+// Delete deletes a size
+func (c *SizeController) Delete(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, err := translators.ToBucketID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for delete size")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	if err := c.SizeMediatorFactory().Delete(r.Context(), id); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
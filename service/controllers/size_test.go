@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/book-recommendations/service/controllers"
@@ -19,14 +20,39 @@ import (
 )
 
 type SizeMediatorMock struct {
-	SizeField  []models.Size
-	ErrorField error
+	SizeField    []models.Size
+	RequestField *models.SizeRequest
+	ErrorField   error
 }
 
 func (m *SizeMediatorMock) Get(ctx context.Context) ([]models.Size, error) {
 	return m.SizeField, m.ErrorField
 }
 
+func (m *SizeMediatorMock) GetAll(ctx context.Context) ([]models.Size, error) {
+	return m.SizeField, m.ErrorField
+}
+
+func (m *SizeMediatorMock) Create(ctx context.Context, req models.SizeRequest) (models.Size, error) {
+	m.RequestField = &req
+	if m.ErrorField != nil {
+		return models.Size{}, m.ErrorField
+	}
+	return req.Size(9), nil
+}
+
+func (m *SizeMediatorMock) Update(ctx context.Context, id int64, req models.SizeRequest) (models.Size, error) {
+	m.RequestField = &req
+	if m.ErrorField != nil {
+		return models.Size{}, m.ErrorField
+	}
+	return req.Size(id), nil
+}
+
+func (m *SizeMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
 func TestSizeController_Get(t *testing.T) {
 	MaxPages1 := int64(34)
 	MinPages1 := int64(35)
@@ -109,3 +135,119 @@ func TestSizeController_Get(t *testing.T) {
 		c.assert(resp, responseBody)
 	}
 }
+
+func TestSizeController_Post(t *testing.T) {
+	var cases = []struct {
+		name     string
+		body     string
+		mediator *SizeMediatorMock
+		assert   func(resp *http.Response, body string, mediator *SizeMediatorMock)
+	}{
+		{
+			name:     "success",
+			body:     `{"title":"Epic – 1500 pages and up","minPages":1500,"sortOrder":8}`,
+			mediator: &SizeMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *SizeMediatorMock) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.JSONEq(t, `{"id":9,"title":"Epic – 1500 pages and up","minPages":1500,"sortOrder":8,"active":true}`, body)
+			},
+		},
+		{
+			name:     "inverted bounds",
+			body:     `{"title":"Backwards","minPages":500,"maxPages":200}`,
+			mediator: &SizeMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *SizeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should not be less than minPages")
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "bound out of range",
+			body:     `{"title":"Nothing","minPages":0}`,
+			mediator: &SizeMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *SizeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be between 1 and 10000")
+			},
+		},
+		{
+			name:     "unknown field",
+			body:     `{"title":"Epic","min":1500}`,
+			mediator: &SizeMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *SizeMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "overlap",
+			body:     `{"title":"Epic – 450 pages and up","minPages":450}`,
+			mediator: &SizeMediatorMock{ErrorField: models.NewError(models.KindConflict, "the pages overlap the ones of size 5")},
+			assert: func(resp *http.Response, body string, mediator *SizeMediatorMock) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Contains(t, body, "size 5")
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.SizeController{
+				Logger:              log.NewEntry(log.New()),
+				SizeMediatorFactory: func() mediators.SizeMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v1/admin/sizes", strings.NewReader(c.body))
+			controller.Post(recorder, request)
+
+			resp := recorder.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err, "should return a readable response body")
+			c.assert(resp, string(body), c.mediator)
+		})
+	}
+}
+
+func TestSizeController_Delete(t *testing.T) {
+	var cases = []struct {
+		name     string
+		url      string
+		mediator *SizeMediatorMock
+		status   int
+	}{
+		{
+			name:     "success",
+			url:      "http://test.com/api/v1/admin/sizes/3",
+			mediator: &SizeMediatorMock{},
+			status:   http.StatusNoContent,
+		},
+		{
+			name:     "not found",
+			url:      "http://test.com/api/v1/admin/sizes/30",
+			mediator: &SizeMediatorMock{ErrorField: models.NewError(models.KindNotFound, "size 30 not found")},
+			status:   http.StatusNotFound,
+		},
+		{
+			name:     "invalid id",
+			url:      "http://test.com/api/v1/admin/sizes/0",
+			mediator: &SizeMediatorMock{},
+			status:   http.StatusBadRequest,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.SizeController{
+				Logger:              log.NewEntry(log.New()),
+				SizeMediatorFactory: func() mediators.SizeMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			router := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
+			router.HandleFunc("/admin/sizes/{id}", controller.Delete).Methods(http.MethodDelete)
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, c.url, nil))
+
+			assert.Equal(t, c.status, recorder.Code)
+		})
+	}
+}
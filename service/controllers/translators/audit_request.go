@@ -0,0 +1,29 @@
+package translators
+
+import (
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const (
+	auditEntityParam string = "entity" //string
+	auditIDParam     string = "id"     //integer
+	auditActorParam  string = "actor"  //string
+	auditSinceParam  string = "since"  //string
+	auditUntilParam  string = "until"  //string
+)
+
+// ToAuditRequest creates the AuditRequest model from the data in the request
+func ToAuditRequest(r *http.Request) models.AuditRequest {
+	query := r.URL.Query()
+	return models.AuditRequest{
+		Entity: query.Get(auditEntityParam),
+		ID:     query.Get(auditIDParam),
+		Actor:  query.Get(auditActorParam),
+		Since:  query.Get(auditSinceParam),
+		Until:  query.Get(auditUntilParam),
+		Limit:  query.Get(limitParam),
+		Offset: query.Get(offsetParam),
+	}
+}
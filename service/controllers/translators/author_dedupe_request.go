@@ -0,0 +1,39 @@
+package translators
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const (
+	minSimilarityParam string = "min-similarity" //number
+
+	// maxMergeBodySize is the maximum size in bytes of the body of a merge request
+	maxMergeBodySize = 1024
+)
+
+// ToAuthorID returns the ID of the author in the path of the request
+func ToAuthorID(r *http.Request) (int64, error) {
+	return toPathID(r, idVar)
+}
+
+// ToDuplicateAuthorsRequest creates the DuplicateAuthorsRequest model from the data in the request
+func ToDuplicateAuthorsRequest(r *http.Request) models.DuplicateAuthorsRequest {
+	query := r.URL.Query()
+	return models.DuplicateAuthorsRequest{
+		MinSimilarity: query.Get(minSimilarityParam),
+		Limit:         query.Get(limitParam),
+	}
+}
+
+// ToMergeAuthorRequest creates the MergeAuthorRequest model from the JSON body of the request
+func ToMergeAuthorRequest(w http.ResponseWriter, r *http.Request) (models.MergeAuthorRequest, error) {
+	var req models.MergeAuthorRequest
+	if err := decodeStrict(w, r, &req, maxMergeBodySize); err != nil {
+		return models.MergeAuthorRequest{}, fmt.Errorf("invalid merge body: %w", err)
+	}
+
+	return req, nil
+}
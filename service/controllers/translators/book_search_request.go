@@ -0,0 +1,58 @@
+package translators
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/book-recommendations/service/models"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// maxSearchBodySize is the maximum size in bytes of the body of a search of books,
+// large enough for thousands of IDs
+const maxSearchBodySize = 64 << 10
+
+// ToBookSearchRequest creates the BookSearchRequest model from the JSON body of the request.
+// Unknown fields and values of the wrong type are reported as validation.Errors by field.
+func ToBookSearchRequest(w http.ResponseWriter, r *http.Request) (models.BookSearchRequest, error) {
+	var req models.BookSearchRequest
+	err := decodeStrict(w, r, &req, maxSearchBodySize)
+	if err == nil {
+		return req, nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return models.BookSearchRequest{}, validation.Errors{
+			typeErr.Field: errors.New("should be of type " + jsonType(typeErr.Type.String())),
+		}
+	}
+	// the decoder has no typed error for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return models.BookSearchRequest{}, validation.Errors{
+			strings.Trim(field, `"`): errors.New("is not a known field"),
+		}
+	}
+
+	return models.BookSearchRequest{}, err
+}
+
+// jsonType returns the JSON name of a Go type of the search request
+func jsonType(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	case strings.Contains(goType, "int"):
+		return "integer"
+	case strings.Contains(goType, "float"):
+		return "number"
+	case strings.Contains(goType, "bool"):
+		return "boolean"
+	case strings.Contains(goType, "string"):
+		return "string"
+	default:
+		return "object"
+	}
+}
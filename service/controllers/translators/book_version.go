@@ -0,0 +1,147 @@
+package translators
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const (
+	// MediaTypeV1 is the shape of the books with their primary author and genre only,
+	// served unless the client asks for another one
+	MediaTypeV1 string = "application/vnd.readcommend.v1+json"
+	// MediaTypeV2 is the shape of the books with every author, along with their role, and every genre
+	MediaTypeV2 string = "application/vnd.readcommend.v2+json"
+)
+
+// BookV1 is the shape of a book before books had several authors and genres. The nil
+// Authors and Genres fields hide the lists of the embedded book from the JSON encoding.
+type BookV1 struct {
+	models.Book
+	Authors *struct{}     `json:"authors,omitempty"`
+	Genres  *struct{}     `json:"genres,omitempty"`
+	Genre   models.Genre  `json:"genre"`
+	Author  models.Author `json:"author"`
+}
+
+// SimilarBookV1 is the v1 shape of a similar book
+type SimilarBookV1 struct {
+	BookV1
+	Score float64 `json:"score"`
+}
+
+// RecommendationV1 is the v1 shape of a recommendation
+type RecommendationV1 struct {
+	BookV1
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// FacetedBooksV1 is the v1 shape of books along with their facets
+type FacetedBooksV1 struct {
+	Books  []BookV1      `json:"books"`
+	Facets models.Facets `json:"facets"`
+}
+
+// ShelfV1 is the v1 shape of a shelf along with its books
+type ShelfV1 struct {
+	models.Shelf
+	Books []ShelfEntryV1 `json:"books,omitempty"`
+}
+
+// ShelfEntryV1 is the v1 shape of a book on a shelf
+type ShelfEntryV1 struct {
+	BookV1
+	Position int64     `json:"position"`
+	AddedAt  time.Time `json:"addedAt"`
+}
+
+// ToMediaType returns the shape of the books asked in the Accept header of the request
+func ToMediaType(r *http.Request) string {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), MediaTypeV2) {
+			return MediaTypeV2
+		}
+	}
+	return MediaTypeV1
+}
+
+// EncodeBooks writes the response holding books in the shape asked by the request
+func EncodeBooks(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	mediaType := ToMediaType(r)
+	w.Header().Add("Vary", "Accept")
+	if mediaType == MediaTypeV2 {
+		w.Header().Set("Content-Type", MediaTypeV2)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ToVersion(mediaType, v))
+}
+
+// ToVersion converts the books held by v to the given shape
+func ToVersion(mediaType string, v interface{}) interface{} {
+	if mediaType == MediaTypeV2 {
+		return v
+	}
+
+	switch value := v.(type) {
+	case models.Book:
+		return ToBookV1(value)
+	case []models.Book:
+		books := make([]BookV1, 0, len(value))
+		for _, book := range value {
+			books = append(books, ToBookV1(book))
+		}
+		return books
+	case models.FacetedBooks:
+		return FacetedBooksV1{
+			Books:  ToVersion(mediaType, value.Books).([]BookV1),
+			Facets: value.Facets,
+		}
+	case []models.SimilarBook:
+		books := make([]SimilarBookV1, 0, len(value))
+		for _, book := range value {
+			books = append(books, SimilarBookV1{BookV1: ToBookV1(book.Book), Score: book.Score})
+		}
+		return books
+	case []models.Recommendation:
+		recommendations := make([]RecommendationV1, 0, len(value))
+		for _, recommendation := range value {
+			recommendations = append(recommendations, RecommendationV1{
+				BookV1: ToBookV1(recommendation.Book),
+				Score:  recommendation.Score,
+				Reason: recommendation.Reason,
+			})
+		}
+		return recommendations
+	case models.Shelf:
+		shelf := ShelfV1{Shelf: value}
+		if value.Books != nil {
+			shelf.Books = make([]ShelfEntryV1, 0, len(value.Books))
+		}
+		for _, entry := range value.Books {
+			shelf.Books = append(shelf.Books, ShelfEntryV1{
+				BookV1:   ToBookV1(entry.Book),
+				Position: entry.Position,
+				AddedAt:  entry.AddedAt,
+			})
+		}
+		return shelf
+	default:
+		return v
+	}
+}
+
+// ToBookV1 converts a book to its v1 shape
+func ToBookV1(book models.Book) BookV1 {
+	return BookV1{
+		Book:   book,
+		Genre:  book.PrimaryGenre(),
+		Author: book.PrimaryAuthor(),
+	}
+}
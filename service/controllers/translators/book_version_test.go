@@ -0,0 +1,80 @@
+package translators_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMediaType(t *testing.T) {
+	var cases = []struct {
+		name   string
+		accept string
+		assert func(mediaType string)
+	}{
+		{
+			name: "success - no accept header",
+			assert: func(mediaType string) {
+				assert.Equal(t, translators.MediaTypeV1, mediaType)
+			},
+		},
+		{
+			name:   "success - any media type",
+			accept: "*/*",
+			assert: func(mediaType string) {
+				assert.Equal(t, translators.MediaTypeV1, mediaType)
+			},
+		},
+		{
+			name:   "success - v2 among others",
+			accept: "application/json;q=0.9, application/vnd.readcommend.v2+json",
+			assert: func(mediaType string) {
+				assert.Equal(t, translators.MediaTypeV2, mediaType)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		req := http.Request{Header: http.Header{}}
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		c.assert(translators.ToMediaType(&req))
+	}
+}
+
+func TestToVersion(t *testing.T) {
+	book := models.Book{
+		ID:     7,
+		Genres: []models.Genre{{ID: 4, Title: "Classics"}, {ID: 5, Title: "Romance"}},
+		Authors: []models.BookAuthor{
+			{Author: models.Author{ID: 20, LastName: "Translator"}, Role: models.RoleTranslator},
+			{Author: models.Author{ID: 21, LastName: "Writer"}, Role: models.RoleAuthor},
+		},
+	}
+
+	books := translators.ToVersion(translators.MediaTypeV1, []models.Book{book}).([]translators.BookV1)
+	assert.Len(t, books, 1)
+	assert.Equal(t, models.Genre{ID: 4, Title: "Classics"}, books[0].Genre)
+	assert.Equal(t, int64(21), books[0].Author.ID, "the primary author should be the first one with the author role")
+
+	similar := translators.ToVersion(translators.MediaTypeV1, []models.SimilarBook{{Book: book, Score: 2}}).([]translators.SimilarBookV1)
+	assert.Equal(t, 2.0, similar[0].Score)
+	assert.Equal(t, int64(21), similar[0].Author.ID)
+
+	shelf := translators.ToVersion(translators.MediaTypeV1, models.Shelf{ID: 1, Books: []models.ShelfEntry{{Book: book, Position: 1}}}).(translators.ShelfV1)
+	assert.Equal(t, int64(1), shelf.Books[0].Position)
+	assert.Equal(t, int64(4), shelf.Books[0].Genre.ID)
+
+	faceted := translators.ToVersion(translators.MediaTypeV1, models.FacetedBooks{
+		Books:  []models.Book{book},
+		Facets: models.Facets{models.FacetGenres: {{ID: 4, Count: 1}}},
+	}).(translators.FacetedBooksV1)
+	assert.Equal(t, int64(21), faceted.Books[0].Author.ID)
+	assert.Equal(t, int64(1), faceted.Facets[models.FacetGenres][0].Count)
+
+	assert.Equal(t, []models.Book{book}, translators.ToVersion(translators.MediaTypeV2, []models.Book{book}))
+}
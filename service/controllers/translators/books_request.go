@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
 )
 
 const (
@@ -14,6 +15,23 @@ const (
 	minYearParam  string = "min-year"  //integer
 	maxYearParam  string = "max-year"  //integer
 	limitParam    string = "limit"     //integer
+	offsetParam   string = "offset"    //integer
+	rankingParam  string = "ranking"   //string
+	rankerParam   string = "ranker"    //string
+	isbnParam     string = "isbn"      //string
+	languageParam string = "language"  //string
+
+	excludeAuthorsParam string = "exclude-authors" //string
+	excludeGenresParam  string = "exclude-genres"  //string
+	minRatingParam      string = "min-rating"      //number
+	genreMatchParam     string = "genre-match"     //string
+	facetsParam         string = "facets"          //string
+
+	firstInSeriesOnlyParam string = "first-in-series-only" //boolean
+	excludeReadParam       string = "exclude-read"         //boolean
+	statsParam             string = "stats"                //boolean
+
+	idVar string = "id" //integer
 )
 
 // ToBookRequest creates the BookRequest model from the data in the request
@@ -28,5 +46,63 @@ func ToBooksRequest(r *http.Request) models.BookRequest {
 		MinYear:  query.Get(minYearParam),
 		MaxYear:  query.Get(maxYearParam),
 		Limit:    query.Get(limitParam),
+		Offset:   query.Get(offsetParam),
+		Ranking:  query.Get(rankingParam),
+		Ranker:   query.Get(rankerParam),
+		ISBN:     query.Get(isbnParam),
+
+		Languages: query.Get(languageParam),
+
+		ExcludeAuthors: query.Get(excludeAuthorsParam),
+		ExcludeGenres:  query.Get(excludeGenresParam),
+		MinRating:      query.Get(minRatingParam),
+		GenreMatch:     query.Get(genreMatchParam),
+		Facets:         query.Get(facetsParam),
+
+		FirstInSeriesOnly: query.Get(firstInSeriesOnlyParam),
+		ExcludeRead:       query.Get(excludeReadParam),
+	}
+}
+
+// ToBookID returns the ID of the book in the path of the request
+func ToBookID(r *http.Request) (int64, error) {
+	return toPathID(r, idVar)
+}
+
+// ToSimilarBooksRequest creates the SimilarBooksRequest model from the data in the request
+func ToSimilarBooksRequest(r *http.Request) models.SimilarBooksRequest {
+	return models.SimilarBooksRequest{
+		ID:    mux.Vars(r)[idVar],
+		Limit: r.URL.Query().Get(limitParam),
+	}
+}
+
+// ToSeriesBooksRequest creates the SeriesBooksRequest model from the data in the request
+func ToSeriesBooksRequest(r *http.Request) models.SeriesBooksRequest {
+	return models.SeriesBooksRequest{
+		ID: mux.Vars(r)[idVar],
+	}
+}
+
+// ToGenresRequest creates the GenresRequest model from the data in the request
+func ToGenresRequest(r *http.Request) models.GenresRequest {
+	return models.GenresRequest{
+		Stats: r.URL.Query().Get(statsParam),
+	}
+}
+
+// ToAuthorRequest creates the AuthorRequest model from the data in the request
+func ToAuthorRequest(r *http.Request) models.AuthorRequest {
+	return models.AuthorRequest{
+		ID: mux.Vars(r)[idVar],
+	}
+}
+
+// ToAuthorBooksRequest creates the AuthorBooksRequest model from the data in the request,
+// the books being filtered by the same parameters as every list of books
+func ToAuthorBooksRequest(r *http.Request) models.AuthorBooksRequest {
+	return models.AuthorBooksRequest{
+		ID:    mux.Vars(r)[idVar],
+		Books: ToBooksRequest(r),
 	}
 }
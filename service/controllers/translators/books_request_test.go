@@ -7,6 +7,7 @@ import (
 
 	"github.com/book-recommendations/service/controllers/translators"
 	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,6 +71,153 @@ func TestToBooksRequest(t *testing.T) {
 				assert.Equal(t, err.Error(), "limit: should be between 1 and 1000.")
 			},
 		},
+		{
+			name: "success - weighted ranking",
+			url:  "ranking=weighted",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, resp.Ranking, models.RankingWeighted)
+			},
+		},
+		{
+			name: "failure - invalid ranking",
+			url:  "ranking=popular",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "ranking: should be one of: raw, weighted.")
+			},
+		},
+		{
+			name: "failure - invalid ranker",
+			url:  "ranker=popular",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "ranker: should be one of: rating, weighted, recency, random.")
+			},
+		},
+		{
+			name: "success - exclusions and genre match",
+			url:  "genres=1,2&genre-match=all&exclude-authors=3,4&exclude-genres=5&min-rating=3.5",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, resp.ExcludeAuthors, "3,4")
+				assert.Equal(t, resp.ExcludeGenres, "5")
+				assert.Equal(t, resp.MinRating, "3.5")
+				assert.Equal(t, resp.GenreMatch, "all")
+			},
+		},
+		{
+			name: "failure - invalid exclusions",
+			url:  "exclude-authors=romance&exclude-genres=1,,2",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "exclude-authors: should be for exaple: 123,456,789; exclude-genres: should be for exaple: 123,456,789.")
+			},
+		},
+		{
+			name: "failure - invalid min rating",
+			url:  "min-rating=5.5",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "min-rating: should be between 0 and 5.")
+			},
+		},
+		{
+			name: "failure - non numeric min rating",
+			url:  "min-rating=high",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "min-rating: should be numeric.")
+			},
+		},
+		{
+			name: "failure - invalid genre match",
+			url:  "genre-match=some",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "genre-match: should be one of: any, all.")
+			},
+		},
+		{
+			name: "failure - invalid offset",
+			url:  "limit=10&offset=-10",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "offset: should be between 0 and 10000.")
+			},
+		},
+		{
+			name: "success - facets",
+			url:  "facets=authors,genres,sizes,eras",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, resp.Facets, "authors,genres,sizes,eras")
+			},
+		},
+		{
+			name: "failure - invalid facets",
+			url:  "facets=authors,,years",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "facets: should be a list of: authors, genres, sizes, eras.")
+			},
+		},
+		{
+			name: "success - isbn and languages",
+			url:  "isbn=978-0-306-40615-7&language=en,pt-BR",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, resp.ISBN, "978-0-306-40615-7")
+				assert.Equal(t, resp.Languages, "en,pt-BR")
+			},
+		},
+		{
+			name: "success - isbn-10 with check digit x",
+			url:  "isbn=080442957X",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name: "failure - invalid isbn checksum",
+			url:  "isbn=9780306406158",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "isbn: should be a valid ISBN-10 or ISBN-13.")
+			},
+		},
+		{
+			name: "failure - invalid language",
+			url:  "language=english!",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "language: should be for example: en,pt-BR.")
+			},
+		},
+		{
+			name: "failure - invalid first in series only",
+			url:  "first-in-series-only=1",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "first-in-series-only: should be one of: true, false.")
+			},
+		},
+		{
+			name: "success - exclude read",
+			url:  "exclude-read=true",
+			assert: func(resp models.BookRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, resp.ExcludeRead, "true")
+			},
+		},
+		{
+			name: "failure - invalid exclude read",
+			url:  "exclude-read=yes",
+			assert: func(resp models.BookRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "exclude-read: should be one of: true, false.")
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -81,3 +229,49 @@ func TestToBooksRequest(t *testing.T) {
 		c.assert(bookReq, err)
 	}
 }
+
+func TestToSimilarBooksRequest(t *testing.T) {
+	cases := []struct {
+		name   string
+		id     string
+		url    string
+		assert func(resp models.SimilarBooksRequest, err error)
+	}{
+		{
+			name: "success",
+			id:   "12",
+			url:  "limit=5",
+			assert: func(resp models.SimilarBooksRequest, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "12", resp.ID)
+				assert.Equal(t, "5", resp.Limit)
+			},
+		},
+		{
+			name: "failure - invalid id",
+			id:   "abc",
+			assert: func(resp models.SimilarBooksRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "id: should be numeric.")
+			},
+		},
+		{
+			name: "failure - invalid limit",
+			id:   "12",
+			url:  "limit=1001",
+			assert: func(resp models.SimilarBooksRequest, err error) {
+				assert.NotNil(t, err)
+				assert.Equal(t, err.Error(), "limit: should be between 1 and 1000.")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		req := mux.SetURLVars(&http.Request{
+			URL: &url.URL{RawQuery: c.url},
+		}, map[string]string{"id": c.id})
+		similarReq := translators.ToSimilarBooksRequest(req)
+		err := similarReq.Validate()
+		c.assert(similarReq, err)
+	}
+}
@@ -0,0 +1,36 @@
+package translators
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+// maxBucketBodySize is the maximum size in bytes of the body of a size or era request
+const maxBucketBodySize = 4096
+
+// ToBucketID returns the ID of the size or era in the path of the request
+func ToBucketID(r *http.Request) (int64, error) {
+	return toPathID(r, idVar)
+}
+
+// ToSizeRequest creates the SizeRequest model from the JSON body of the request
+func ToSizeRequest(w http.ResponseWriter, r *http.Request) (models.SizeRequest, error) {
+	var req models.SizeRequest
+	if err := decodeStrict(w, r, &req, maxBucketBodySize); err != nil {
+		return models.SizeRequest{}, fmt.Errorf("invalid size body: %w", err)
+	}
+
+	return req, nil
+}
+
+// ToEraRequest creates the EraRequest model from the JSON body of the request
+func ToEraRequest(w http.ResponseWriter, r *http.Request) (models.EraRequest, error) {
+	var req models.EraRequest
+	if err := decodeStrict(w, r, &req, maxBucketBodySize); err != nil {
+		return models.EraRequest{}, fmt.Errorf("invalid era body: %w", err)
+	}
+
+	return req, nil
+}
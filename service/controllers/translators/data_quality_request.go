@@ -0,0 +1,16 @@
+package translators
+
+import (
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const rulesParam string = "rules" //string
+
+// ToDataQualityRequest creates the DataQualityRequest model from the data in the request
+func ToDataQualityRequest(r *http.Request) models.DataQualityRequest {
+	return models.DataQualityRequest{
+		Rules: r.URL.Query().Get(rulesParam),
+	}
+}
@@ -0,0 +1,132 @@
+package translators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const (
+	fieldsParam string = "fields" //string
+
+	// idField is returned whatever the fieldset, for the clients to tell the items apart
+	idField string = "id"
+)
+
+// ToFieldsRequest creates the FieldsRequest model from the data in the request, the
+// known fields being the JSON fields of item
+func ToFieldsRequest(r *http.Request, item interface{}) models.FieldsRequest {
+	return models.FieldsRequest{
+		Fields: r.URL.Query().Get(fieldsParam),
+		Known:  jsonFields(reflect.TypeOf(item)),
+	}
+}
+
+// jsonFields returns the names of the JSON fields of a struct, in order
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-" || !field.IsExported():
+		case name == "" && field.Anonymous:
+			fields = append(fields, jsonFields(field.Type)...)
+		case name == "":
+			fields = append(fields, field.Name)
+		default:
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// ToBooksMeta returns the metadata of a valid request of books, the number of books it
+// matches and their facets, warning about the parameters that had no effect
+func ToBooksMeta(req models.BookRequest, total int64, facets models.Facets) models.Meta {
+	meta := models.Meta{Total: total, Facets: facets}
+	meta.Limit, _ = strconv.ParseInt(req.Limit, 10, 64)
+	meta.Offset, _ = strconv.ParseInt(req.Offset, 10, 64)
+
+	if req.Ranking != "" && req.Ranker != "" {
+		meta.Warnings = append(meta.Warnings, "ranking is ignored as ranker is given")
+	}
+	if meta.Offset > 0 && meta.Offset >= total {
+		meta.Warnings = append(meta.Warnings, fmt.Sprintf("offset is past the last of the %d matching books", total))
+	}
+	return meta
+}
+
+// ToLinks returns the links of a list. Only the lists of a GET request have links to
+// their other pages, which are the same URL with another offset.
+func ToLinks(r *http.Request, meta models.Meta) models.Links {
+	links := models.Links{Self: r.URL.RequestURI()}
+	if r.Method != http.MethodGet || meta.Limit <= 0 {
+		return links
+	}
+
+	page := func(offset int64) string {
+		query := r.URL.Query()
+		query.Set(offsetParam, strconv.FormatInt(offset, 10))
+		return r.URL.Path + "?" + query.Encode()
+	}
+	links.First = page(0)
+	if meta.Offset > 0 {
+		links.Prev = page(max(meta.Offset-meta.Limit, 0))
+	}
+	if meta.Offset+meta.Limit < meta.Total {
+		links.Next = page(meta.Offset + meta.Limit)
+	}
+	return links
+}
+
+// EncodeEnvelope writes the response holding a list in its envelope, its items only
+// holding the given fields along with their id, or every field when none is given
+func EncodeEnvelope(w http.ResponseWriter, r *http.Request, status int, items interface{}, meta models.Meta, fields []string) {
+	data, err := selectFields(items, fields)
+	if err != nil {
+		ParseError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.Envelope{
+		Data:  data,
+		Meta:  meta,
+		Links: ToLinks(r, meta),
+	})
+}
+
+// selectFields returns the list of items as JSON objects only holding the given fields
+func selectFields(items interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	content, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, 0, len(objects))
+	for _, object := range objects {
+		sparse := map[string]json.RawMessage{idField: object[idField]}
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				sparse[field] = value
+			}
+		}
+		selected = append(selected, sparse)
+	}
+	return selected, nil
+}
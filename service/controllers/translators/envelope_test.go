@@ -0,0 +1,118 @@
+package translators_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFieldsRequest(t *testing.T) {
+	req := translators.ToFieldsRequest(httptest.NewRequest(http.MethodGet, "/api/v2/books?fields=title,title,authors", nil), models.Book{})
+
+	assert.Equal(t, []string{"title", "authors"}, req.Names())
+	assert.Contains(t, req.Known, "series")
+	assert.Contains(t, req.Known, "coverUrl")
+	assert.NoError(t, req.Validate())
+
+	req = translators.ToFieldsRequest(httptest.NewRequest(http.MethodGet, "/api/v2/authors?fields=role", nil), models.Author{})
+	assert.Equal(t, []string{"id", "firstName", "lastName"}, req.Known)
+	assert.EqualError(t, req.Validate(), "fields: should be a list of: id, firstName, lastName.")
+}
+
+func TestToBooksMeta(t *testing.T) {
+	var cases = []struct {
+		name   string
+		req    models.BookRequest
+		total  int64
+		assert func(meta models.Meta)
+	}{
+		{
+			name:  "success",
+			req:   models.BookRequest{Limit: "10", Offset: "20"},
+			total: 42,
+			assert: func(meta models.Meta) {
+				assert.Equal(t, models.Meta{Total: 42, Limit: 10, Offset: 20}, meta)
+			},
+		},
+		{
+			name:  "success - ranking overridden by ranker",
+			req:   models.BookRequest{Ranking: models.RankingRaw, Ranker: models.RankerRecency},
+			total: 42,
+			assert: func(meta models.Meta) {
+				assert.Equal(t, []string{"ranking is ignored as ranker is given"}, meta.Warnings)
+			},
+		},
+		{
+			name:  "success - offset past the total",
+			req:   models.BookRequest{Offset: "50"},
+			total: 42,
+			assert: func(meta models.Meta) {
+				assert.Equal(t, []string{"offset is past the last of the 42 matching books"}, meta.Warnings)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assert(translators.ToBooksMeta(c.req, c.total, nil))
+	}
+}
+
+func TestToLinks(t *testing.T) {
+	var cases = []struct {
+		name   string
+		method string
+		url    string
+		meta   models.Meta
+		assert func(links models.Links)
+	}{
+		{
+			name:   "success - first page",
+			method: http.MethodGet,
+			url:    "/api/v2/books?genres=3&limit=10",
+			meta:   models.Meta{Total: 25, Limit: 10},
+			assert: func(links models.Links) {
+				assert.Equal(t, models.Links{
+					Self:  "/api/v2/books?genres=3&limit=10",
+					First: "/api/v2/books?genres=3&limit=10&offset=0",
+					Next:  "/api/v2/books?genres=3&limit=10&offset=10",
+				}, links)
+			},
+		},
+		{
+			name:   "success - last page",
+			method: http.MethodGet,
+			url:    "/api/v2/books?limit=10&offset=15",
+			meta:   models.Meta{Total: 25, Limit: 10, Offset: 15},
+			assert: func(links models.Links) {
+				assert.Equal(t, "/api/v2/books?limit=10&offset=5", links.Prev)
+				assert.Empty(t, links.Next)
+			},
+		},
+		{
+			name:   "success - no limit",
+			method: http.MethodGet,
+			url:    "/api/v2/authors",
+			meta:   models.Meta{Total: 25},
+			assert: func(links models.Links) {
+				assert.Equal(t, models.Links{Self: "/api/v2/authors"}, links)
+			},
+		},
+		{
+			name:   "success - search",
+			method: http.MethodPost,
+			url:    "/api/v2/books/search",
+			meta:   models.Meta{Total: 25, Limit: 10},
+			assert: func(links models.Links) {
+				assert.Equal(t, models.Links{Self: "/api/v2/books/search"}, links)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assert(translators.ToLinks(httptest.NewRequest(c.method, c.url, nil), c.meta))
+	}
+}
@@ -1,31 +1,108 @@
 package translators
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/book-recommendations/service/models"
+	validation "github.com/go-ozzo/ozzo-validation"
 )
 
 const (
 	ErrBadRequest = "invalid query parameters"
+	ErrBadBody    = "invalid request body"
+	ErrBadPath    = "invalid path parameters"
+
+	// ProblemMediaType is the media type of the error responses
+	ProblemMediaType = "application/problem+json"
+	// ProblemTypeBase prefixes the type of the problems of a kind of domain error, the
+	// problems without any other meaning than their status have the about:blank type
+	ProblemTypeBase = "/problems/"
+
+	// RequestIDHeader is the header holding the ID of the request, on the request when the
+	// client or the gateway gives one and always on the response
+	RequestIDHeader = "X-Request-ID"
 )
 
-// ParseError
+// ParseError writes the problem of a status code, without any more details
 func ParseError(w http.ResponseWriter, code int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
+	problem := models.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+	}
+	if code == http.StatusBadRequest {
+		problem.Type = ProblemTypeBase + string(models.KindInvalid)
+		problem.Detail = ErrBadRequest
+	}
+	writeProblem(w, problem)
+}
 
-	var message string
-	switch code {
-	case http.StatusBadRequest:
-		message = ErrBadRequest
-	default:
-		message = http.StatusText(code)
+// ParseValidationError writes a bad request problem with the given detail, along with
+// the messages of every invalid parameter or field when err holds validation.Errors
+func ParseValidationError(w http.ResponseWriter, detail string, err error) {
+	problem := models.Problem{
+		Type:   ProblemTypeBase + string(models.KindInvalid),
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: detail,
+	}
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		problem.Errors = make(map[string][]string, len(fieldErrs))
+		for field, fieldErr := range fieldErrs {
+			problem.Errors[field] = []string{fieldErr.Error()}
+		}
+	}
+	writeProblem(w, problem)
+}
+
+// ParseDomainError writes the problem matching the kind of a domain error and returns
+// its status. The detail of the error is only shown for the client errors, any error
+// without a kind being an internal server error.
+func ParseDomainError(w http.ResponseWriter, err error) int {
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) && errors.Is(err, context.DeadlineExceeded) {
+		domainErr = models.ErrTimeout
+	}
+	if domainErr == nil {
+		ParseError(w, http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
 
-	err := models.ResponseError{
-		Message: message,
+	var status int
+	switch domainErr.Kind {
+	case models.KindNotFound:
+		status = http.StatusNotFound
+	case models.KindConflict:
+		status = http.StatusConflict
+	case models.KindInvalid:
+		status = http.StatusBadRequest
+	case models.KindTimeout:
+		status = http.StatusGatewayTimeout
+	case models.KindGone:
+		status = http.StatusGone
+	default:
+		ParseError(w, http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
-	json.NewEncoder(w).Encode(err)
+
+	writeProblem(w, models.Problem{
+		Type:   ProblemTypeBase + string(domainErr.Kind),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: domainErr.Detail,
+	})
+	return status
+}
+
+// writeProblem writes the problem, its instance being the ID of the request set on the response
+func writeProblem(w http.ResponseWriter, problem models.Problem) {
+	problem.Instance = w.Header().Get(RequestIDHeader)
+
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
 }
@@ -0,0 +1,114 @@
+package translators_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/models"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDomainError(t *testing.T) {
+	var cases = []struct {
+		name   string
+		err    error
+		assert func(status int, problem models.Problem)
+	}{
+		{
+			name: "not found",
+			err:  fmt.Errorf("getting book: %w", models.NewError(models.KindNotFound, "book %d not found", 7)),
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusNotFound, status)
+				assert.Equal(t, models.Problem{
+					Type:     "/problems/not-found",
+					Title:    "Not Found",
+					Status:   http.StatusNotFound,
+					Detail:   "book 7 not found",
+					Instance: "req-1",
+				}, problem)
+			},
+		},
+		{
+			name: "conflict",
+			err:  models.ErrConflict,
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusConflict, status)
+				assert.Equal(t, "/problems/conflict", problem.Type)
+			},
+		},
+		{
+			name: "gone",
+			err:  models.NewError(models.KindGone, "book %d was deleted", 7),
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusGone, status)
+				assert.Equal(t, "/problems/gone", problem.Type)
+				assert.Equal(t, "book 7 was deleted", problem.Detail)
+			},
+		},
+		{
+			name: "invalid",
+			err:  models.ErrUnknownVariant,
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, "unknown experiment variant", problem.Detail)
+			},
+		},
+		{
+			name: "timeout",
+			err:  fmt.Errorf("error while building query: %w", context.DeadlineExceeded),
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusGatewayTimeout, status)
+				assert.Equal(t, "/problems/timeout", problem.Type)
+			},
+		},
+		{
+			name: "internal server error",
+			err:  errors.New("connection refused"),
+			assert: func(status int, problem models.Problem) {
+				assert.Equal(t, http.StatusInternalServerError, status)
+				assert.Equal(t, "about:blank", problem.Type)
+				assert.Empty(t, problem.Detail, "the cause of server errors should not be shown")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		recorder.Header().Set(translators.RequestIDHeader, "req-1")
+
+		status := translators.ParseDomainError(recorder, c.err)
+
+		assert.Equal(t, translators.ProblemMediaType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, status, recorder.Code)
+		var problem models.Problem
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		c.assert(status, problem)
+	}
+}
+
+func TestParseValidationError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	err := validation.Errors{
+		"max-pages": errors.New("should be between 1 and 10000"),
+		"genres":    errors.New("should be for exaple: 123,456,789"),
+	}
+
+	translators.ParseValidationError(recorder, translators.ErrBadRequest, err)
+
+	var problem models.Problem
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/problems/invalid-request", problem.Type)
+	assert.Equal(t, translators.ErrBadRequest, problem.Detail)
+	assert.Equal(t, map[string][]string{
+		"max-pages": {"should be between 1 and 10000"},
+		"genres":    {"should be for exaple: 123,456,789"},
+	}, problem.Errors)
+}
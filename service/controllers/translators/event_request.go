@@ -0,0 +1,25 @@
+package translators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+// maxEventBodySize is the maximum size in bytes of the body of an event
+const maxEventBodySize = 4096
+
+// ToEvent creates the Event model from the JSON body of the request
+func ToEvent(w http.ResponseWriter, r *http.Request) (models.Event, error) {
+	var event models.Event
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEventBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&event); err != nil {
+		return models.Event{}, fmt.Errorf("invalid event body: %w", err)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,25 @@
+package translators
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/book-recommendations/service/models"
+)
+
+// ExperimentHeader lists the experiment variants the caller was assigned to, as a
+// comma-separated list of experiment=variant pairs
+const ExperimentHeader string = "X-Experiment-Variant"
+
+// SetExperimentHeader reports the assigned variants in the response
+func SetExperimentHeader(w http.ResponseWriter, assignments []models.Assignment) {
+	if len(assignments) == 0 {
+		return
+	}
+
+	variants := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		variants = append(variants, assignment.Experiment+"="+assignment.Variant)
+	}
+	w.Header().Set(ExperimentHeader, strings.Join(variants, ", "))
+}
@@ -0,0 +1,24 @@
+package translators
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// maxGraphQLBodySize is the maximum size in bytes of the body of a GraphQL request
+const maxGraphQLBodySize = 64 << 10
+
+// ToGraphQLRequest creates the GraphQLRequest model from the JSON body of the request
+func ToGraphQLRequest(w http.ResponseWriter, r *http.Request) (models.GraphQLRequest, error) {
+	var req models.GraphQLRequest
+	if err := decodeStrict(w, r, &req, maxGraphQLBodySize); err != nil {
+		return models.GraphQLRequest{}, err
+	}
+	if req.Query == "" {
+		return models.GraphQLRequest{}, validation.Errors{"query": errors.New("cannot be blank")}
+	}
+	return req, nil
+}
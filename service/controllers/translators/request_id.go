@@ -0,0 +1,20 @@
+package translators
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// requestIDPattern is the shape of the request IDs accepted from clients
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ToRequestID returns the request ID given by the client when valid, a new one otherwise
+func ToRequestID(given string) string {
+	if requestIDPattern.MatchString(given) {
+		return given
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,190 @@
+package translators
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+)
+
+const (
+	shelfIDVar    string = "shelfId" //integer
+	bookIDVar     string = "bookId"  //integer
+	shareTokenVar string = "token"   //string
+
+	formatParam string = "format" //string
+
+	// SharedShelvesPath is the path of the public read-only shelves, relative to the API root
+	SharedShelvesPath string = "/shared/shelves/"
+
+	// maxShelfBodySize is the maximum size in bytes of the body of a shelf request
+	maxShelfBodySize = 4096
+)
+
+// ToShelfID returns the ID of the shelf in the path of the request
+func ToShelfID(r *http.Request) (int64, error) {
+	return toPathID(r, shelfIDVar)
+}
+
+// ToShelfBookID returns the ID of the book in the path of the request
+func ToShelfBookID(r *http.Request) (int64, error) {
+	return toPathID(r, bookIDVar)
+}
+
+// ToShareToken returns the token of the shared shelf in the path of the request
+func ToShareToken(r *http.Request) string {
+	return mux.Vars(r)[shareTokenVar]
+}
+
+// ToShelfRequest creates the ShelfRequest model from the JSON body of the request
+func ToShelfRequest(w http.ResponseWriter, r *http.Request) (models.ShelfRequest, error) {
+	var req models.ShelfRequest
+	if err := decodeStrict(w, r, &req, maxShelfBodySize); err != nil {
+		return models.ShelfRequest{}, fmt.Errorf("invalid shelf body: %w", err)
+	}
+
+	return req, nil
+}
+
+// ToShelfEntryRequest creates the ShelfEntryRequest model from the JSON body of the request.
+// When the book is in the path of the request, the body only holds its position.
+func ToShelfEntryRequest(w http.ResponseWriter, r *http.Request) (models.ShelfEntryRequest, error) {
+	var req models.ShelfEntryRequest
+	if err := decodeStrict(w, r, &req, maxShelfBodySize); err != nil {
+		return models.ShelfEntryRequest{}, fmt.Errorf("invalid shelf entry body: %w", err)
+	}
+
+	if _, ok := mux.Vars(r)[bookIDVar]; ok {
+		bookID, err := ToShelfBookID(r)
+		if err != nil {
+			return models.ShelfEntryRequest{}, err
+		}
+		if req.BookID != 0 && req.BookID != bookID {
+			return models.ShelfEntryRequest{}, fmt.Errorf("book %d does not match the path", req.BookID)
+		}
+		req.BookID = bookID
+	}
+
+	return req, nil
+}
+
+// ToExportFormat returns the format asked to export a shelf, JSON by default
+func ToExportFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get(formatParam); format {
+	case "", models.ExportJSON:
+		return models.ExportJSON, nil
+	case models.ExportCSV:
+		return models.ExportCSV, nil
+	default:
+		return "", validation.Errors{formatParam: fmt.Errorf("should be one of: %s, %s", models.ExportJSON, models.ExportCSV)}
+	}
+}
+
+// ToShelfShare creates the ShelfShare model with the public link of the shelf
+func ToShelfShare(r *http.Request, token string) models.ShelfShare {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+
+	return models.ShelfShare{
+		Token: token,
+		URL:   scheme + "://" + r.Host + apiRoot(r) + SharedShelvesPath + token,
+	}
+}
+
+// WriteShelfExport writes the books of the shelf as an attachment in the given format
+func WriteShelfExport(w http.ResponseWriter, r *http.Request, shelf models.Shelf, format string) error {
+	filename := fmt.Sprintf("shelf-%d.%s", shelf.ID, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == models.ExportJSON {
+		mediaType := ToMediaType(r)
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(ToVersion(mediaType, shelf))
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	records := [][]string{{"position", "book_id", "title", "authors", "genres", "year_published", "pages", "rating", "added_at", "isbn13"}}
+	for _, entry := range shelf.Books {
+		records = append(records, []string{
+			strconv.FormatInt(entry.Position, 10),
+			strconv.FormatInt(entry.ID, 10),
+			entry.Title,
+			authorNames(entry.Book),
+			genreTitles(entry.Book),
+			strconv.FormatInt(entry.YearPublished, 10),
+			strconv.FormatInt(entry.Pages, 10),
+			strconv.FormatFloat(entry.Rating, 'f', -1, 64),
+			entry.AddedAt.UTC().Format(time.RFC3339),
+			entry.ISBN13,
+		})
+	}
+
+	return writer.WriteAll(records)
+}
+
+// authorNames lists the names of the authors of the book, separated by semicolons
+func authorNames(book models.Book) string {
+	names := make([]string, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		if author.Role == models.RoleAuthor {
+			names = append(names, author.FirstName+" "+author.LastName)
+		}
+	}
+	return strings.Join(names, "; ")
+}
+
+// genreTitles lists the titles of the genres of the book, separated by semicolons
+func genreTitles(book models.Book) string {
+	titles := make([]string, 0, len(book.Genres))
+	for _, genre := range book.Genres {
+		titles = append(titles, genre.Title)
+	}
+	return strings.Join(titles, "; ")
+}
+
+// apiRoot returns the prefix of the route matching the request, e.g. /api/v1
+func apiRoot(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	if i := strings.Index(template, "/me/"); i >= 0 {
+		return template[:i]
+	}
+	return ""
+}
+
+func toPathID(r *http.Request, name string) (int64, error) {
+	value := mux.Vars(r)[name]
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 1 || id > math.MaxInt32 {
+		return 0, validation.Errors{name: fmt.Errorf("should be between 1 and %v", math.MaxInt32)}
+	}
+	return id, nil
+}
+
+// decodeStrict decodes the JSON body of the request, rejecting unknown fields and bodies
+// larger than maxSize bytes
+func decodeStrict(w http.ResponseWriter, r *http.Request, v interface{}, maxSize int64) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSize))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
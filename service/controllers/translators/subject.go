@@ -0,0 +1,101 @@
+package translators
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// UserIDHeader carries the ID of the authenticated user, it is set by the gateway in front of the service
+	UserIDHeader string = "X-User-ID"
+	// UserSignatureHeader carries the signature of UserIDHeader by the gateway, see SignUserID
+	UserSignatureHeader string = "X-User-Signature"
+	// AnonymousCookie identifies the visitors that are not logged in
+	AnonymousCookie string = "readcommend_anon"
+
+	anonymousCookieMaxAge = 365 * 24 * time.Hour
+)
+
+var (
+	userIDPattern      = regexp.MustCompile("^[0-9]{1,10}$")
+	anonymousIDPattern = regexp.MustCompile("^[0-9a-f]{32}$")
+)
+
+// ToUserID returns the ID of the authenticated user, ok is false for anonymous callers.
+// The requests reach the controllers only once the signature of the header is verified.
+func ToUserID(r *http.Request) (userID int64, ok bool) {
+	return ParseUserID(r.Header.Get(UserIDHeader))
+}
+
+// ParseUserID returns the user ID of the value of a user ID header, ok is false when invalid
+func ParseUserID(header string) (userID int64, ok bool) {
+	if !userIDPattern.MatchString(header) {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || userID <= 0 || userID > math.MaxInt32 {
+		return 0, false
+	}
+	return userID, true
+}
+
+// SignUserID returns the signature of the value of a user ID header: the hex-encoded
+// HMAC-SHA256 of the value with the secret shared by the gateway and the service
+func SignUserID(secret, header string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyUserID tells whether the signature is the one of the value of a user ID header.
+// No signature is valid without a secret, so that no header is trusted until one is set.
+func VerifyUserID(secret, header, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(SignUserID(secret, header))
+	if err != nil {
+		return false
+	}
+	actual, err := hex.DecodeString(signature)
+	return err == nil && hmac.Equal(expected, actual)
+}
+
+// ToSubject returns the stable identifier of the caller used to assign experiment variants:
+// "user:<id>" for authenticated users and "anon:<id>" for anonymous visitors. Visitors without
+// a valid anonymous cookie get a new one.
+func ToSubject(w http.ResponseWriter, r *http.Request) string {
+	if userID, ok := ToUserID(r); ok {
+		return "user:" + strconv.FormatInt(userID, 10)
+	}
+
+	if cookie, err := r.Cookie(AnonymousCookie); err == nil && anonymousIDPattern.MatchString(cookie.Value) {
+		return "anon:" + cookie.Value
+	}
+
+	anonymousID := newAnonymousID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     AnonymousCookie,
+		Value:    anonymousID,
+		Path:     "/",
+		MaxAge:   int(anonymousCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return "anon:" + anonymousID
+}
+
+func newAnonymousID() string {
+	id := make([]byte, 16)
+	// crypto/rand never fails on the supported platforms
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
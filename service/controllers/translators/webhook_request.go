@@ -0,0 +1,46 @@
+package translators
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/book-recommendations/service/models"
+)
+
+const (
+	deliveryIDVar string = "deliveryId" //integer
+
+	deliveryStatusParam string = "status" //string
+
+	// maxWebhookBodySize is the maximum size in bytes of the body of a webhook request
+	maxWebhookBodySize = 8192
+)
+
+// ToWebhookID returns the ID of the webhook subscription in the path of the request
+func ToWebhookID(r *http.Request) (int64, error) {
+	return toPathID(r, idVar)
+}
+
+// ToDeliveryID returns the ID of the webhook delivery in the path of the request
+func ToDeliveryID(r *http.Request) (int64, error) {
+	return toPathID(r, deliveryIDVar)
+}
+
+// ToWebhookRequest creates the WebhookRequest model from the JSON body of the request
+func ToWebhookRequest(w http.ResponseWriter, r *http.Request) (models.WebhookRequest, error) {
+	var req models.WebhookRequest
+	if err := decodeStrict(w, r, &req, maxWebhookBodySize); err != nil {
+		return models.WebhookRequest{}, fmt.Errorf("invalid webhook body: %w", err)
+	}
+
+	return req, nil
+}
+
+// ToDeliveriesRequest creates the DeliveriesRequest model from the data in the request
+func ToDeliveriesRequest(r *http.Request) models.DeliveriesRequest {
+	query := r.URL.Query()
+	return models.DeliveriesRequest{
+		Status: query.Get(deliveryStatusParam),
+		Limit:  query.Get(limitParam),
+	}
+}
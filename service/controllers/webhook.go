@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookController defines the controller for the webhook subscriptions of the partners
+type WebhookController struct {
+	Logger                 *log.Entry
+	WebhookMediatorFactory func() mediators.WebhookMediator
+}
+
+// GetAll retrieves every webhook subscription
+func (c *WebhookController) GetAll(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	subscriptions, err := c.WebhookMediatorFactory().GetAll(r.Context())
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+// Get retrieves a webhook subscription
+func (c *WebhookController) Get(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.WebhookMediatorFactory().Get(r.Context(), id)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// Post creates a webhook subscription
+func (c *WebhookController) Post(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	req, err := translators.ToWebhookRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for post webhook")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	subscription, err := c.WebhookMediatorFactory().Create(r.Context(), req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// Put replaces a webhook subscription
+func (c *WebhookController) Put(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+	req, err := translators.ToWebhookRequest(w, r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request body for put webhook")
+		translators.ParseValidationError(w, translators.ErrBadBody, err)
+		return
+	}
+
+	subscription, err := c.WebhookMediatorFactory().Update(r.Context(), id, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// Delete deletes a webhook subscription along with its deliveries
+func (c *WebhookController) Delete(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.WebhookMediatorFactory().Delete(r.Context(), id); err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries retrieves the latest deliveries of a webhook subscription with the log of their attempts
+func (c *WebhookController) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+	req := translators.ToDeliveriesRequest(r)
+	if err := req.Validate(); err != nil {
+		c.Logger.WithError(err).Error("invalid request params for get webhook deliveries")
+		translators.ParseValidationError(w, translators.ErrBadRequest, err)
+		return
+	}
+
+	deliveries, err := c.WebhookMediatorFactory().GetDeliveries(r.Context(), id, req)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// Retry makes a dead delivery of a webhook subscription pending again
+func (c *WebhookController) Retry(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := translators.ToDeliveryID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for retry webhook delivery")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return
+	}
+
+	delivery, err := c.WebhookMediatorFactory().Retry(r.Context(), id, deliveryID)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// Ping delivers a ping event to a webhook subscription only, to check its receiver
+func (c *WebhookController) Ping(w http.ResponseWriter, r *http.Request) {
+	c.Logger.WithField("url", r.URL).Info("request")
+
+	id, ok := c.toWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.WebhookMediatorFactory().Ping(r.Context(), id)
+	if err != nil {
+		writeError(c.Logger, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// toWebhookID returns the ID of the webhook subscription in the path, answering the
+// request itself when it is invalid
+func (c *WebhookController) toWebhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := translators.ToWebhookID(r)
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid request params for webhook")
+		translators.ParseValidationError(w, translators.ErrBadPath, err)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,215 @@
+package controllers_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-recommendations/service/controllers"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type WebhookMediatorMock struct {
+	RequestField    *models.WebhookRequest
+	DeliveriesField *models.DeliveriesRequest
+	ErrorField      error
+}
+
+func (m *WebhookMediatorMock) GetAll(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return nil, m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Get(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	return models.WebhookSubscription{ID: id}, m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Create(ctx context.Context, req models.WebhookRequest) (models.WebhookSubscription, error) {
+	m.RequestField = &req
+	return req.Subscription(1), m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Update(ctx context.Context, id int64, req models.WebhookRequest) (models.WebhookSubscription, error) {
+	m.RequestField = &req
+	return req.Subscription(id), m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+func (m *WebhookMediatorMock) GetDeliveries(ctx context.Context, id int64, req models.DeliveriesRequest) ([]models.WebhookDelivery, error) {
+	m.DeliveriesField = &req
+	return []models.WebhookDelivery{}, m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Retry(ctx context.Context, id, deliveryID int64) (models.WebhookDelivery, error) {
+	return models.WebhookDelivery{ID: deliveryID, SubscriptionID: id, Status: models.DeliveryPending}, m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Ping(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	return models.WebhookDelivery{ID: 12, SubscriptionID: id, EventType: models.ChangePing, Status: models.DeliveryPending}, m.ErrorField
+}
+
+func (m *WebhookMediatorMock) Dispatch(ctx context.Context) error {
+	return m.ErrorField
+}
+
+func TestWebhookController_Post(t *testing.T) {
+	var cases = []struct {
+		name     string
+		body     string
+		mediator *WebhookMediatorMock
+		assert   func(resp *http.Response, body string, mediator *WebhookMediatorMock)
+	}{
+		{
+			name:     "success",
+			body:     `{"url":"https://partner.example.com/hooks","secret":"0123456789abcdef","eventTypes":["book.created","book.deleted"]}`,
+			mediator: &WebhookMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *WebhookMediatorMock) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Contains(t, body, `"eventTypes":["book.created","book.deleted"]`)
+				assert.Contains(t, body, `"active":true`)
+				assert.NotContains(t, body, "0123456789abcdef", "the secret is never returned")
+			},
+		},
+		{
+			name:     "short secret",
+			body:     `{"url":"https://partner.example.com/hooks","secret":"short","eventTypes":["book.created"]}`,
+			mediator: &WebhookMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *WebhookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be between 16 and 200 characters")
+				assert.Nil(t, mediator.RequestField)
+			},
+		},
+		{
+			name:     "not http",
+			body:     `{"url":"ftp://partner.example.com/hooks","secret":"0123456789abcdef","eventTypes":["book.created"]}`,
+			mediator: &WebhookMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *WebhookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be an http or https URL")
+			},
+		},
+		{
+			name:     "unknown event type",
+			body:     `{"url":"https://partner.example.com/hooks","secret":"0123456789abcdef","eventTypes":["shelf.created"]}`,
+			mediator: &WebhookMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *WebhookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should be a list of")
+			},
+		},
+		{
+			name:     "repeated event type",
+			body:     `{"url":"https://partner.example.com/hooks","secret":"0123456789abcdef","eventTypes":["book.created","book.created"]}`,
+			mediator: &WebhookMediatorMock{},
+			assert: func(resp *http.Response, body string, mediator *WebhookMediatorMock) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, body, "should not repeat a type")
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.WebhookController{
+				Logger:                 log.NewEntry(log.New()),
+				WebhookMediatorFactory: func() mediators.WebhookMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "http://test.com/api/v1/admin/webhooks", strings.NewReader(c.body))
+			controller.Post(recorder, request)
+
+			resp := recorder.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err, "should return a readable response body")
+			c.assert(resp, string(body), c.mediator)
+		})
+	}
+}
+
+func TestWebhookController_Deliveries(t *testing.T) {
+	var cases = []struct {
+		name     string
+		method   string
+		url      string
+		mediator *WebhookMediatorMock
+		status   int
+	}{
+		{
+			name:     "list dead",
+			method:   http.MethodGet,
+			url:      "http://test.com/api/v1/admin/webhooks/3/deliveries?status=dead",
+			mediator: &WebhookMediatorMock{},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "unknown status",
+			method:   http.MethodGet,
+			url:      "http://test.com/api/v1/admin/webhooks/3/deliveries?status=lost",
+			mediator: &WebhookMediatorMock{},
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "unknown subscription",
+			method:   http.MethodGet,
+			url:      "http://test.com/api/v1/admin/webhooks/30/deliveries",
+			mediator: &WebhookMediatorMock{ErrorField: models.NewError(models.KindNotFound, "webhook 30 not found")},
+			status:   http.StatusNotFound,
+		},
+		{
+			name:     "ping",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/webhooks/3/ping",
+			mediator: &WebhookMediatorMock{},
+			status:   http.StatusAccepted,
+		},
+		{
+			name:     "retry",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/webhooks/3/deliveries/42/retry",
+			mediator: &WebhookMediatorMock{},
+			status:   http.StatusAccepted,
+		},
+		{
+			name:     "retry not dead",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/webhooks/3/deliveries/42/retry",
+			mediator: &WebhookMediatorMock{ErrorField: models.NewError(models.KindConflict, "delivery 42 is pending, only dead deliveries are retried")},
+			status:   http.StatusConflict,
+		},
+		{
+			name:     "retry invalid delivery",
+			method:   http.MethodPost,
+			url:      "http://test.com/api/v1/admin/webhooks/3/deliveries/0/retry",
+			mediator: &WebhookMediatorMock{},
+			status:   http.StatusBadRequest,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := controllers.WebhookController{
+				Logger:                 log.NewEntry(log.New()),
+				WebhookMediatorFactory: func() mediators.WebhookMediator { return c.mediator },
+			}
+
+			recorder := httptest.NewRecorder()
+			router := mux.NewRouter().PathPrefix("/api/v1/admin").Subrouter()
+			router.HandleFunc("/webhooks/{id}/deliveries", controller.GetDeliveries).Methods(http.MethodGet)
+			router.HandleFunc("/webhooks/{id}/ping", controller.Ping).Methods(http.MethodPost)
+			router.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/retry", controller.Retry).Methods(http.MethodPost)
+			router.ServeHTTP(recorder, httptest.NewRequest(c.method, c.url, nil))
+
+			assert.Equal(t, c.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Executor runs the GraphQL requests, rejecting the queries beyond its limits
+type Executor struct {
+	schema   graphql.Schema
+	resolver Resolver
+	limits   config.GraphQLConfig
+}
+
+// NewExecutor returns a new instance of Executor
+func NewExecutor(resolver Resolver, limits config.GraphQLConfig) (*Executor, error) {
+	schema, err := NewSchema(resolver)
+	if err != nil {
+		return nil, fmt.Errorf("error building graphql schema: %w", err)
+	}
+
+	return &Executor{
+		schema:   schema,
+		resolver: resolver,
+		limits:   limits,
+	}, nil
+}
+
+// Execute runs the request on behalf of the given user, zero for anonymous callers. The
+// result has no data when the query was rejected before being executed.
+func (e *Executor) Execute(ctx context.Context, req models.GraphQLRequest, userID int64) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+	}
+
+	depth, complexity := measure(doc, req.Variables)
+	if depth > e.limits.MaxDepth {
+		return rejected("the query is %d levels deep, more than the maximum of %d", depth, e.limits.MaxDepth)
+	}
+	if complexity > e.limits.MaxComplexity {
+		return rejected("the query has a complexity of %d, more than the maximum of %d", complexity, e.limits.MaxComplexity)
+	}
+
+	ctx = context.WithValue(withLoaders(ctx, e.resolver), userIDKey{}, userID)
+	result := graphql.Do(graphql.Params{
+		Schema:         e.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	for i, err := range result.Errors {
+		if err.Extensions == nil {
+			result.Errors[i].Extensions = extensionsOf(err.OriginalError())
+		}
+	}
+	return result
+}
+
+// extensionsOf returns the extensions of the error at the bottom of err. The executor
+// wraps the errors of the thunks twice, dropping their extensions on the way.
+func extensionsOf(err error) map[string]interface{} {
+	for err != nil {
+		switch wrapped := err.(type) {
+		case gqlerrors.ExtendedError:
+			return wrapped.Extensions()
+		case gqlerrors.FormattedError:
+			err = wrapped.OriginalError()
+		case *gqlerrors.Error:
+			err = wrapped.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func rejected(format string, args ...interface{}) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(fmt.Sprintf(format, args...))}}
+}
@@ -0,0 +1,361 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/graph"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/graphql-go/graphql"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type BookMediatorMock struct {
+	BookField    []models.Book
+	FacetsField  models.Facets
+	ErrorField   error
+	RequestField models.BookRequest
+}
+
+func (m *BookMediatorMock) Get(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
+	m.RequestField = req
+	return m.BookField, m.ErrorField
+}
+
+func (m *BookMediatorMock) Count(ctx context.Context, req models.BookRequest) (int64, error) {
+	return int64(len(m.BookField)), m.ErrorField
+}
+
+func (m *BookMediatorMock) Similar(ctx context.Context, req models.SimilarBooksRequest) ([]models.SimilarBook, error) {
+	return nil, m.ErrorField
+}
+
+func (m *BookMediatorMock) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{}, m.ErrorField
+}
+
+func (m *BookMediatorMock) GetHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	return []models.BookVersion{{Version: 1}}, m.ErrorField
+}
+
+func (m *BookMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+func (m *BookMediatorMock) Restore(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{ID: id}, m.ErrorField
+}
+
+func (m *BookMediatorMock) Facets(ctx context.Context, req models.BookRequest) (models.Facets, error) {
+	m.RequestField = req
+	return m.FacetsField, m.ErrorField
+}
+
+type AuthorMediatorMock struct {
+	AuthorField []models.Author
+	ErrorField  error
+	Calls       int32
+}
+
+func (m *AuthorMediatorMock) Get(ctx context.Context) ([]models.Author, error) {
+	atomic.AddInt32(&m.Calls, 1)
+	return m.AuthorField, m.ErrorField
+}
+
+func (m *AuthorMediatorMock) GetAuthor(ctx context.Context, req models.AuthorRequest) (models.AuthorDetail, error) {
+	return models.AuthorDetail{}, m.ErrorField
+}
+
+func (m *AuthorMediatorMock) GetBooks(ctx context.Context, req models.AuthorBooksRequest) ([]models.Book, error) {
+	return nil, m.ErrorField
+}
+
+type GenreMediatorMock struct {
+	GenreField []models.Genre
+	ErrorField error
+}
+
+func (m *GenreMediatorMock) Get(ctx context.Context) ([]models.Genre, error) {
+	return m.GenreField, m.ErrorField
+}
+
+func (m *GenreMediatorMock) GetStats(ctx context.Context) ([]models.GenreStats, error) {
+	return nil, m.ErrorField
+}
+
+type SizeMediatorMock struct {
+	SizeField  []models.Size
+	ErrorField error
+}
+
+func (m *SizeMediatorMock) Get(ctx context.Context) ([]models.Size, error) {
+	return m.SizeField, m.ErrorField
+}
+
+func (m *SizeMediatorMock) GetAll(ctx context.Context) ([]models.Size, error) {
+	return m.SizeField, m.ErrorField
+}
+
+func (m *SizeMediatorMock) Create(ctx context.Context, req models.SizeRequest) (models.Size, error) {
+	return models.Size{}, m.ErrorField
+}
+
+func (m *SizeMediatorMock) Update(ctx context.Context, id int64, req models.SizeRequest) (models.Size, error) {
+	return models.Size{}, m.ErrorField
+}
+
+func (m *SizeMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+type EraMediatorMock struct {
+	EraField   []models.Era
+	ErrorField error
+}
+
+func (m *EraMediatorMock) Get(ctx context.Context) ([]models.Era, error) {
+	return m.EraField, m.ErrorField
+}
+
+func (m *EraMediatorMock) GetAll(ctx context.Context) ([]models.Era, error) {
+	return m.EraField, m.ErrorField
+}
+
+func (m *EraMediatorMock) Create(ctx context.Context, req models.EraRequest) (models.Era, error) {
+	return models.Era{}, m.ErrorField
+}
+
+func (m *EraMediatorMock) Update(ctx context.Context, id int64, req models.EraRequest) (models.Era, error) {
+	return models.Era{}, m.ErrorField
+}
+
+func (m *EraMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+var limits = config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 5000}
+
+func newExecutor(t *testing.T, book *BookMediatorMock, author *AuthorMediatorMock, cfg config.GraphQLConfig) *graph.Executor {
+	executor, err := graph.NewExecutor(graph.Resolver{
+		Logger:                log.WithField("test", "graphql"),
+		BookMediatorFactory:   func() mediators.BookMediator { return book },
+		AuthorMediatorFactory: func() mediators.AuthorMediator { return author },
+		GenreMediatorFactory: func() mediators.GenreMediator {
+			return &GenreMediatorMock{GenreField: []models.Genre{{ID: 8, Title: "Childrens"}}}
+		},
+		SizeMediatorFactory: func() mediators.SizeMediator { return &SizeMediatorMock{} },
+		EraMediatorFactory:  func() mediators.EraMediator { return &EraMediatorMock{} },
+	}, cfg)
+	require.NoError(t, err)
+	return executor
+}
+
+// decode returns the data of a result as JSON, as clients get it
+func decode(t *testing.T, result *graphql.Result, v interface{}) {
+	content, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, v))
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	books := []models.Book{
+		{
+			ID:      1,
+			Title:   "Alanna Saves the Day",
+			Rating:  1.62,
+			Genres:  []models.Genre{{ID: 8, Title: "Childrens"}},
+			Authors: []models.BookAuthor{{Author: models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"}, Role: models.RoleAuthor}},
+		},
+	}
+	authors := []models.Author{
+		{ID: 6, FirstName: "Bernard", LastName: "Hopf"},
+		{ID: 7, FirstName: "Amelia", LastName: "Inez"},
+	}
+
+	var cases = []struct {
+		name    string
+		book    *BookMediatorMock
+		author  *AuthorMediatorMock
+		limits  config.GraphQLConfig
+		request models.GraphQLRequest
+		userID  int64
+		assert  func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock)
+	}{
+		{
+			name:   "books",
+			book:   &BookMediatorMock{BookField: books},
+			author: &AuthorMediatorMock{},
+			limits: limits,
+			request: models.GraphQLRequest{
+				Query: `{ books(authors: [6], genreMatch: ALL, pages: {min: 100}, sort: RECENCY, limit: 5) { id title author { lastName } authors { id role } genre { title } } }`,
+			},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Empty(t, result.Errors)
+				var data struct {
+					Books []struct {
+						ID      int64
+						Title   string
+						Author  struct{ LastName string }
+						Authors []struct {
+							ID   int64
+							Role string
+						}
+						Genre struct{ Title string }
+					}
+				}
+				decode(t, result, &data)
+				require.Len(t, data.Books, 1)
+				assert.Equal(t, "Alanna Saves the Day", data.Books[0].Title)
+				assert.Equal(t, "Hopf", data.Books[0].Author.LastName)
+				assert.Equal(t, int64(6), data.Books[0].Authors[0].ID)
+				assert.Equal(t, "Childrens", data.Books[0].Genre.Title)
+
+				assert.Equal(t, "6", book.RequestField.Authors)
+				assert.Equal(t, models.GenreMatchAll, book.RequestField.GenreMatch)
+				assert.Equal(t, "100", book.RequestField.MinPages)
+				assert.Equal(t, models.RankerRecency, book.RequestField.Ranker)
+				assert.Equal(t, "5", book.RequestField.Limit)
+			},
+		},
+		{
+			name: "facets batched",
+			book: &BookMediatorMock{FacetsField: models.Facets{
+				models.FacetAuthors: {{ID: 6, Count: 3}, {ID: 7, Count: 1}},
+			}},
+			author: &AuthorMediatorMock{AuthorField: authors},
+			limits: limits,
+			request: models.GraphQLRequest{
+				Query: `{ facets(genres: [8]) { ...counts } } fragment counts on Facets { authors { author { firstName } count } }`,
+			},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Empty(t, result.Errors)
+				var data struct {
+					Facets struct {
+						Authors []struct {
+							Author struct{ FirstName string }
+							Count  int64
+						}
+					}
+				}
+				decode(t, result, &data)
+				require.Len(t, data.Facets.Authors, 2)
+				assert.Equal(t, "Bernard", data.Facets.Authors[0].Author.FirstName)
+				assert.Equal(t, "Amelia", data.Facets.Authors[1].Author.FirstName)
+				assert.Equal(t, int64(3), data.Facets.Authors[0].Count)
+
+				assert.Equal(t, models.FacetAuthors, book.RequestField.Facets)
+				assert.Equal(t, "8", book.RequestField.Genres)
+				assert.Equal(t, int32(1), author.Calls)
+			},
+		},
+		{
+			name:    "author not found",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{AuthorField: authors},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `query($id: Int!) { author(id: $id) { lastName } }`, Variables: map[string]interface{}{"id": 99}},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "author 99 not found", result.Errors[0].Message)
+				assert.Equal(t, models.KindNotFound, result.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name:    "internal error hidden",
+			book:    &BookMediatorMock{ErrorField: errors.New("connection refused")},
+			author:  &AuthorMediatorMock{},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `{ books { id } }`},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "internal server error", result.Errors[0].Message)
+			},
+		},
+		{
+			name:    "invalid arguments",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `{ books(minRating: 6) { id } }`},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, models.KindInvalid, result.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name:    "exclude read anonymous",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `{ books(excludeRead: true) { id } }`},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "excludeRead needs an authenticated user", result.Errors[0].Message)
+			},
+		},
+		{
+			name:    "exclude read",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `{ books(excludeRead: true) { id } }`},
+			userID:  42,
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				require.Empty(t, result.Errors)
+				assert.Equal(t, int64(42), book.RequestField.UserID)
+			},
+		},
+		{
+			name:    "too deep",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{},
+			limits:  config.GraphQLConfig{MaxDepth: 2, MaxComplexity: 5000},
+			request: models.GraphQLRequest{Query: `{ books { author { lastName } } }`},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				assert.Nil(t, result.Data)
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "the query is 3 levels deep, more than the maximum of 2", result.Errors[0].Message)
+			},
+		},
+		{
+			name:   "too complex",
+			book:   &BookMediatorMock{},
+			author: &AuthorMediatorMock{},
+			limits: config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 100},
+			request: models.GraphQLRequest{
+				Query:     `query($limit: Int) { books(limit: $limit) { id title authors { id } } }`,
+				Variables: map[string]interface{}{"limit": 50},
+			},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				assert.Nil(t, result.Data)
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "the query has a complexity of 201, more than the maximum of 100", result.Errors[0].Message)
+			},
+		},
+		{
+			name:    "syntax error",
+			book:    &BookMediatorMock{},
+			author:  &AuthorMediatorMock{},
+			limits:  limits,
+			request: models.GraphQLRequest{Query: `{ books { id }`},
+			assert: func(result *graphql.Result, book *BookMediatorMock, author *AuthorMediatorMock) {
+				assert.Nil(t, result.Data)
+				assert.NotEmpty(t, result.Errors)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			executor := newExecutor(t, c.book, c.author, c.limits)
+			result := executor.Execute(context.Background(), c.request, c.userID)
+			c.assert(result, c.book, c.author)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// measure returns the depth and the complexity of the operations of a query. The depth
+// is the deepest nesting of fields. The complexity is the number of fields to resolve,
+// the fields under a field with a limit argument counting once per item of the limit.
+func measure(doc *ast.Document, variables map[string]interface{}) (depth, complexity int64) {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	// visiting holds the fragments being expanded, the validation rejects the cycles
+	// later on but they must not loop here
+	visiting := make(map[string]bool)
+	var walk func(set *ast.SelectionSet, level int64) (int64, int64)
+	walk = func(set *ast.SelectionSet, level int64) (maxLevel, cost int64) {
+		if set == nil {
+			return level - 1, 0
+		}
+		maxLevel = level
+		for _, selection := range set.Selections {
+			var childLevel, childCost int64
+			switch selection := selection.(type) {
+			case *ast.Field:
+				childLevel, childCost = walk(selection.SelectionSet, level+1)
+				cost += 1 + limitOf(selection, variables)*childCost
+				maxLevel = max(maxLevel, childLevel)
+				continue
+			case *ast.InlineFragment:
+				childLevel, childCost = walk(selection.SelectionSet, level)
+			case *ast.FragmentSpread:
+				name := selection.Name.Value
+				if fragment, ok := fragments[name]; ok && !visiting[name] {
+					visiting[name] = true
+					childLevel, childCost = walk(fragment.SelectionSet, level)
+					visiting[name] = false
+				}
+			}
+			cost += childCost
+			maxLevel = max(maxLevel, childLevel)
+		}
+		return maxLevel, cost
+	}
+
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok {
+			operationDepth, operationComplexity := walk(operation.SelectionSet, 1)
+			depth = max(depth, operationDepth)
+			complexity += operationComplexity
+		}
+	}
+	return depth, complexity
+}
+
+// limitOf returns the limit argument of a field, its default for the books query and
+// one for the fields without any
+func limitOf(field *ast.Field, variables map[string]interface{}) int64 {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if limit, err := strconv.ParseInt(value.Value, 10, 64); err == nil && limit > 0 {
+				return limit
+			}
+		case *ast.Variable:
+			if limit, err := strconv.ParseInt(fmt.Sprint(variables[value.Name.Value]), 10, 64); err == nil && limit > 0 {
+				return limit
+			}
+		}
+	}
+	if field.Name.Value == "books" {
+		return defaultLimit
+	}
+	return 1
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait is how long a loader waits for more keys before fetching a batch. The
+// executor resolves the fields of a level before their thunks, so every key of a
+// level is queued by then.
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaders batch the lookups of the authors, genres, sizes and eras of a request
+type loaders struct {
+	authors *dataloader.Loader[int64, models.Author]
+	genres  *dataloader.Loader[int64, models.Genre]
+	sizes   *dataloader.Loader[int64, models.Size]
+	eras    *dataloader.Loader[int64, models.Era]
+}
+
+// withLoaders returns a context holding new loaders, they cache their results for the
+// lifetime of the request only
+func withLoaders(ctx context.Context, r Resolver) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		authors: newLoader(func(ctx context.Context) ([]models.Author, error) {
+			return r.AuthorMediatorFactory().Get(ctx)
+		}, func(author models.Author) int64 { return author.ID }, "author"),
+		genres: newLoader(func(ctx context.Context) ([]models.Genre, error) {
+			return r.GenreMediatorFactory().Get(ctx)
+		}, func(genre models.Genre) int64 { return genre.ID }, "genre"),
+		sizes: newLoader(func(ctx context.Context) ([]models.Size, error) {
+			return r.SizeMediatorFactory().Get(ctx)
+		}, func(size models.Size) int64 { return size.ID }, "size"),
+		eras: newLoader(func(ctx context.Context) ([]models.Era, error) {
+			return r.EraMediatorFactory().Get(ctx)
+		}, func(era models.Era) int64 { return era.ID }, "era"),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// newLoader returns a loader fetching a whole batch with a single call to getAll, the
+// mediators listing every item at once. The keys missing from the list are not found.
+func newLoader[V any](getAll func(ctx context.Context) ([]V, error), idOf func(V) int64, name string) *dataloader.Loader[int64, V] {
+	batch := func(ctx context.Context, keys []int64) []*dataloader.Result[V] {
+		results := make([]*dataloader.Result[V], len(keys))
+
+		items, err := getAll(ctx)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[V]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[int64]V, len(items))
+		for _, item := range items {
+			byID[idOf(item)] = item
+		}
+		for i, key := range keys {
+			if item, ok := byID[key]; ok {
+				results[i] = &dataloader.Result[V]{Data: item}
+			} else {
+				results[i] = &dataloader.Result[V]{Error: models.NewError(models.KindNotFound, "%s %d not found", name, key)}
+			}
+		}
+		return results
+	}
+
+	return dataloader.NewBatchedLoader(batch, dataloader.WithWait[int64, V](loaderWait))
+}
+
+// thunk defers the load of a key until the executor resolves the thunks, letting the
+// loader batch it with the keys of the sibling fields
+func thunk[V any](ctx context.Context, loader *dataloader.Loader[int64, V], key int64) func() (interface{}, error) {
+	load := loader.Load(ctx, key)
+	return func() (interface{}, error) {
+		return load()
+	}
+}
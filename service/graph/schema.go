@@ -0,0 +1,407 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultLimit is the number of books returned by the books query when no limit is given
+const defaultLimit = 10
+
+// errInternal is shown in place of the errors that are not domain errors
+var errInternal = publicError{kind: "internal", detail: "internal server error"}
+
+// Resolver resolves the queries with the mediators of the REST API
+type Resolver struct {
+	Logger                *log.Entry
+	BookMediatorFactory   func() mediators.BookMediator
+	AuthorMediatorFactory func() mediators.AuthorMediator
+	GenreMediatorFactory  func() mediators.GenreMediator
+	SizeMediatorFactory   func() mediators.SizeMediator
+	EraMediatorFactory    func() mediators.EraMediator
+}
+
+type userIDKey struct{}
+
+// NewSchema returns the schema of the books, their authors, genres, sizes and eras
+func NewSchema(r Resolver) (graphql.Schema, error) {
+	author := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"firstName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"lastName":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	genre := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Genre",
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	size := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Size",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"minPages": &graphql.Field{Type: graphql.Int},
+			"maxPages": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	era := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Era",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"minYear": &graphql.Field{Type: graphql.Int},
+			"maxYear": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	bookAuthor := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "BookAuthor",
+		Description: "An author of a book along with their role",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.BookAuthor).ID, nil
+			}},
+			"firstName": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.BookAuthor).FirstName, nil
+			}},
+			"lastName": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.BookAuthor).LastName, nil
+			}},
+			"role": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	bookSeries := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BookSeries",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"position": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+	book := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"yearPublished":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"rating":         &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"ratingCount":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"weightedRating": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"pages":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"authors":        &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookAuthor)))},
+			"genres":         &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(genre)))},
+			"author": &graphql.Field{
+				Type:        author,
+				Description: "The primary author of the book",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(models.Book).PrimaryAuthor(), nil
+				},
+			},
+			"genre": &graphql.Field{
+				Type:        genre,
+				Description: "The primary genre of the book",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(models.Book).PrimaryGenre(), nil
+				},
+			},
+			"series":      &graphql.Field{Type: bookSeries},
+			"isbn10":      &graphql.Field{Type: graphql.String},
+			"isbn13":      &graphql.Field{Type: graphql.String},
+			"language":    &graphql.Field{Type: graphql.String},
+			"publisher":   &graphql.Field{Type: graphql.String},
+			"description": &graphql.Field{Type: graphql.String},
+			"coverUrl":    &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	facets := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Facets",
+		Description: "The number of books for every value of a facet, with every filter applied but the ones of that facet",
+		Fields: graphql.Fields{
+			models.FacetAuthors: facetField(r, author, "AuthorCount", models.FacetAuthors, func(ctx context.Context, id int64) func() (interface{}, error) {
+				return thunk(ctx, loadersFrom(ctx).authors, id)
+			}),
+			models.FacetGenres: facetField(r, genre, "GenreCount", models.FacetGenres, func(ctx context.Context, id int64) func() (interface{}, error) {
+				return thunk(ctx, loadersFrom(ctx).genres, id)
+			}),
+			models.FacetSizes: facetField(r, size, "SizeCount", models.FacetSizes, func(ctx context.Context, id int64) func() (interface{}, error) {
+				return thunk(ctx, loadersFrom(ctx).sizes, id)
+			}),
+			models.FacetEras: facetField(r, era, "EraCount", models.FacetEras, func(ctx context.Context, id int64) func() (interface{}, error) {
+				return thunk(ctx, loadersFrom(ctx).eras, id)
+			}),
+		},
+	})
+
+	byID := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"books": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(book))),
+				Description: "The books matching the filters, ranked",
+				Args:        booksArgs(true),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					req, err := toBookRequest(p)
+					if err != nil {
+						return nil, err
+					}
+					books, err := r.BookMediatorFactory().Get(p.Context, req)
+					if err != nil {
+						return nil, r.fail(err)
+					}
+					return books, nil
+				},
+			},
+			"facets": &graphql.Field{
+				Type:        graphql.NewNonNull(facets),
+				Description: "The facets of the books matching the filters, only the selected facets are counted",
+				Args:        booksArgs(false),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					req, err := toBookRequest(p)
+					if err != nil {
+						return nil, err
+					}
+					req.Facets = strings.Join(selectedFields(p), ",")
+					counts, err := r.BookMediatorFactory().Facets(p.Context, req)
+					if err != nil {
+						return nil, r.fail(err)
+					}
+					return counts, nil
+				},
+			},
+			"authors": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(author))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					authors, err := r.AuthorMediatorFactory().Get(p.Context)
+					return authors, r.fail(err)
+				},
+			},
+			"author": &graphql.Field{
+				Type: author,
+				Args: byID,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.failThunk(thunk(p.Context, loadersFrom(p.Context).authors, int64(p.Args["id"].(int)))), nil
+				},
+			},
+			"genres": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(genre))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					genres, err := r.GenreMediatorFactory().Get(p.Context)
+					return genres, r.fail(err)
+				},
+			},
+			"genre": &graphql.Field{
+				Type: genre,
+				Args: byID,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.failThunk(thunk(p.Context, loadersFrom(p.Context).genres, int64(p.Args["id"].(int)))), nil
+				},
+			},
+			"sizes": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(size))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					sizes, err := r.SizeMediatorFactory().Get(p.Context)
+					return sizes, r.fail(err)
+				},
+			},
+			"eras": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(era))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					eras, err := r.EraMediatorFactory().Get(p.Context)
+					return eras, r.fail(err)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// facetField returns the field of the counts of a facet, along with the item each count is about
+func facetField(r Resolver, item *graphql.Object, name, facet string, load func(ctx context.Context, id int64) func() (interface{}, error)) *graphql.Field {
+	count := graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.Fields{
+			strings.ToLower(item.Name()): &graphql.Field{
+				Type: graphql.NewNonNull(item),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.failThunk(load(p.Context, p.Source.(models.FacetCount).ID)), nil
+				},
+			},
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(count))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(models.Facets)[facet], nil
+		},
+	}
+}
+
+// booksArgs returns the arguments of the books and facets queries. They mirror the body
+// of a search of books, the books query taking the sort and the page as well.
+func booksArgs(paged bool) graphql.FieldConfigArgument {
+	ids := graphql.NewList(graphql.NewNonNull(graphql.Int))
+	args := graphql.FieldConfigArgument{
+		"authors":           &graphql.ArgumentConfig{Type: ids},
+		"genres":            &graphql.ArgumentConfig{Type: ids},
+		"genreMatch":        &graphql.ArgumentConfig{Type: genreMatch},
+		"excludeAuthors":    &graphql.ArgumentConfig{Type: ids},
+		"excludeGenres":     &graphql.ArgumentConfig{Type: ids},
+		"pages":             &graphql.ArgumentConfig{Type: rangeInput},
+		"years":             &graphql.ArgumentConfig{Type: rangeInput},
+		"minRating":         &graphql.ArgumentConfig{Type: graphql.Float},
+		"isbn":              &graphql.ArgumentConfig{Type: graphql.String},
+		"languages":         &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"firstInSeriesOnly": &graphql.ArgumentConfig{Type: graphql.Boolean},
+		"excludeRead":       &graphql.ArgumentConfig{Type: graphql.Boolean},
+	}
+	if paged {
+		args["sort"] = &graphql.ArgumentConfig{Type: ranker}
+		args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultLimit}
+		args["offset"] = &graphql.ArgumentConfig{Type: graphql.Int}
+	}
+	return args
+}
+
+var (
+	rangeInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "Range",
+		Description: "An inclusive range, a missing bound leaves it open",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"min": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"max": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+	genreMatch = graphql.NewEnum(graphql.EnumConfig{
+		Name: "GenreMatch",
+		Values: graphql.EnumValueConfigMap{
+			"ANY": &graphql.EnumValueConfig{Value: models.GenreMatchAny},
+			"ALL": &graphql.EnumValueConfig{Value: models.GenreMatchAll},
+		},
+	})
+	ranker = graphql.NewEnum(graphql.EnumConfig{
+		Name: "Ranker",
+		Values: graphql.EnumValueConfigMap{
+			"RATING":   &graphql.EnumValueConfig{Value: models.RankerRating},
+			"WEIGHTED": &graphql.EnumValueConfig{Value: models.RankerWeighted},
+			"RECENCY":  &graphql.EnumValueConfig{Value: models.RankerRecency},
+			"RANDOM":   &graphql.EnumValueConfig{Value: models.RankerRandom},
+		},
+	})
+)
+
+// toBookRequest returns the BookRequest of the arguments, validated as the body of a
+// search of books, whose fields they share
+func toBookRequest(p graphql.ResolveParams) (models.BookRequest, error) {
+	content, err := json.Marshal(p.Args)
+	if err != nil {
+		return models.BookRequest{}, err
+	}
+	var search models.BookSearchRequest
+	if err := json.Unmarshal(content, &search); err != nil {
+		return models.BookRequest{}, err
+	}
+	if err := search.Validate(); err != nil {
+		return models.BookRequest{}, publicError{kind: models.KindInvalid, detail: err.Error()}
+	}
+
+	req := search.ToBookRequest()
+	if search.ExcludeRead {
+		userID, _ := p.Context.Value(userIDKey{}).(int64)
+		if userID == 0 {
+			return models.BookRequest{}, publicError{kind: models.KindInvalid, detail: "excludeRead needs an authenticated user"}
+		}
+		req.UserID = userID
+	}
+	return req, nil
+}
+
+// selectedFields returns the names of the fields selected on the field being resolved
+func selectedFields(p graphql.ResolveParams) []string {
+	var names []string
+	var collect func(set *ast.SelectionSet)
+	collect = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, selection := range set.Selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				names = append(names, selection.Name.Value)
+			case *ast.InlineFragment:
+				collect(selection.SelectionSet)
+			case *ast.FragmentSpread:
+				if fragment, ok := p.Info.Fragments[selection.Name.Value].(*ast.FragmentDefinition); ok {
+					collect(fragment.SelectionSet)
+				}
+			}
+		}
+	}
+	for _, field := range p.Info.FieldASTs {
+		collect(field.SelectionSet)
+	}
+
+	var facets []string
+	for _, name := range models.ParseFacets(strings.Join(names, ",")) {
+		for _, facet := range models.FacetNames {
+			if name == facet {
+				facets = append(facets, name)
+			}
+		}
+	}
+	return facets
+}
+
+// fail hides the errors that are not domain errors from the client, logging them
+func (r Resolver) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+	var domainErr *models.Error
+	if errors.As(err, &domainErr) {
+		return publicError{kind: domainErr.Kind, detail: domainErr.Detail}
+	}
+	r.Logger.WithError(err).Error("internal server error")
+	return errInternal
+}
+
+// publicError is a domain error as shown to the client, with its kind as code
+type publicError struct {
+	kind   models.ErrorKind
+	detail string
+}
+
+func (e publicError) Error() string {
+	return e.detail
+}
+
+func (e publicError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.kind}
+}
+
+// failThunk hides the errors of a thunk that are not domain errors from the client
+func (r Resolver) failThunk(load func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := load()
+		return value, r.fail(err)
+	}
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Job is a unit of background work
+type Job func(ctx context.Context) error
+
+// Schedule runs the job right away and then every interval until the context is done.
+// Failures are logged and the job is retried at the next tick. It blocks, so it is
+// meant to be started in its own goroutine.
+func Schedule(ctx context.Context, logger *log.Entry, interval time.Duration, job Job) {
+	if interval <= 0 {
+		logger.Info("job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := job(ctx); err != nil {
+			logger.WithError(err).Error("job failed")
+		} else {
+			logger.WithField("duration", time.Since(start)).Info("job done")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
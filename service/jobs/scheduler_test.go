@@ -0,0 +1,47 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/book-recommendations/service/jobs"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int64
+	done := make(chan struct{})
+
+	go func() {
+		jobs.Schedule(ctx, log.NewEntry(log.New()), 5*time.Millisecond, func(ctx context.Context) error {
+			if runs.Add(1) >= 3 {
+				cancel()
+			}
+			return errors.New("failures should not stop the schedule")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("schedule should stop once the context is done")
+	}
+	assert.Equal(t, int64(3), runs.Load())
+}
+
+func TestSchedule_Disabled(t *testing.T) {
+	var runs atomic.Int64
+
+	jobs.Schedule(context.Background(), log.NewEntry(log.New()), 0, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	assert.Equal(t, int64(0), runs.Load())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,36 +12,92 @@ import (
 	"time"
 
 	"github.com/book-recommendations/service/api"
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/stores"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := api.Commands[os.Args[1]]; ok {
+			if err := runCommand(command, os.Args[2:]); err != nil {
+				fmt.Println("error :", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	if err := run(); err != nil {
 		fmt.Println("error :", err)
 		os.Exit(1)
 	}
 }
 
+// runCommand runs a subcommand of the service instead of serving the API, until it is done
+// or interrupted
+func runCommand(command api.Command, args []string) error {
+	configValues, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error init config application: %w", err)
+	}
+
+	storeAdapter, err := stores.NewStore(configValues.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("error initializing database: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return command(ctx, configValues, storeAdapter, args, os.Stdout)
+}
+
 func run() error {
-	router := api.Routes()
+	configValues, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error init config application: %w", err)
+	}
+
+	storeAdapter, err := stores.NewStore(configValues.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("error initializing database: %w", err)
+	}
+
+	router := api.Routes(configValues, storeAdapter)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	api.StartJobs(jobsCtx, configValues, storeAdapter)
 
 	srv := &http.Server{
-		Addr:         ":5001",
+		Addr:         ":" + configValues.HTTPPort,
 		WriteTimeout: time.Second * time.Duration(10),
 		ReadTimeout:  time.Second * time.Duration(10),
 		IdleTimeout:  time.Second * time.Duration(60),
 		Handler:      router,
 	}
 
+	grpcListener, err := net.Listen("tcp", ":"+configValues.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("error listening for grpc: %w", err)
+	}
+	grpcSrv := api.GRPCServer(configValues, storeAdapter)
+
 	log.Info("setting up api shutdown")
 
-	serverErr := make(chan error, 1)
+	serverErr := make(chan error, 2)
 
 	go func() {
 		log.Info("starting server")
 		serverErr <- srv.ListenAndServe()
 	}()
 
+	go func() {
+		log.WithField("port", configValues.GRPCPort).Info("starting grpc server")
+		serverErr <- grpcSrv.Serve(grpcListener)
+	}()
+
 	shutdown := make(chan os.Signal, 1)
 	// We'll accept graceful shutdowns when quit via SIGINT or SIGKILL
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -55,9 +112,21 @@ func run() error {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(60))
 		defer cancel()
 
-		// Attempt to gracefully shutdown API
+		// Attempt to gracefully shutdown API, the gRPC server along with it
+		grpcStopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(grpcStopped)
+		}()
+
 		err := srv.Shutdown(ctx)
 
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			grpcSrv.Stop()
+		}
+
 		if err != nil {
 			log.WithError(err).Error("graceful shutdown failed")
 			err = srv.Close()
@@ -0,0 +1,113 @@
+package mediators
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuditMediator specifies the methods to read the audit log and enforce its retention
+type AuditMediator interface {
+	Get(ctx context.Context, req models.AuditRequest) ([]models.AuditEntry, error)
+	Export(ctx context.Context, req models.AuditRequest, fn func(models.AuditEntry) error) error
+	Prune(ctx context.Context) error
+}
+
+// auditMediator is the concrete implementation of the AuditMediator interface
+type auditMediator struct {
+	logger *log.Entry
+	store  stores.AuditStore
+	cfg    config.AuditConfig
+	now    func() time.Time
+}
+
+// NewAuditMediator returns a new instance of AuditMediator
+func NewAuditMediator(logger *log.Entry, auditStore stores.AuditStore, cfg config.AuditConfig) AuditMediator {
+	return &auditMediator{
+		logger: logger,
+		store:  auditStore,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// Get returns the latest entries matching the request, DefaultAuditEntries of them unless
+// the request gives a limit
+func (m *auditMediator) Get(ctx context.Context, req models.AuditRequest) ([]models.AuditEntry, error) {
+	filter, err := toAuditFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = models.DefaultAuditEntries
+	}
+
+	return m.store.GetAuditEntries(ctx, filter)
+}
+
+// Export calls fn with every entry matching the request, latest first, as they are read.
+// There is no limit unless the request gives one.
+func (m *auditMediator) Export(ctx context.Context, req models.AuditRequest, fn func(models.AuditEntry) error) error {
+	filter, err := toAuditFilter(req)
+	if err != nil {
+		return err
+	}
+
+	return m.store.ExportAuditEntries(ctx, filter, fn)
+}
+
+// Prune deletes the entries older than the retention, the entries are kept forever when it is zero
+func (m *auditMediator) Prune(ctx context.Context) error {
+	if m.cfg.Retention <= 0 {
+		return nil
+	}
+
+	deleted, err := m.store.DeleteAuditEntries(ctx, m.now().Add(-m.cfg.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		m.logger.WithField("deleted", deleted).Info("audit log pruned")
+	}
+
+	return nil
+}
+
+// toAuditFilter parses the validated request, its empty parameters matching every entry
+func toAuditFilter(req models.AuditRequest) (models.AuditFilter, error) {
+	filter := models.AuditFilter{Entity: req.Entity, Actor: req.Actor}
+
+	var err error
+	if req.ID != "" {
+		if filter.EntityID, err = strconv.ParseInt(req.ID, 10, 64); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if req.Since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, req.Since); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if req.Until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, req.Until); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if req.Limit != "" {
+		if filter.Limit, err = strconv.ParseInt(req.Limit, 10, 64); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if req.Offset != "" {
+		if filter.Offset, err = strconv.ParseInt(req.Offset, 10, 64); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
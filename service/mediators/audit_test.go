@@ -0,0 +1,145 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type AuditStoreMock struct {
+	EntryField   []models.AuditEntry
+	FilterField  *models.AuditFilter
+	BeforeField  time.Time
+	DeletedField int64
+	ErrorField   error
+}
+
+func (m *AuditStoreMock) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	m.FilterField = &filter
+	return m.EntryField, m.ErrorField
+}
+
+func (m *AuditStoreMock) ExportAuditEntries(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEntry) error) error {
+	m.FilterField = &filter
+	for _, entry := range m.EntryField {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return m.ErrorField
+}
+
+func (m *AuditStoreMock) DeleteAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	m.BeforeField = before
+	return m.DeletedField, m.ErrorField
+}
+
+func TestAuditMediator_Get(t *testing.T) {
+	var cases = []struct {
+		name   string
+		req    models.AuditRequest
+		store  *AuditStoreMock
+		assert func(store *AuditStoreMock, err error)
+	}{
+		{
+			name:  "entity history",
+			req:   models.AuditRequest{Entity: models.AuditSize, ID: "3", Since: "2024-03-01T10:00:00Z"},
+			store: &AuditStoreMock{},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, models.AuditSize, store.FilterField.Entity)
+				assert.Equal(t, int64(3), store.FilterField.EntityID)
+				assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), store.FilterField.Since.UTC())
+				assert.True(t, store.FilterField.Until.IsZero())
+				assert.Equal(t, int64(models.DefaultAuditEntries), store.FilterField.Limit)
+			},
+		},
+		{
+			name:  "page",
+			req:   models.AuditRequest{Actor: "user:42", Limit: "10", Offset: "20"},
+			store: &AuditStoreMock{},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user:42", store.FilterField.Actor)
+				assert.Equal(t, int64(10), store.FilterField.Limit)
+				assert.Equal(t, int64(20), store.FilterField.Offset)
+			},
+		},
+		{
+			name:  "failure",
+			store: &AuditStoreMock{ErrorField: errors.New("Error")},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewAuditMediator(log.NewEntry(log.New()), c.store, config.AuditConfig{})
+			_, err := m.Get(context.Background(), c.req)
+			c.assert(c.store, err)
+		})
+	}
+}
+
+func TestAuditMediator_Export(t *testing.T) {
+	store := &AuditStoreMock{EntryField: []models.AuditEntry{{ID: 2}, {ID: 1}}}
+	m := mediators.NewAuditMediator(log.NewEntry(log.New()), store, config.AuditConfig{})
+
+	var exported []int64
+	err := m.Export(context.Background(), models.AuditRequest{Entity: models.AuditEra}, func(entry models.AuditEntry) error {
+		exported = append(exported, entry.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, exported)
+	assert.Zero(t, store.FilterField.Limit, "the export has no limit unless one is given")
+}
+
+func TestAuditMediator_Prune(t *testing.T) {
+	var cases = []struct {
+		name      string
+		retention time.Duration
+		store     *AuditStoreMock
+		assert    func(store *AuditStoreMock, err error)
+	}{
+		{
+			name:      "older than the retention",
+			retention: 30 * 24 * time.Hour,
+			store:     &AuditStoreMock{DeletedField: 12},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), store.BeforeField, time.Minute)
+			},
+		},
+		{
+			name:  "kept forever",
+			store: &AuditStoreMock{},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.True(t, store.BeforeField.IsZero())
+			},
+		},
+		{
+			name:      "failure",
+			retention: time.Hour,
+			store:     &AuditStoreMock{ErrorField: errors.New("Error")},
+			assert: func(store *AuditStoreMock, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewAuditMediator(log.NewEntry(log.New()), c.store, config.AuditConfig{Retention: c.retention})
+			c.assert(c.store, m.Prune(context.Background()))
+		})
+	}
+}
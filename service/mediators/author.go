@@ -2,6 +2,7 @@ package mediators
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/book-recommendations/service/models"
 	"github.com/book-recommendations/service/stores"
@@ -11,19 +12,24 @@ import (
 // AuthorMediator specifies the methods to get authors
 type AuthorMediator interface {
 	Get(ctx context.Context) ([]models.Author, error)
+	GetAuthor(ctx context.Context, req models.AuthorRequest) (models.AuthorDetail, error)
+	GetBooks(ctx context.Context, req models.AuthorBooksRequest) ([]models.Book, error)
 }
 
 // authorMediator is the concrete implementation of the AuthorMediator interface
 type authorMediator struct {
 	logger *log.Entry
 	store  stores.AuthorStore
+	books  BookMediator
 }
 
-// NewAuthorMediator returns a new instance of AuthorMediator
-func NewAuthorMediator(logger *log.Entry, authorStore stores.AuthorStore) AuthorMediator {
+// NewAuthorMediator returns a new instance of AuthorMediator, the books of the authors
+// being filtered and ranked by bookMediator
+func NewAuthorMediator(logger *log.Entry, authorStore stores.AuthorStore, bookMediator BookMediator) AuthorMediator {
 	return &authorMediator{
 		logger: logger,
 		store:  authorStore,
+		books:  bookMediator,
 	}
 }
 
@@ -39,3 +45,28 @@ func (m *authorMediator) Get(ctx context.Context) ([]models.Author, error) {
 
 	return authors, nil
 }
+
+// GetAuthor returns an author along with the figures of their books, or a not found
+// error when there is no such author
+func (m *authorMediator) GetAuthor(ctx context.Context, req models.AuthorRequest) (models.AuthorDetail, error) {
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		return models.AuthorDetail{}, err
+	}
+
+	return m.store.GetAuthor(ctx, id)
+}
+
+// GetBooks returns the books of an author matching the filters of the request, ranked as
+// any list of books, or a not found error when there is no such author. The author, the
+// one they were merged into if they were, takes the place of any authors filter of the request.
+func (m *authorMediator) GetBooks(ctx context.Context, req models.AuthorBooksRequest) ([]models.Book, error) {
+	author, err := m.GetAuthor(ctx, models.AuthorRequest{ID: req.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	bookReq := req.Books
+	bookReq.Authors = strconv.FormatInt(author.ID, 10)
+	return m.books.Get(ctx, bookReq)
+}
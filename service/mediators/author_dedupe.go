@@ -0,0 +1,82 @@
+package mediators
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuthorDedupeMediator specifies the methods to find and merge duplicate authors
+type AuthorDedupeMediator interface {
+	Duplicates(ctx context.Context, req models.DuplicateAuthorsRequest) ([]models.DuplicateAuthors, error)
+	Merge(ctx context.Context, id int64, req models.MergeAuthorRequest) (models.AuthorDetail, error)
+}
+
+// authorDedupeMediator is the concrete implementation of the AuthorDedupeMediator interface
+type authorDedupeMediator struct {
+	logger *log.Entry
+	store  stores.AuthorStore
+	cfg    config.DedupeConfig
+}
+
+// NewAuthorDedupeMediator returns a new instance of AuthorDedupeMediator
+func NewAuthorDedupeMediator(logger *log.Entry, authorStore stores.AuthorStore, cfg config.DedupeConfig) AuthorDedupeMediator {
+	return &authorDedupeMediator{
+		logger: logger,
+		store:  authorStore,
+		cfg:    cfg,
+	}
+}
+
+// Duplicates returns the candidate duplicate authors, the most likely first, for review.
+// The request defaults to the configured similarity and to DefaultDuplicateAuthors pairs.
+// Each pair suggests keeping the author of the most books, the oldest one on a tie.
+func (m *authorDedupeMediator) Duplicates(ctx context.Context, req models.DuplicateAuthorsRequest) ([]models.DuplicateAuthors, error) {
+	filter := models.DuplicateAuthorsFilter{
+		MinSimilarity: m.cfg.MinSimilarity,
+		Limit:         models.DefaultDuplicateAuthors,
+	}
+	var err error
+	if req.MinSimilarity != "" {
+		if filter.MinSimilarity, err = strconv.ParseFloat(req.MinSimilarity, 64); err != nil {
+			return nil, err
+		}
+	}
+	if req.Limit != "" {
+		if filter.Limit, err = strconv.ParseInt(req.Limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	duplicates, err := m.store.GetDuplicateAuthors(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range duplicates {
+		if pair.Merge.BookCount > pair.Keep.BookCount ||
+			(pair.Merge.BookCount == pair.Keep.BookCount && pair.Merge.ID < pair.Keep.ID) {
+			duplicates[i].Keep, duplicates[i].Merge = pair.Merge, pair.Keep
+		}
+	}
+
+	return duplicates, nil
+}
+
+// Merge merges the author with the given id into the one of the request and returns the
+// latter, whose id the merged one now resolves to
+func (m *authorDedupeMediator) Merge(ctx context.Context, id int64, req models.MergeAuthorRequest) (models.AuthorDetail, error) {
+	if id == req.Into {
+		return models.AuthorDetail{}, models.NewError(models.KindInvalid, "author %d cannot be merged into themselves", id)
+	}
+
+	if err := m.store.MergeAuthors(ctx, id, req.Into); err != nil {
+		return models.AuthorDetail{}, err
+	}
+	m.logger.WithField("author", id).WithField("into", req.Into).Info("author merged")
+
+	return m.store.GetAuthor(ctx, req.Into)
+}
@@ -0,0 +1,115 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorDedupeMediator_Duplicates(t *testing.T) {
+	candidate := func(id, books int64) models.AuthorCandidate {
+		return models.AuthorCandidate{Author: models.Author{ID: id}, BookCount: books}
+	}
+
+	var cases = []struct {
+		name   string
+		req    models.DuplicateAuthorsRequest
+		store  *AuthorStoreMock
+		assert func(store *AuthorStoreMock, duplicates []models.DuplicateAuthors, err error)
+	}{
+		{
+			name: "keeps the author of the most books",
+			store: &AuthorStoreMock{DuplicatesField: []models.DuplicateAuthors{
+				{Keep: candidate(3, 1), Merge: candidate(8, 4), SameNormalizedName: true},
+				{Keep: candidate(2, 2), Merge: candidate(5, 2), NameSimilarity: 0.7},
+			}},
+			assert: func(store *AuthorStoreMock, duplicates []models.DuplicateAuthors, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, models.DuplicateAuthorsFilter{MinSimilarity: 0.6, Limit: models.DefaultDuplicateAuthors}, *store.FilterField)
+				assert.Equal(t, int64(8), duplicates[0].Keep.ID)
+				assert.Equal(t, int64(3), duplicates[0].Merge.ID)
+				assert.Equal(t, int64(2), duplicates[1].Keep.ID, "the oldest author should be kept on a tie")
+			},
+		},
+		{
+			name:  "requested similarity",
+			req:   models.DuplicateAuthorsRequest{MinSimilarity: "0.85", Limit: "10"},
+			store: &AuthorStoreMock{},
+			assert: func(store *AuthorStoreMock, duplicates []models.DuplicateAuthors, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, models.DuplicateAuthorsFilter{MinSimilarity: 0.85, Limit: 10}, *store.FilterField)
+			},
+		},
+		{
+			name:  "failure",
+			store: &AuthorStoreMock{ErrorField: errors.New("Error")},
+			assert: func(store *AuthorStoreMock, duplicates []models.DuplicateAuthors, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewAuthorDedupeMediator(log.NewEntry(log.New()), c.store, config.DedupeConfig{MinSimilarity: 0.6})
+			duplicates, err := m.Duplicates(context.Background(), c.req)
+			c.assert(c.store, duplicates, err)
+		})
+	}
+}
+
+func TestAuthorDedupeMediator_Merge(t *testing.T) {
+	var cases = []struct {
+		name   string
+		id     int64
+		into   int64
+		store  *AuthorStoreMock
+		assert func(store *AuthorStoreMock, author models.AuthorDetail, err error)
+	}{
+		{
+			name:  "success",
+			id:    9,
+			into:  6,
+			store: &AuthorStoreMock{DetailField: models.AuthorDetail{Author: models.Author{ID: 6}}},
+			assert: func(store *AuthorStoreMock, author models.AuthorDetail, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []int64{9, 6}, store.MergedField)
+				assert.Equal(t, int64(6), author.ID)
+			},
+		},
+		{
+			name:  "into themselves",
+			id:    6,
+			into:  6,
+			store: &AuthorStoreMock{DetailField: models.AuthorDetail{Author: models.Author{ID: 6}}},
+			assert: func(store *AuthorStoreMock, author models.AuthorDetail, err error) {
+				var domainErr *models.Error
+				require.True(t, errors.As(err, &domainErr))
+				assert.Equal(t, models.KindInvalid, domainErr.Kind)
+				assert.Nil(t, store.MergedField)
+			},
+		},
+		{
+			name:  "unknown author",
+			id:    9,
+			into:  7,
+			store: &AuthorStoreMock{ErrorField: models.NewError(models.KindNotFound, "author 7 not found")},
+			assert: func(store *AuthorStoreMock, author models.AuthorDetail, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewAuthorDedupeMediator(log.NewEntry(log.New()), c.store, config.DedupeConfig{})
+			author, err := m.Merge(context.Background(), c.id, models.MergeAuthorRequest{Into: c.into})
+			c.assert(c.store, author, err)
+		})
+	}
+}
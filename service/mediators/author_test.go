@@ -13,14 +13,49 @@ import (
 )
 
 type AuthorStoreMock struct {
-	AuthorField []models.Author
-	ErrorField  error
+	AuthorField     []models.Author
+	DetailField     models.AuthorDetail
+	RedirectField   map[int64]int64
+	DuplicatesField []models.DuplicateAuthors
+	FilterField     *models.DuplicateAuthorsFilter
+	MergedField     []int64
+	ErrorField      error
 }
 
 func (m *AuthorStoreMock) GetAllAuthors(ctx context.Context) ([]models.Author, error) {
 	return m.AuthorField, m.ErrorField
 }
 
+func (m *AuthorStoreMock) GetAuthor(ctx context.Context, id int64) (models.AuthorDetail, error) {
+	if m.ErrorField != nil {
+		return models.AuthorDetail{}, m.ErrorField
+	}
+	if to, ok := m.RedirectField[id]; ok {
+		id = to
+	}
+	if m.DetailField.ID != id {
+		return models.AuthorDetail{}, models.NewError(models.KindNotFound, "author %d not found", id)
+	}
+	return m.DetailField, nil
+}
+
+func (m *AuthorStoreMock) GetDuplicateAuthors(ctx context.Context, filter models.DuplicateAuthorsFilter) ([]models.DuplicateAuthors, error) {
+	m.FilterField = &filter
+	return m.DuplicatesField, m.ErrorField
+}
+
+func (m *AuthorStoreMock) MergeAuthors(ctx context.Context, fromID, intoID int64) error {
+	if m.ErrorField != nil {
+		return m.ErrorField
+	}
+	m.MergedField = []int64{fromID, intoID}
+	if m.RedirectField == nil {
+		m.RedirectField = map[int64]int64{}
+	}
+	m.RedirectField[fromID] = intoID
+	return nil
+}
+
 func TestAuthorController_Get(t *testing.T) {
 	var cases = []struct {
 		name   string
@@ -69,8 +104,100 @@ func TestAuthorController_Get(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		m := mediators.NewAuthorMediator(log.NewEntry(log.New()), c.store)
+		m := mediators.NewAuthorMediator(log.NewEntry(log.New()), c.store, nil)
 		res, err := m.Get(context.Background())
 		c.assert(res, err)
 	}
 }
+
+func TestAuthorMediator_GetAuthor(t *testing.T) {
+	detail := models.AuthorDetail{
+		Author:        models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"},
+		BookCount:     2,
+		AverageRating: 3.4,
+		YearsActive:   &models.YearsActive{From: 1972, To: 1999},
+		MainGenres:    []models.GenreCount{{Genre: models.Genre{ID: 8, Title: "Childrens"}, BookCount: 2}},
+	}
+
+	var cases = []struct {
+		name    string
+		store   stores.AuthorStore
+		request models.AuthorRequest
+		assert  func(author models.AuthorDetail, err error)
+	}{
+		{
+			name:    "success",
+			store:   &AuthorStoreMock{DetailField: detail},
+			request: models.AuthorRequest{ID: "6"},
+			assert: func(author models.AuthorDetail, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, detail, author)
+			},
+		},
+		{
+			name:    "not found",
+			store:   &AuthorStoreMock{DetailField: detail},
+			request: models.AuthorRequest{ID: "7"},
+			assert: func(author models.AuthorDetail, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			},
+		},
+	}
+	for _, c := range cases {
+		m := mediators.NewAuthorMediator(log.NewEntry(log.New()), c.store, nil)
+		author, err := m.GetAuthor(context.Background(), c.request)
+		c.assert(author, err)
+	}
+}
+
+func TestAuthorMediator_GetBooks(t *testing.T) {
+	var cases = []struct {
+		name    string
+		store   stores.AuthorStore
+		request models.AuthorBooksRequest
+		assert  func(bookStore *BookStoreMock, books []models.Book, err error)
+	}{
+		{
+			name:  "success",
+			store: &AuthorStoreMock{DetailField: models.AuthorDetail{Author: models.Author{ID: 6}}},
+			request: models.AuthorBooksRequest{
+				ID:    "6",
+				Books: models.BookRequest{Authors: "1,2", Genres: "8", Limit: "1", Ranker: models.RankerRating},
+			},
+			assert: func(bookStore *BookStoreMock, books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "6", bookStore.RequestField.Authors)
+				assert.Equal(t, "8", bookStore.RequestField.Genres)
+				assert.Equal(t, []models.Book{{ID: 2, Rating: 4}}, books)
+			},
+		},
+		{
+			name: "merged author",
+			store: &AuthorStoreMock{
+				DetailField:   models.AuthorDetail{Author: models.Author{ID: 6}},
+				RedirectField: map[int64]int64{9: 6},
+			},
+			request: models.AuthorBooksRequest{ID: "9"},
+			assert: func(bookStore *BookStoreMock, books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "6", bookStore.RequestField.Authors, "the books should be the ones of the author merged into")
+			},
+		},
+		{
+			name:    "not found",
+			store:   &AuthorStoreMock{DetailField: models.AuthorDetail{Author: models.Author{ID: 6}}},
+			request: models.AuthorBooksRequest{ID: "7"},
+			assert: func(bookStore *BookStoreMock, books []models.Book, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+				assert.Empty(t, bookStore.RequestField.Authors, "the books of an unknown author should not be fetched")
+			},
+		},
+	}
+	for _, c := range cases {
+		bookStore := &BookStoreMock{BookField: []models.Book{{ID: 1, Rating: 3}, {ID: 2, Rating: 4}}}
+		bookMediator := mediators.NewBookMediator(log.NewEntry(log.New()), bookStore, similarityWeights, rankerConfig)
+		m := mediators.NewAuthorMediator(log.NewEntry(log.New()), c.store, bookMediator)
+		books, err := m.GetBooks(context.Background(), c.request)
+		c.assert(bookStore, books, err)
+	}
+}
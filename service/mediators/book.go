@@ -2,39 +2,238 @@ package mediators
 
 import (
 	"context"
+	"sort"
+	"strconv"
 
+	"github.com/book-recommendations/service/config"
 	"github.com/book-recommendations/service/models"
 	"github.com/book-recommendations/service/stores"
 	log "github.com/sirupsen/logrus"
 )
 
-// BookMediator specifies the methods to get books
+// BookMediator specifies the methods to get, delete and restore books
 type BookMediator interface {
 	Get(ctx context.Context, req models.BookRequest) ([]models.Book, error)
+	Count(ctx context.Context, req models.BookRequest) (int64, error)
+	Similar(ctx context.Context, req models.SimilarBooksRequest) ([]models.SimilarBook, error)
+	GetBook(ctx context.Context, id int64) (models.Book, error)
+	GetHistory(ctx context.Context, id int64) ([]models.BookVersion, error)
+	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (models.Book, error)
+	Facets(ctx context.Context, req models.BookRequest) (models.Facets, error)
 }
 
 // bookMediator is the concrete implementation of the BookMediator interface
 type bookMediator struct {
-	logger *log.Entry
-	store  stores.BookStore
+	logger     *log.Entry
+	store      stores.BookStore
+	similarity config.SimilarityConfig
+	ranker     config.RankerConfig
 }
 
 // NewBookMediator returns a new instance of BookMediator
-func NewBookMediator(logger *log.Entry, bookStore stores.BookStore) BookMediator {
+func NewBookMediator(logger *log.Entry, bookStore stores.BookStore, similarity config.SimilarityConfig, ranker config.RankerConfig) BookMediator {
 	return &bookMediator{
-		logger: logger,
-		store:  bookStore,
+		logger:     logger,
+		store:      bookStore,
+		similarity: similarity,
+		ranker:     ranker,
 	}
 }
 
-// Get returns a list of Books
+// Get returns a list of Books. The store fetches a pool of candidates matching the
+// request, which the requested ranker reorders before the offset and the limit are applied.
+// The pool holds PoolFactor times the books asked, up to PoolSize, and the whole catalog
+// when the request has no limit.
 func (m *bookMediator) Get(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
-	var books []models.Book
+	rankerName := m.rankerName(req)
+	ranker, err := NewRanker(rankerName, m.ranker)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(0)
+	if req.Limit != "" {
+		if limit, err = strconv.ParseInt(req.Limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	offset := int64(0)
+	if req.Offset != "" {
+		if offset, err = strconv.ParseInt(req.Offset, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	poolReq := req
+	poolReq.Limit = ""
+	if limit > 0 {
+		pool := max(min((offset+limit)*m.ranker.PoolFactor, m.ranker.PoolSize), offset+limit)
+		poolReq.Limit = strconv.FormatInt(pool, 10)
+	}
+	// pick the pool by raw rating when that is what the ranker orders by
+	if poolReq.Ranking == "" && rankerName == models.RankerRating {
+		poolReq.Ranking = models.RankingRaw
+	}
 
-	books, err := m.store.GetBooks(ctx, req)
+	books, err := m.store.GetBooks(ctx, poolReq)
 	if err != nil {
 		return nil, err
 	}
 
+	books = ranker.Rank(books)
+	books = books[min(offset, int64(len(books))):]
+	if limit > 0 && int64(len(books)) > limit {
+		books = books[:limit]
+	}
+
 	return books, nil
 }
+
+// Count returns the number of books matching the request, whatever its limit and offset
+func (m *bookMediator) Count(ctx context.Context, req models.BookRequest) (int64, error) {
+	return m.store.CountBooks(ctx, req)
+}
+
+// Facets returns the counts of the facets asked by the request. They ignore the limit,
+// and every count ignores the filters of its own facet.
+func (m *bookMediator) Facets(ctx context.Context, req models.BookRequest) (models.Facets, error) {
+	return m.store.GetFacets(ctx, req, models.ParseFacets(req.Facets))
+}
+
+// rankerName returns the ranker asked by the request. The ranking parameter predates
+// the rankers and keeps selecting the matching one when no ranker is given.
+func (m *bookMediator) rankerName(req models.BookRequest) string {
+	switch {
+	case req.Ranker != "":
+		return req.Ranker
+	case req.Ranking == models.RankingRaw:
+		return models.RankerRating
+	case req.Ranking == models.RankingWeighted:
+		return models.RankerWeighted
+	default:
+		return m.ranker.Default
+	}
+}
+
+// GetBook returns the book with the given id, models.ErrGone when it was deleted
+func (m *bookMediator) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	return m.store.GetBook(ctx, id)
+}
+
+// GetHistory returns every version of the book with the given id, latest first
+func (m *bookMediator) GetHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	return m.store.GetBookHistory(ctx, id)
+}
+
+// Delete marks the book with the given id deleted, leaving it out of every listing
+func (m *bookMediator) Delete(ctx context.Context, id int64) error {
+	return m.store.DeleteBook(ctx, id)
+}
+
+// Restore brings back the deleted book with the given id
+func (m *bookMediator) Restore(ctx context.Context, id int64) (models.Book, error) {
+	return m.store.RestoreBook(ctx, id)
+}
+
+// Similar returns the books most similar to the requested one, best match first
+func (m *bookMediator) Similar(ctx context.Context, req models.SimilarBooksRequest) ([]models.SimilarBook, error) {
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	limit := models.DefaultSimilarBooks
+	if req.Limit != "" {
+		if limit, err = strconv.Atoi(req.Limit); err != nil {
+			return nil, err
+		}
+	}
+
+	book, err := m.store.GetBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := m.store.GetSimilarCandidates(ctx, book, m.similarity.YearWindow, m.similarity.PagesWindow, m.similarity.Candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]models.SimilarBook, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ID == book.ID {
+			continue
+		}
+		similar = append(similar, models.SimilarBook{
+			Book:  candidate,
+			Score: similarityScore(book, candidate, m.similarity),
+		})
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].WeightedRating > similar[j].WeightedRating
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	return similar, nil
+}
+
+// similarityScore adds up the weight of every criterion the candidate shares with the book.
+// The year and pages criteria only count partially the further apart both books are.
+func similarityScore(book, candidate models.Book, weights config.SimilarityConfig) float64 {
+	var score float64
+	if sharesGenre(book, candidate) {
+		score += weights.GenreWeight
+	}
+	if sharesAuthor(book, candidate) {
+		score += weights.AuthorWeight
+	}
+	score += weights.YearWeight * proximity(book.YearPublished, candidate.YearPublished, weights.YearWindow)
+	score += weights.PagesWeight * proximity(book.Pages, candidate.Pages, weights.PagesWindow)
+
+	return score
+}
+
+// proximity is 1 when both values are equal and decreases linearly to 0 at window
+func proximity(a, b, window int64) float64 {
+	if window <= 0 {
+		return 0
+	}
+	distance := a - b
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance >= window {
+		return 0
+	}
+
+	return 1 - float64(distance)/float64(window)
+}
+
+// sharesGenre tells whether both books have at least one genre in common
+func sharesGenre(a, b models.Book) bool {
+	for _, genreA := range a.Genres {
+		for _, genreB := range b.Genres {
+			if genreA.ID == genreB.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharesAuthor tells whether both books have at least one contributor in common, whatever their role
+func sharesAuthor(a, b models.Book) bool {
+	for _, authorA := range a.Authors {
+		for _, authorB := range b.Authors {
+			if authorA.ID == authorB.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
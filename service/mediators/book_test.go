@@ -3,8 +3,10 @@ package mediators_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
+	"github.com/book-recommendations/service/config"
 	"github.com/book-recommendations/service/mediators"
 	"github.com/book-recommendations/service/models"
 	"github.com/book-recommendations/service/stores"
@@ -13,14 +15,114 @@ import (
 )
 
 type BookStoreMock struct {
-	BookField  []models.Book
-	ErrorField error
+	BookField    []models.Book
+	FacetsField  models.Facets
+	CountField   int64
+	ErrorField   error
+	RequestField models.BookRequest
+	FacetNames   []string
+	DeletedField []int64
+	// OutOfPoolField are the books GetBooks leaves out, as if ranked past the pool
+	OutOfPoolField []int64
+	// ByIDsRequestField is the request of the last GetBooksByIDs
+	ByIDsRequestField models.BookRequest
+}
+
+var similarityWeights = config.SimilarityConfig{
+	GenreWeight:  3,
+	AuthorWeight: 2,
+	YearWeight:   1,
+	PagesWeight:  1,
+	YearWindow:   20,
+	PagesWindow:  200,
+	Candidates:   250,
+}
+
+var rankerConfig = config.RankerConfig{
+	Default:       models.RankerWeighted,
+	PoolSize:      100,
+	PoolFactor:    3,
+	RecencyBoost:  1,
+	RecencyWindow: 10,
 }
 
 func (m *BookStoreMock) GetBooks(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
+	m.RequestField = req
+	if len(m.OutOfPoolField) == 0 {
+		return m.BookField, m.ErrorField
+	}
+	books := make([]models.Book, 0, len(m.BookField))
+	for _, book := range m.BookField {
+		if !slices.Contains(m.OutOfPoolField, book.ID) {
+			books = append(books, book)
+		}
+	}
+	return books, m.ErrorField
+}
+
+func (m *BookStoreMock) CountBooks(ctx context.Context, req models.BookRequest) (int64, error) {
+	m.RequestField = req
+	return m.CountField, m.ErrorField
+}
+
+func (m *BookStoreMock) GetFacets(ctx context.Context, req models.BookRequest, facets []string) (models.Facets, error) {
+	m.RequestField = req
+	m.FacetNames = facets
+	return m.FacetsField, m.ErrorField
+}
+
+func (m *BookStoreMock) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	if m.ErrorField != nil {
+		return models.Book{}, m.ErrorField
+	}
+	for _, deleted := range m.DeletedField {
+		if deleted == id {
+			return models.Book{}, models.NewError(models.KindGone, "book %d was deleted", id)
+		}
+	}
+	for _, book := range m.BookField {
+		if book.ID == id {
+			return book, nil
+		}
+	}
+	return models.Book{}, models.ErrNotFound
+}
+
+func (m *BookStoreMock) GetBooksByIDs(ctx context.Context, ids []int64, req models.BookRequest) ([]models.Book, error) {
+	m.ByIDsRequestField = req
+	if m.ErrorField != nil {
+		return nil, m.ErrorField
+	}
+	books := make([]models.Book, 0, len(ids))
+	for _, id := range ids {
+		if book, err := m.GetBook(ctx, id); err == nil {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+func (m *BookStoreMock) GetBookHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	return []models.BookVersion{{Version: 1}}, m.ErrorField
+}
+
+func (m *BookStoreMock) DeleteBook(ctx context.Context, id int64) error {
+	m.DeletedField = append(m.DeletedField, id)
+	return m.ErrorField
+}
+
+func (m *BookStoreMock) RestoreBook(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{ID: id}, m.ErrorField
+}
+
+func (m *BookStoreMock) GetSimilarCandidates(ctx context.Context, book models.Book, yearWindow, pagesWindow, perCriterion int64) ([]models.Book, error) {
 	return m.BookField, m.ErrorField
 }
 
+func (m *BookStoreMock) UpdateRankingPrior(ctx context.Context, priorMean float64, priorWeight int64) error {
+	return m.ErrorField
+}
+
 func TestBookController_Get(t *testing.T) {
 	var cases = []struct {
 		name    string
@@ -66,10 +168,276 @@ func TestBookController_Get(t *testing.T) {
 				assert.NotNil(t, err)
 			},
 		},
+		{
+			name: "success - ranked then limited",
+			store: &BookStoreMock{
+				BookField: []models.Book{
+					{ID: 1, Rating: 4.9, WeightedRating: 3.1},
+					{ID: 2, Rating: 4.1, WeightedRating: 4.0},
+					{ID: 3, Rating: 3.5, WeightedRating: 3.4},
+				},
+			},
+			request: models.BookRequest{Limit: "2"},
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, books, 2)
+				assert.Equal(t, int64(2), books[0].ID)
+				assert.Equal(t, int64(3), books[1].ID)
+			},
+		},
+		{
+			name: "success - requested ranker",
+			store: &BookStoreMock{
+				BookField: []models.Book{
+					{ID: 1, Rating: 4.1, WeightedRating: 4.0},
+					{ID: 2, Rating: 4.9, WeightedRating: 3.1},
+				},
+			},
+			request: models.BookRequest{Ranker: models.RankerRating},
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, books, 2)
+				assert.Equal(t, int64(2), books[0].ID)
+			},
+		},
+		{
+			name: "success - legacy raw ranking",
+			store: &BookStoreMock{
+				BookField: []models.Book{
+					{ID: 1, Rating: 4.1, WeightedRating: 4.0},
+					{ID: 2, Rating: 4.9, WeightedRating: 3.1},
+				},
+			},
+			request: models.BookRequest{Ranking: models.RankingRaw},
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, int64(2), books[0].ID)
+			},
+		},
 	}
 	for _, c := range cases {
-		m := mediators.NewBookMediator(log.NewEntry(log.New()), c.store)
+		m := mediators.NewBookMediator(log.NewEntry(log.New()), c.store, similarityWeights, rankerConfig)
 		res, err := m.Get(context.Background(), c.request)
 		c.assert(res, err)
 	}
 }
+
+func TestBookMediator_Similar(t *testing.T) {
+	catalog := []models.Book{
+		{
+			ID:            1,
+			Title:         "Alanna Saves the Day",
+			YearPublished: 1972,
+			Pages:         169,
+			Genres:        []models.Genre{{ID: 8}},
+			Authors:       []models.BookAuthor{{Author: models.Author{ID: 6}, Role: models.RoleAuthor}},
+		},
+		{
+			ID:            2,
+			Title:         "Same genre and author, far apart",
+			YearPublished: 2010,
+			Pages:         900,
+			Genres:        []models.Genre{{ID: 8}},
+			Authors:       []models.BookAuthor{{Author: models.Author{ID: 6}, Role: models.RoleAuthor}},
+		},
+		{
+			ID:            3,
+			Title:         "Same genre, close by",
+			YearPublished: 1972,
+			Pages:         269,
+			Genres:        []models.Genre{{ID: 8}},
+			Authors:       []models.BookAuthor{{Author: models.Author{ID: 1}, Role: models.RoleAuthor}},
+		},
+		{
+			ID:             4,
+			Title:          "Same era only, well rated",
+			YearPublished:  1962,
+			Pages:          169,
+			WeightedRating: 4.5,
+			Genres:         []models.Genre{{ID: 1}},
+			Authors:        []models.BookAuthor{{Author: models.Author{ID: 2}, Role: models.RoleAuthor}},
+		},
+		{
+			ID:             5,
+			Title:          "Same era only, badly rated",
+			YearPublished:  1962,
+			Pages:          169,
+			WeightedRating: 1.5,
+			Genres:         []models.Genre{{ID: 1}},
+			Authors:        []models.BookAuthor{{Author: models.Author{ID: 3}, Role: models.RoleAuthor}},
+		},
+	}
+
+	var cases = []struct {
+		name    string
+		store   stores.BookStore
+		request models.SimilarBooksRequest
+		assert  func(books []models.SimilarBook, err error)
+	}{
+		{
+			name:    "success",
+			store:   &BookStoreMock{BookField: catalog},
+			request: models.SimilarBooksRequest{ID: "1"},
+			assert: func(books []models.SimilarBook, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, books, 4)
+				assert.Equal(t, int64(2), books[0].ID)
+				assert.Equal(t, 5.0, books[0].Score)
+				assert.Equal(t, int64(3), books[1].ID)
+				assert.Equal(t, 4.5, books[1].Score)
+				assert.Equal(t, int64(4), books[2].ID)
+				assert.Equal(t, 1.5, books[2].Score)
+				assert.Equal(t, int64(5), books[3].ID)
+			},
+		},
+		{
+			name:    "success - limit",
+			store:   &BookStoreMock{BookField: catalog},
+			request: models.SimilarBooksRequest{ID: "1", Limit: "1"},
+			assert: func(books []models.SimilarBook, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, books, 1)
+				assert.Equal(t, int64(2), books[0].ID)
+			},
+		},
+		{
+			name:    "failure - not found",
+			store:   &BookStoreMock{BookField: catalog},
+			request: models.SimilarBooksRequest{ID: "42"},
+			assert: func(books []models.SimilarBook, err error) {
+				assert.ErrorIs(t, err, models.ErrNotFound)
+			},
+		},
+		{
+			name:    "failure",
+			store:   &BookStoreMock{ErrorField: errors.New("Error")},
+			request: models.SimilarBooksRequest{ID: "1"},
+			assert: func(books []models.SimilarBook, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		m := mediators.NewBookMediator(log.NewEntry(log.New()), c.store, similarityWeights, rankerConfig)
+		res, err := m.Similar(context.Background(), c.request)
+		c.assert(res, err)
+	}
+}
+
+func TestBookMediator_GetPool(t *testing.T) {
+	store := &BookStoreMock{}
+	m := mediators.NewBookMediator(log.NewEntry(log.New()), store, similarityWeights, rankerConfig)
+
+	_, err := m.Get(context.Background(), models.BookRequest{Limit: "5"})
+	assert.Nil(t, err)
+	assert.Equal(t, "15", store.RequestField.Limit)
+
+	_, err = m.Get(context.Background(), models.BookRequest{Limit: "50"})
+	assert.Nil(t, err)
+	assert.Equal(t, "100", store.RequestField.Limit, "the pool should be capped")
+
+	_, err = m.Get(context.Background(), models.BookRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", store.RequestField.Limit, "the whole catalog should be ranked without a limit")
+
+	_, err = m.Get(context.Background(), models.BookRequest{Limit: "500", Ranker: models.RankerRating})
+	assert.Nil(t, err)
+	assert.Equal(t, "500", store.RequestField.Limit)
+	assert.Equal(t, models.RankingRaw, store.RequestField.Ranking)
+}
+
+func TestBookMediator_GetOffset(t *testing.T) {
+	store := &BookStoreMock{BookField: []models.Book{
+		{ID: 1, WeightedRating: 4},
+		{ID: 2, WeightedRating: 3},
+		{ID: 3, WeightedRating: 2},
+		{ID: 4, WeightedRating: 1},
+	}}
+	m := mediators.NewBookMediator(log.NewEntry(log.New()), store, similarityWeights, rankerConfig)
+
+	books, err := m.Get(context.Background(), models.BookRequest{Limit: "2", Offset: "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 3}, []int64{books[0].ID, books[1].ID})
+
+	books, err = m.Get(context.Background(), models.BookRequest{Limit: "90", Offset: "20"})
+	assert.Nil(t, err)
+	assert.Empty(t, books)
+	assert.Equal(t, "110", store.RequestField.Limit, "the pool should hold the skipped books")
+}
+
+func TestBookMediator_GetBook(t *testing.T) {
+	store := &BookStoreMock{BookField: []models.Book{{ID: 1, Title: "Alanna Saves the Day"}}}
+	m := mediators.NewBookMediator(log.NewEntry(log.New()), store, similarityWeights, rankerConfig)
+
+	book, err := m.GetBook(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alanna Saves the Day", book.Title)
+
+	_, err = m.GetBook(context.Background(), 2)
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+}
+
+func TestBookMediator_Delete(t *testing.T) {
+	store := &BookStoreMock{BookField: []models.Book{{ID: 1, Title: "Alanna Saves the Day"}}}
+	m := mediators.NewBookMediator(log.NewEntry(log.New()), store, similarityWeights, rankerConfig)
+
+	assert.Nil(t, m.Delete(context.Background(), 1))
+	_, err := m.GetBook(context.Background(), 1)
+	assert.True(t, errors.Is(err, models.ErrGone), "a deleted book should be gone rather than not found")
+
+	_, err = m.Similar(context.Background(), models.SimilarBooksRequest{ID: "1"})
+	assert.True(t, errors.Is(err, models.ErrGone))
+}
+
+func TestBookMediator_Count(t *testing.T) {
+	store := &BookStoreMock{CountField: 42}
+	m := mediators.NewBookMediator(log.NewEntry(log.New()), store, similarityWeights, rankerConfig)
+
+	total, err := m.Count(context.Background(), models.BookRequest{Genres: "3", Limit: "5"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), total)
+	assert.Equal(t, "3", store.RequestField.Genres)
+
+	store.ErrorField = errors.New("store error")
+	_, err = m.Count(context.Background(), models.BookRequest{})
+	assert.NotNil(t, err)
+}
+
+func TestBookMediator_Facets(t *testing.T) {
+	var cases = []struct {
+		name    string
+		store   *BookStoreMock
+		request models.BookRequest
+		assert  func(store *BookStoreMock, facets models.Facets, err error)
+	}{
+		{
+			name: "success",
+			store: &BookStoreMock{
+				FacetsField: models.Facets{models.FacetEras: {{ID: 2, Count: 7}}},
+			},
+			request: models.BookRequest{Facets: "eras,authors,eras", Limit: "5"},
+			assert: func(store *BookStoreMock, facets models.Facets, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{models.FacetEras, models.FacetAuthors}, store.FacetNames)
+				assert.Equal(t, "5", store.RequestField.Limit)
+				assert.Equal(t, []models.FacetCount{{ID: 2, Count: 7}}, facets[models.FacetEras])
+			},
+		},
+		{
+			name: "store error",
+			store: &BookStoreMock{
+				ErrorField: errors.New("store error"),
+			},
+			request: models.BookRequest{Facets: "genres"},
+			assert: func(store *BookStoreMock, facets models.Facets, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		mediator := mediators.NewBookMediator(log.NewEntry(log.New()), c.store, similarityWeights, rankerConfig)
+		facets, err := mediator.Facets(context.Background(), c.request)
+		c.assert(c.store, facets, err)
+	}
+}
@@ -0,0 +1,67 @@
+package mediators
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// DataQualityMediator specifies the methods to check the data of the catalog
+type DataQualityMediator interface {
+	Check(ctx context.Context, req models.DataQualityRequest) (models.DataQualityReport, error)
+}
+
+// dataQualityMediator is the concrete implementation of the DataQualityMediator interface
+type dataQualityMediator struct {
+	logger *log.Entry
+	store  stores.DataQualityStore
+	cfg    config.DataQualityConfig
+}
+
+// NewDataQualityMediator returns a new instance of DataQualityMediator
+func NewDataQualityMediator(logger *log.Entry, dataQualityStore stores.DataQualityStore, cfg config.DataQualityConfig) DataQualityMediator {
+	return &dataQualityMediator{
+		logger: logger,
+		store:  dataQualityStore,
+		cfg:    cfg,
+	}
+}
+
+// Check runs the rules of the request, the configured ones when it names none, and reports
+// the rows breaking each of them. The report passes when no row breaks any rule.
+func (m *dataQualityMediator) Check(ctx context.Context, req models.DataQualityRequest) (models.DataQualityReport, error) {
+	rules := models.ParseDataQualityRules(req.Rules)
+	if rules == nil {
+		rules = m.cfg.Rules
+	}
+
+	report := models.DataQualityReport{
+		Passed:    true,
+		CheckedAt: time.Now().UTC(),
+		Results:   make([]models.DataQualityResult, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		violations, rows, err := m.store.FindViolations(ctx, rule, m.cfg.MaxRows)
+		if err != nil {
+			return models.DataQualityReport{}, err
+		}
+		result := models.DataQualityResult{
+			Rule:        rule,
+			Description: models.DataQualityDescriptions[rule],
+			Passed:      violations == 0,
+			Violations:  violations,
+			Rows:        rows,
+		}
+		if !result.Passed {
+			report.Passed = false
+			m.logger.WithField("rule", rule).WithField("violations", violations).Warn("data-quality rule failed")
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
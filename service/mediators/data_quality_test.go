@@ -0,0 +1,99 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DataQualityStoreMock struct {
+	ViolationsField map[string][]models.DataQualityRow
+	RulesField      []string
+	LimitField      int64
+	ErrorField      error
+}
+
+func (m *DataQualityStoreMock) FindViolations(ctx context.Context, rule string, limit int64) (int64, []models.DataQualityRow, error) {
+	m.RulesField = append(m.RulesField, rule)
+	m.LimitField = limit
+	rows := m.ViolationsField[rule]
+	if int64(len(rows)) > limit {
+		return int64(len(rows)), rows[:limit], m.ErrorField
+	}
+	return int64(len(rows)), rows, m.ErrorField
+}
+
+func TestDataQualityMediator_Check(t *testing.T) {
+	var cases = []struct {
+		name   string
+		req    models.DataQualityRequest
+		store  *DataQualityStoreMock
+		assert func(store *DataQualityStoreMock, report models.DataQualityReport, err error)
+	}{
+		{
+			name:  "passed",
+			store: &DataQualityStoreMock{},
+			assert: func(store *DataQualityStoreMock, report models.DataQualityReport, err error) {
+				require.NoError(t, err)
+				assert.True(t, report.Passed)
+				assert.False(t, report.CheckedAt.IsZero())
+				assert.Equal(t, []string{models.RuleBookPages, models.RuleOrphanGenre}, store.RulesField)
+				assert.Equal(t, int64(2), store.LimitField)
+				assert.Len(t, report.Results, 2)
+				assert.True(t, report.Results[0].Passed)
+				assert.Equal(t, models.DataQualityDescriptions[models.RuleBookPages], report.Results[0].Description)
+			},
+		},
+		{
+			name: "failed",
+			store: &DataQualityStoreMock{ViolationsField: map[string][]models.DataQualityRow{
+				models.RuleOrphanGenre: {
+					{Entity: "genre", ID: 3, Detail: "Poetry has no book nor subgenre"},
+					{Entity: "genre", ID: 8, Detail: "Essay has no book nor subgenre"},
+					{Entity: "genre", ID: 9, Detail: "Drama has no book nor subgenre"},
+				},
+			}},
+			assert: func(store *DataQualityStoreMock, report models.DataQualityReport, err error) {
+				require.NoError(t, err)
+				assert.False(t, report.Passed)
+				assert.True(t, report.Results[0].Passed)
+				assert.False(t, report.Results[1].Passed)
+				assert.Equal(t, int64(3), report.Results[1].Violations)
+				assert.Len(t, report.Results[1].Rows, 2)
+			},
+		},
+		{
+			name:  "requested rules",
+			req:   models.DataQualityRequest{Rules: "duplicate-title, book-year"},
+			store: &DataQualityStoreMock{},
+			assert: func(store *DataQualityStoreMock, report models.DataQualityReport, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []string{models.RuleDuplicateTitle, models.RuleBookYear}, store.RulesField)
+			},
+		},
+		{
+			name:  "failure",
+			store: &DataQualityStoreMock{ErrorField: errors.New("Error")},
+			assert: func(store *DataQualityStoreMock, report models.DataQualityReport, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewDataQualityMediator(log.NewEntry(log.New()), c.store, config.DataQualityConfig{
+				Rules:   []string{models.RuleBookPages, models.RuleOrphanGenre},
+				MaxRows: 2,
+			})
+			report, err := m.Check(context.Background(), c.req)
+			c.assert(c.store, report, err)
+		})
+	}
+}
@@ -2,15 +2,20 @@ package mediators
 
 import (
 	"context"
+	"strings"
 
 	"github.com/book-recommendations/service/models"
 	"github.com/book-recommendations/service/stores"
 	log "github.com/sirupsen/logrus"
 )
 
-// EraMediator specifies the methods to get eras
+// EraMediator specifies the methods to get and manage eras
 type EraMediator interface {
 	Get(ctx context.Context) ([]models.Era, error)
+	GetAll(ctx context.Context) ([]models.Era, error)
+	Create(ctx context.Context, req models.EraRequest) (models.Era, error)
+	Update(ctx context.Context, id int64, req models.EraRequest) (models.Era, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 // eraMediator is the concrete implementation of the EraMediator interface
@@ -27,14 +32,54 @@ func NewEraMediator(logger *log.Entry, eraStore stores.EraStore) EraMediator {
 	}
 }
 
-// Get returns a list of Eras
+// Get returns a list of the active Eras
 func (m *eraMediator) Get(ctx context.Context) ([]models.Era, error) {
 	var eras []models.Era
 
-	eras, err := m.store.GetAllEras(ctx)
+	eras, err := m.store.GetAllEras(ctx, false)
 	if err != nil {
 		return nil, err
 	}
 
 	return eras, nil
 }
+
+// GetAll returns every era, the retired ones included
+func (m *eraMediator) GetAll(ctx context.Context) ([]models.Era, error) {
+	return m.store.GetAllEras(ctx, true)
+}
+
+// Create creates an era, unless it overlaps an active one and the request does not allow it
+func (m *eraMediator) Create(ctx context.Context, req models.EraRequest) (models.Era, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	era := req.Era(0)
+	return m.store.CreateEra(ctx, era, eraOverlapCheck(era, req.AllowOverlap))
+}
+
+// Update replaces an era, unless it overlaps an active one and the request does not allow it
+func (m *eraMediator) Update(ctx context.Context, id int64, req models.EraRequest) (models.Era, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	era := req.Era(id)
+	return m.store.UpdateEra(ctx, era, eraOverlapCheck(era, req.AllowOverlap))
+}
+
+// Delete deletes an era
+func (m *eraMediator) Delete(ctx context.Context, id int64) error {
+	return m.store.DeleteEra(ctx, id)
+}
+
+// eraOverlapCheck returns the check of the store that fails with a conflict when the years of an
+// active era overlap the ones of another active era, unless allowed
+func eraOverlapCheck(era models.Era, allowed bool) func(active []models.Era) error {
+	return func(active []models.Era) error {
+		if allowed || !era.Active {
+			return nil
+		}
+		for _, other := range active {
+			if other.ID != era.ID && models.Overlaps(era.MinYear, era.MaxYear, other.MinYear, other.MaxYear) {
+				return models.NewError(models.KindConflict, "the years overlap the ones of era %d %q, which allowOverlap allows", other.ID, other.Title)
+			}
+		}
+		return nil
+	}
+}
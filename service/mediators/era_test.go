@@ -13,14 +13,44 @@ import (
 )
 
 type EraStoreMock struct {
-	EraField   []models.Era
-	ErrorField error
+	EraField     []models.Era
+	SavedField   *models.Era
+	DeletedField int64
+	ErrorField   error
 }
 
-func (m *EraStoreMock) GetAllEras(ctx context.Context) ([]models.Era, error) {
+func (m *EraStoreMock) GetAllEras(ctx context.Context, includeInactive bool) ([]models.Era, error) {
 	return m.EraField, m.ErrorField
 }
 
+func (m *EraStoreMock) CreateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error) {
+	if m.ErrorField != nil {
+		return models.Era{}, m.ErrorField
+	}
+	if err := check(m.EraField); err != nil {
+		return models.Era{}, err
+	}
+	era.ID = int64(len(m.EraField) + 1)
+	m.SavedField = &era
+	return era, nil
+}
+
+func (m *EraStoreMock) UpdateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error) {
+	if m.ErrorField != nil {
+		return models.Era{}, m.ErrorField
+	}
+	if err := check(m.EraField); err != nil {
+		return models.Era{}, err
+	}
+	m.SavedField = &era
+	return era, nil
+}
+
+func (m *EraStoreMock) DeleteEra(ctx context.Context, id int64) error {
+	m.DeletedField = id
+	return m.ErrorField
+}
+
 func TestEraController_Get(t *testing.T) {
 	MaxYear := int64(1969)
 	MinYear := int64(1970)
@@ -77,3 +107,47 @@ func TestEraController_Get(t *testing.T) {
 		c.assert(res, err)
 	}
 }
+
+func TestEraMediator_Create(t *testing.T) {
+	year := func(value int64) *int64 { return &value }
+	eras := []models.Era{
+		{ID: 1, Title: "Any", Active: true},
+		{ID: 2, Title: "Classic", MaxYear: year(1969), Active: true},
+		{ID: 3, Title: "Modern", MinYear: year(1970), Active: true},
+	}
+
+	var cases = []struct {
+		name   string
+		req    models.EraRequest
+		store  *EraStoreMock
+		assert func(store *EraStoreMock, era models.Era, err error)
+	}{
+		{
+			name:  "overlap",
+			req:   models.EraRequest{Title: "Contemporary (2000+)", MinYear: year(2000)},
+			store: &EraStoreMock{EraField: eras},
+			assert: func(store *EraStoreMock, era models.Era, err error) {
+				assert.True(t, errors.Is(err, models.ErrConflict))
+				assert.Contains(t, err.Error(), "era 3")
+				assert.Nil(t, store.SavedField)
+			},
+		},
+		{
+			name:  "overlap allowed",
+			req:   models.EraRequest{Title: "Contemporary (2000+)", MinYear: year(2000), SortOrder: 4, AllowOverlap: true},
+			store: &EraStoreMock{EraField: eras},
+			assert: func(store *EraStoreMock, era models.Era, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), era.ID)
+				assert.True(t, era.Active)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewEraMediator(log.NewEntry(log.New()), c.store)
+			era, err := m.Create(context.Background(), c.req)
+			c.assert(c.store, era, err)
+		})
+	}
+}
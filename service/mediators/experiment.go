@@ -0,0 +1,95 @@
+package mediators
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExperimentMediator specifies the methods to assign subjects to experiments and record their events
+type ExperimentMediator interface {
+	Assign(subject string) []models.Assignment
+	LogEvent(ctx context.Context, event models.Event) error
+}
+
+// experimentMediator is the concrete implementation of the ExperimentMediator interface
+type experimentMediator struct {
+	logger      *log.Entry
+	store       stores.EventStore
+	experiments []models.Experiment
+}
+
+// NewExperimentMediator returns a new instance of ExperimentMediator
+func NewExperimentMediator(logger *log.Entry, eventStore stores.EventStore, experiments []models.Experiment) ExperimentMediator {
+	return &experimentMediator{
+		logger:      logger,
+		store:       eventStore,
+		experiments: experiments,
+	}
+}
+
+// Assign returns the variant of every experiment the subject is enrolled in. The same
+// subject is always assigned to the same variant as long as the traffic split is unchanged.
+func (m *experimentMediator) Assign(subject string) []models.Assignment {
+	assignments := make([]models.Assignment, 0, len(m.experiments))
+	for _, experiment := range m.experiments {
+		if variant, ok := assignVariant(experiment, subject); ok {
+			assignments = append(assignments, models.Assignment{
+				Experiment: experiment.Name,
+				Variant:    variant.Name,
+				Ranker:     variant.Ranker,
+			})
+		}
+	}
+
+	return assignments
+}
+
+// LogEvent records an event of a subject, the event must belong to a running experiment
+func (m *experimentMediator) LogEvent(ctx context.Context, event models.Event) error {
+	if !m.hasVariant(event.Experiment, event.Variant) {
+		return models.ErrUnknownVariant
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	return m.store.CreateEvent(ctx, event)
+}
+
+func (m *experimentMediator) hasVariant(experimentName, variantName string) bool {
+	for _, experiment := range m.experiments {
+		if experiment.Name != experimentName {
+			continue
+		}
+		for _, variant := range experiment.Variants {
+			if variant.Name == variantName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// assignVariant hashes the subject into one of the traffic buckets of the experiment and
+// returns the variant owning that bucket. Hashing the experiment name along with the
+// subject keeps the assignments of different experiments independent.
+func assignVariant(experiment models.Experiment, subject string) (models.Variant, bool) {
+	hash := fnv.New64a()
+	hash.Write([]byte(experiment.Name + ":" + subject))
+	bucket := int64(hash.Sum64() % models.TrafficBuckets)
+
+	for _, variant := range experiment.Variants {
+		if bucket < variant.Traffic {
+			return variant, true
+		}
+		bucket -= variant.Traffic
+	}
+
+	return models.Variant{}, false
+}
@@ -0,0 +1,110 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type EventStoreMock struct {
+	EventField models.Event
+	ErrorField error
+}
+
+func (m *EventStoreMock) CreateEvent(ctx context.Context, event models.Event) error {
+	m.EventField = event
+	return m.ErrorField
+}
+
+var experiments = []models.Experiment{
+	{
+		Name: "ranker",
+		Variants: []models.Variant{
+			{Name: "control", Ranker: models.RankerWeighted, Traffic: 50},
+			{Name: "recency", Ranker: models.RankerRecency, Traffic: 30},
+		},
+	},
+	{
+		Name: "everyone",
+		Variants: []models.Variant{
+			{Name: "random", Ranker: models.RankerRandom, Traffic: 100},
+		},
+	},
+}
+
+func TestExperimentMediator_Assign(t *testing.T) {
+	m := mediators.NewExperimentMediator(log.NewEntry(log.New()), &EventStoreMock{}, experiments)
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		subject := fmt.Sprintf("anon:%d", i)
+		assignments := m.Assign(subject)
+		assert.Equal(t, assignments, m.Assign(subject), "assignments should be deterministic")
+
+		enrolled := false
+		for _, assignment := range assignments {
+			if assignment.Experiment == "ranker" {
+				counts[assignment.Variant]++
+				enrolled = true
+			}
+		}
+		if !enrolled {
+			counts["none"]++
+		}
+		assert.Contains(t, assignments, models.Assignment{Experiment: "everyone", Variant: "random", Ranker: models.RankerRandom})
+	}
+
+	assert.InDelta(t, 5000, counts["control"], 300)
+	assert.InDelta(t, 3000, counts["recency"], 300)
+	assert.InDelta(t, 2000, counts["none"], 300)
+}
+
+func TestExperimentMediator_LogEvent(t *testing.T) {
+	var cases = []struct {
+		name   string
+		store  stores.EventStore
+		event  models.Event
+		assert func(store stores.EventStore, err error)
+	}{
+		{
+			name:  "success",
+			store: &EventStoreMock{},
+			event: models.Event{Type: models.EventClick, Experiment: "ranker", Variant: "recency", BookID: 3, Subject: "user:1"},
+			assert: func(store stores.EventStore, err error) {
+				assert.Nil(t, err)
+				event := store.(*EventStoreMock).EventField
+				assert.Equal(t, "recency", event.Variant)
+				assert.Equal(t, "user:1", event.Subject)
+				assert.False(t, event.CreatedAt.IsZero())
+			},
+		},
+		{
+			name:  "failure - unknown variant",
+			store: &EventStoreMock{},
+			event: models.Event{Type: models.EventClick, Experiment: "ranker", Variant: "random", BookID: 3},
+			assert: func(store stores.EventStore, err error) {
+				assert.ErrorIs(t, err, models.ErrUnknownVariant)
+			},
+		},
+		{
+			name:  "failure",
+			store: &EventStoreMock{ErrorField: errors.New("Error")},
+			event: models.Event{Type: models.EventImpression, Experiment: "everyone", Variant: "random", BookID: 3},
+			assert: func(store stores.EventStore, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		m := mediators.NewExperimentMediator(log.NewEntry(log.New()), c.store, experiments)
+		err := m.LogEvent(context.Background(), c.event)
+		c.assert(c.store, err)
+	}
+}
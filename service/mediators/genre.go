@@ -11,6 +11,7 @@ import (
 // GenreMediator specifies the methods to get genres
 type GenreMediator interface {
 	Get(ctx context.Context) ([]models.Genre, error)
+	GetStats(ctx context.Context) ([]models.GenreStats, error)
 }
 
 // genreMediator is the concrete implementation of the GenreMediator interface
@@ -27,7 +28,7 @@ func NewGenreMediator(logger *log.Entry, genreStore stores.GenreStore) GenreMedi
 	}
 }
 
-// Get returns a list of Genres
+// Get returns a list of Genres, each one along with its direct children
 func (m *genreMediator) Get(ctx context.Context) ([]models.Genre, error) {
 	var genres []models.Genre
 
@@ -36,5 +37,38 @@ func (m *genreMediator) Get(ctx context.Context) ([]models.Genre, error) {
 		return nil, err
 	}
 
-	return genres, nil
+	return withChildren(genres), nil
+}
+
+// GetStats returns a list of Genres along with the figures of their books, each one
+// along with its direct children
+func (m *genreMediator) GetStats(ctx context.Context) ([]models.GenreStats, error) {
+	stats, err := m.store.GetGenreStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make([]models.Genre, 0, len(stats))
+	for _, genre := range stats {
+		genres = append(genres, genre.Genre)
+	}
+	for i, genre := range withChildren(genres) {
+		stats[i].Genre = genre
+	}
+
+	return stats, nil
+}
+
+// withChildren sets the direct children of every genre of the list, in the order of the list
+func withChildren(genres []models.Genre) []models.Genre {
+	children := make(map[int64][]models.Genre)
+	for _, genre := range genres {
+		if genre.ParentID != nil {
+			children[*genre.ParentID] = append(children[*genre.ParentID], genre)
+		}
+	}
+	for i := range genres {
+		genres[i].Children = children[genres[i].ID]
+	}
+	return genres
 }
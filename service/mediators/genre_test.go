@@ -14,6 +14,7 @@ import (
 
 type GenreStoreMock struct {
 	GenreField []models.Genre
+	StatsField []models.GenreStats
 	ErrorField error
 }
 
@@ -21,6 +22,10 @@ func (m *GenreStoreMock) GetAllGenres(ctx context.Context) ([]models.Genre, erro
 	return m.GenreField, m.ErrorField
 }
 
+func (m *GenreStoreMock) GetGenreStats(ctx context.Context) ([]models.GenreStats, error) {
+	return m.StatsField, m.ErrorField
+}
+
 func TestGenreController_Get(t *testing.T) {
 	var cases = []struct {
 		name   string
@@ -70,3 +75,64 @@ func TestGenreController_Get(t *testing.T) {
 		c.assert(res, err)
 	}
 }
+
+func TestGenreMediator_GetChildren(t *testing.T) {
+	sciFi := int64(2)
+	store := &GenreStoreMock{
+		GenreField: []models.Genre{
+			{ID: 1, Title: "Young Adult"},
+			{ID: 2, Title: "SciFi/Fantasy"},
+			{ID: 9, Title: "Space Opera", ParentID: &sciFi},
+			{ID: 10, Title: "Epic Fantasy", ParentID: &sciFi},
+		},
+	}
+	m := mediators.NewGenreMediator(log.NewEntry(log.New()), store)
+
+	genres, err := m.Get(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, genres, 4)
+	assert.Empty(t, genres[0].Children)
+	assert.Equal(t, []models.Genre{
+		{ID: 9, Title: "Space Opera", ParentID: &sciFi},
+		{ID: 10, Title: "Epic Fantasy", ParentID: &sciFi},
+	}, genres[1].Children)
+	assert.Empty(t, genres[2].Children)
+}
+
+func TestGenreMediator_GetStats(t *testing.T) {
+	sciFi := int64(2)
+	var cases = []struct {
+		name   string
+		store  stores.GenreStore
+		assert func(stats []models.GenreStats, err error)
+	}{
+		{
+			name: "success",
+			store: &GenreStoreMock{
+				StatsField: []models.GenreStats{
+					{Genre: models.Genre{ID: 2, Title: "SciFi/Fantasy"}, BookCount: 12, AverageRating: 3.2},
+					{Genre: models.Genre{ID: 9, Title: "Space Opera", ParentID: &sciFi}, BookCount: 4, AverageRating: 3.6},
+				},
+			},
+			assert: func(stats []models.GenreStats, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, stats, 2)
+				assert.Equal(t, int64(12), stats[0].BookCount)
+				assert.Equal(t, []models.Genre{{ID: 9, Title: "Space Opera", ParentID: &sciFi}}, stats[0].Children)
+				assert.Equal(t, 3.6, stats[1].AverageRating)
+			},
+		},
+		{
+			name:  "failure",
+			store: &GenreStoreMock{ErrorField: errors.New("Error")},
+			assert: func(stats []models.GenreStats, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		m := mediators.NewGenreMediator(log.NewEntry(log.New()), c.store)
+		stats, err := m.GetStats(context.Background())
+		c.assert(stats, err)
+	}
+}
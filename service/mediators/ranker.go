@@ -0,0 +1,89 @@
+package mediators
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+)
+
+// Ranker reorders a list of candidate books, best first
+type Ranker interface {
+	Rank(books []models.Book) []models.Book
+}
+
+// NewRanker returns the ranker with the given name
+func NewRanker(name string, cfg config.RankerConfig) (Ranker, error) {
+	switch name {
+	case models.RankerRating:
+		return ratingRanker{}, nil
+	case models.RankerWeighted:
+		return weightedRanker{}, nil
+	case models.RankerRecency:
+		return recencyRanker{
+			boost:       cfg.RecencyBoost,
+			window:      cfg.RecencyWindow,
+			currentYear: int64(time.Now().Year()),
+		}, nil
+	case models.RankerRandom:
+		return randomRanker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ranker %q", name)
+	}
+}
+
+// ratingRanker orders books by their average rating
+type ratingRanker struct{}
+
+func (ratingRanker) Rank(books []models.Book) []models.Book {
+	return rankBy(books, func(book models.Book) float64 {
+		return book.Rating
+	})
+}
+
+// weightedRanker orders books by their confidence-weighted rating
+type weightedRanker struct{}
+
+func (weightedRanker) Rank(books []models.Book) []models.Book {
+	return rankBy(books, func(book models.Book) float64 {
+		return book.WeightedRating
+	})
+}
+
+// recencyRanker orders books by their weighted rating plus a boost that is full for
+// the books published this year and fades away for books older than window years
+type recencyRanker struct {
+	boost       float64
+	window      int64
+	currentYear int64
+}
+
+func (r recencyRanker) Rank(books []models.Book) []models.Book {
+	return rankBy(books, func(book models.Book) float64 {
+		if book.YearPublished > r.currentYear {
+			return book.WeightedRating + r.boost
+		}
+		return book.WeightedRating + r.boost*proximity(r.currentYear, book.YearPublished, r.window)
+	})
+}
+
+// randomRanker shuffles the books
+type randomRanker struct{}
+
+func (randomRanker) Rank(books []models.Book) []models.Book {
+	rand.Shuffle(len(books), func(i, j int) {
+		books[i], books[j] = books[j], books[i]
+	})
+	return books
+}
+
+// rankBy sorts the books by descending score, keeping the current order on ties
+func rankBy(books []models.Book, score func(models.Book) float64) []models.Book {
+	sort.SliceStable(books, func(i, j int) bool {
+		return score(books[i]) > score(books[j])
+	})
+	return books
+}
@@ -0,0 +1,91 @@
+package mediators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRanker(t *testing.T) {
+	currentYear := int64(time.Now().Year())
+	candidates := func() []models.Book {
+		return []models.Book{
+			{ID: 1, Rating: 4.9, WeightedRating: 3.0, YearPublished: 1950},
+			{ID: 2, Rating: 4.2, WeightedRating: 4.0, YearPublished: 1990},
+			{ID: 3, Rating: 3.0, WeightedRating: 3.5, YearPublished: currentYear},
+		}
+	}
+	ids := func(books []models.Book) []int64 {
+		res := make([]int64, 0, len(books))
+		for _, book := range books {
+			res = append(res, book.ID)
+		}
+		return res
+	}
+
+	var cases = []struct {
+		name   string
+		ranker string
+		assert func(books []models.Book, err error)
+	}{
+		{
+			name:   "rating",
+			ranker: models.RankerRating,
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{1, 2, 3}, ids(books))
+			},
+		},
+		{
+			name:   "weighted",
+			ranker: models.RankerWeighted,
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{2, 3, 1}, ids(books))
+			},
+		},
+		{
+			name:   "recency",
+			ranker: models.RankerRecency,
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{3, 2, 1}, ids(books))
+			},
+		},
+		{
+			name:   "random",
+			ranker: models.RankerRandom,
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.ElementsMatch(t, []int64{1, 2, 3}, ids(books))
+			},
+		},
+		{
+			name:   "unknown",
+			ranker: "popular",
+			assert: func(books []models.Book, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		ranker, err := mediators.NewRanker(c.ranker, rankerConfig)
+		if err != nil {
+			c.assert(nil, err)
+			continue
+		}
+		require.NotNil(t, ranker)
+		c.assert(ranker.Rank(candidates()), nil)
+	}
+
+	// every ranker RANKER_DEFAULT accepts exists
+	for _, name := range config.Rankers {
+		_, err := mediators.NewRanker(name, rankerConfig)
+		assert.Nil(t, err, name)
+	}
+}
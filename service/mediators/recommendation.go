@@ -0,0 +1,329 @@
+package mediators
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// RecommendationMediator specifies the methods to get personalized recommendations
+type RecommendationMediator interface {
+	Recommend(ctx context.Context, userID int64, req models.BookRequest) ([]models.Recommendation, error)
+	RefreshSimilarities(ctx context.Context) error
+}
+
+// recommendationMediator is the concrete implementation of the RecommendationMediator interface
+type recommendationMediator struct {
+	logger      *log.Entry
+	bookStore   stores.BookStore
+	ratingStore stores.RatingStore
+	cfg         config.RecommendationConfig
+	poolSize    int64
+}
+
+// NewRecommendationMediator returns a new instance of RecommendationMediator
+func NewRecommendationMediator(logger *log.Entry, bookStore stores.BookStore, ratingStore stores.RatingStore, cfg config.RecommendationConfig, poolSize int64) RecommendationMediator {
+	return &recommendationMediator{
+		logger:      logger,
+		bookStore:   bookStore,
+		ratingStore: ratingStore,
+		cfg:         cfg,
+		poolSize:    poolSize,
+	}
+}
+
+// Recommend returns the books matching the request the user has not rated yet, best first.
+// Users with enough ratings get books scored by item-item collaborative filtering, completed
+// by their preferences; other users only get books scored by their preferences.
+func (m *recommendationMediator) Recommend(ctx context.Context, userID int64, req models.BookRequest) ([]models.Recommendation, error) {
+	limit := int64(0)
+	if req.Limit != "" {
+		var err error
+		if limit, err = strconv.ParseInt(req.Limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	ratings, err := m.ratingStore.GetUserRatings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rated := make(map[int64]float64, len(ratings))
+	for _, rating := range ratings {
+		rated[rating.BookID] = rating.Rating
+	}
+
+	collaborative := map[int64]float64{}
+	if int64(len(ratings)) >= m.cfg.MinRatings {
+		if collaborative, err = m.collaborativeScores(ctx, rated); err != nil {
+			return nil, err
+		}
+	}
+	preferences, err := m.preferenceScores(ctx, ratings)
+	if err != nil {
+		return nil, err
+	}
+	neighbours, err := m.neighbours(ctx, collaborative, rated, req)
+	if err != nil {
+		return nil, err
+	}
+
+	poolReq := req
+	poolReq.Limit = ""
+	if pool := max(m.poolSize, limit) + int64(len(ratings)); pool > 0 {
+		poolReq.Limit = strconv.FormatInt(pool, 10)
+	}
+	pool, err := m.bookStore.GetBooks(ctx, poolReq)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(neighbours)+len(pool))
+	for _, book := range neighbours {
+		recommendations = append(recommendations, models.Recommendation{
+			Book:   book,
+			Score:  collaborative[book.ID],
+			Reason: models.ReasonCollaborative,
+		})
+	}
+	for _, book := range pool {
+		if _, ok := rated[book.ID]; ok {
+			continue
+		}
+		if _, ok := collaborative[book.ID]; ok {
+			continue
+		}
+		recommendation := models.Recommendation{Book: book, Reason: models.ReasonPopular}
+		if score := preferences.score(book); score > 0 {
+			recommendation.Score = score
+			recommendation.Reason = models.ReasonPreferences
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	// collaborative recommendations first, then the preferred ones, then the rest in pool order
+	reasonRank := map[string]int{models.ReasonCollaborative: 0, models.ReasonPreferences: 1, models.ReasonPopular: 2}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Reason != recommendations[j].Reason {
+			return reasonRank[recommendations[i].Reason] < reasonRank[recommendations[j].Reason]
+		}
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if limit > 0 && int64(len(recommendations)) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations, nil
+}
+
+// collaborativeScores predicts the rating the user would give to the neighbours of the rated
+// books, as the average of the ratings of the user weighted by the similarity of the books
+func (m *recommendationMediator) collaborativeScores(ctx context.Context, rated map[int64]float64) (map[int64]float64, error) {
+	bookIDs := make([]int64, 0, len(rated))
+	for bookID := range rated {
+		bookIDs = append(bookIDs, bookID)
+	}
+	similarities, err := m.ratingStore.GetSimilarities(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	weighted := map[int64]float64{}
+	weights := map[int64]float64{}
+	for _, similarity := range similarities {
+		rating, ok := rated[similarity.BookID]
+		if !ok || similarity.Score <= 0 {
+			continue
+		}
+		weighted[similarity.SimilarBookID] += similarity.Score * rating
+		weights[similarity.SimilarBookID] += similarity.Score
+	}
+
+	scores := make(map[int64]float64, len(weighted))
+	for bookID, sum := range weighted {
+		scores[bookID] = sum / weights[bookID]
+	}
+
+	return scores, nil
+}
+
+// neighbours loads the books scored by collaborative filtering the user has not rated yet,
+// leaving out the ones not matching the filters of the request. They are looked up by ID as
+// they may rank anywhere in the catalog, far from the pool of the best rated books.
+func (m *recommendationMediator) neighbours(ctx context.Context, scores map[int64]float64, rated map[int64]float64, req models.BookRequest) ([]models.Book, error) {
+	ids := make([]int64, 0, len(scores))
+	for bookID := range scores {
+		if _, ok := rated[bookID]; !ok {
+			ids = append(ids, bookID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	req.Limit, req.Offset = "", ""
+
+	return m.bookStore.GetBooksByIDs(ctx, ids, req)
+}
+
+// preferences counts how many liked books share each genre and each author
+type preferences struct {
+	genres  map[int64]float64
+	authors map[int64]float64
+}
+
+func (p preferences) score(book models.Book) float64 {
+	var score float64
+	for _, genre := range book.Genres {
+		score += p.genres[genre.ID]
+	}
+	for _, author := range book.Authors {
+		score += p.authors[author.ID]
+	}
+	return score
+}
+
+// preferenceScores gathers the genres and the authors of the books the user liked. The
+// ratings of the deleted books are kept for when they are restored, but are no preference.
+func (m *recommendationMediator) preferenceScores(ctx context.Context, ratings []models.UserRating) (preferences, error) {
+	prefs := preferences{
+		genres:  map[int64]float64{},
+		authors: map[int64]float64{},
+	}
+	liked := make([]int64, 0, len(ratings))
+	for _, rating := range ratings {
+		if rating.Rating >= models.LikedRating {
+			liked = append(liked, rating.BookID)
+		}
+	}
+	if len(liked) == 0 {
+		return prefs, nil
+	}
+
+	books, err := m.bookStore.GetBooksByIDs(ctx, liked, models.BookRequest{})
+	if err != nil {
+		return preferences{}, err
+	}
+	for _, book := range books {
+		for _, genre := range book.Genres {
+			prefs.genres[genre.ID]++
+		}
+		for _, author := range book.Authors {
+			prefs.authors[author.ID]++
+		}
+	}
+
+	return prefs, nil
+}
+
+// RefreshSimilarities recomputes the similarity between every pair of books from the ratings of the users
+func (m *recommendationMediator) RefreshSimilarities(ctx context.Context) error {
+	ratings, err := m.ratingStore.GetAllRatings(ctx)
+	if err != nil {
+		return err
+	}
+
+	similarities := ComputeSimilarities(ratings, m.cfg.Neighbours, m.cfg.MinCoRaters)
+	if err := m.ratingStore.ReplaceSimilarities(ctx, similarities); err != nil {
+		return err
+	}
+	m.logger.WithField("ratings", len(ratings)).WithField("similarities", len(similarities)).
+		Info("book similarities refreshed")
+
+	return nil
+}
+
+// ComputeSimilarities returns the adjusted cosine similarity between the books rated by at
+// least minCoRaters common users: the ratings are centered on the mean of each user, so that
+// books are similar when the same users rate them above (or below) their habits. Only the
+// neighbours best positively correlated books of each book are kept.
+func ComputeSimilarities(ratings []models.UserRating, neighbours, minCoRaters int64) []models.BookSimilarity {
+	type centeredRating struct {
+		bookID int64
+		value  float64
+	}
+	type pair struct {
+		a, b int64
+	}
+	type accumulator struct {
+		dot, normA, normB float64
+		coRaters          int64
+	}
+
+	byUser := map[int64][]models.UserRating{}
+	for _, rating := range ratings {
+		byUser[rating.UserID] = append(byUser[rating.UserID], rating)
+	}
+
+	pairs := map[pair]*accumulator{}
+	for _, userRatings := range byUser {
+		var mean float64
+		for _, rating := range userRatings {
+			mean += rating.Rating
+		}
+		mean /= float64(len(userRatings))
+
+		centered := make([]centeredRating, 0, len(userRatings))
+		for _, rating := range userRatings {
+			centered = append(centered, centeredRating{bookID: rating.BookID, value: rating.Rating - mean})
+		}
+		sort.Slice(centered, func(i, j int) bool {
+			return centered[i].bookID < centered[j].bookID
+		})
+
+		for i := range centered {
+			for j := i + 1; j < len(centered); j++ {
+				key := pair{a: centered[i].bookID, b: centered[j].bookID}
+				acc, ok := pairs[key]
+				if !ok {
+					acc = &accumulator{}
+					pairs[key] = acc
+				}
+				acc.dot += centered[i].value * centered[j].value
+				acc.normA += centered[i].value * centered[i].value
+				acc.normB += centered[j].value * centered[j].value
+				acc.coRaters++
+			}
+		}
+	}
+
+	byBook := map[int64][]models.BookSimilarity{}
+	for key, acc := range pairs {
+		if acc.coRaters < minCoRaters || acc.normA == 0 || acc.normB == 0 {
+			continue
+		}
+		score := acc.dot / math.Sqrt(acc.normA*acc.normB)
+		if score <= 0 {
+			continue
+		}
+		byBook[key.a] = append(byBook[key.a], models.BookSimilarity{BookID: key.a, SimilarBookID: key.b, Score: score, CoRaters: acc.coRaters})
+		byBook[key.b] = append(byBook[key.b], models.BookSimilarity{BookID: key.b, SimilarBookID: key.a, Score: score, CoRaters: acc.coRaters})
+	}
+
+	similarities := make([]models.BookSimilarity, 0)
+	for _, bookSimilarities := range byBook {
+		sort.Slice(bookSimilarities, func(i, j int) bool {
+			if bookSimilarities[i].Score != bookSimilarities[j].Score {
+				return bookSimilarities[i].Score > bookSimilarities[j].Score
+			}
+			return bookSimilarities[i].SimilarBookID < bookSimilarities[j].SimilarBookID
+		})
+		if neighbours > 0 && int64(len(bookSimilarities)) > neighbours {
+			bookSimilarities = bookSimilarities[:neighbours]
+		}
+		similarities = append(similarities, bookSimilarities...)
+	}
+	sort.Slice(similarities, func(i, j int) bool {
+		if similarities[i].BookID != similarities[j].BookID {
+			return similarities[i].BookID < similarities[j].BookID
+		}
+		return similarities[i].Score > similarities[j].Score
+	})
+
+	return similarities
+}
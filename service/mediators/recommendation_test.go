@@ -0,0 +1,237 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RatingStoreMock struct {
+	RatingField       []models.UserRating
+	SimilarityField   []models.BookSimilarity
+	ReplacedField     []models.BookSimilarity
+	ErrorField        error
+	RatingsErrorField error
+}
+
+func (m *RatingStoreMock) GetUserRatings(ctx context.Context, userID int64) ([]models.UserRating, error) {
+	ratings := make([]models.UserRating, 0)
+	for _, rating := range m.RatingField {
+		if rating.UserID == userID {
+			ratings = append(ratings, rating)
+		}
+	}
+	return ratings, m.RatingsErrorField
+}
+
+func (m *RatingStoreMock) GetAllRatings(ctx context.Context) ([]models.UserRating, error) {
+	return m.RatingField, m.RatingsErrorField
+}
+
+func (m *RatingStoreMock) GetSimilarities(ctx context.Context, bookIDs []int64) ([]models.BookSimilarity, error) {
+	return m.SimilarityField, m.ErrorField
+}
+
+func (m *RatingStoreMock) ReplaceSimilarities(ctx context.Context, similarities []models.BookSimilarity) error {
+	m.ReplacedField = similarities
+	return m.ErrorField
+}
+
+var recommendationConfig = config.RecommendationConfig{
+	MinRatings:  2,
+	Neighbours:  10,
+	MinCoRaters: 2,
+}
+
+var recommendationCatalog = []models.Book{
+	{ID: 1, Title: "Rated", Genres: []models.Genre{{ID: 1}}, Authors: []models.BookAuthor{{Author: models.Author{ID: 1}, Role: models.RoleAuthor}}},
+	{ID: 2, Title: "Rated too", Genres: []models.Genre{{ID: 2}}, Authors: []models.BookAuthor{{Author: models.Author{ID: 2}, Role: models.RoleAuthor}}},
+	{ID: 3, Title: "Popular", Genres: []models.Genre{{ID: 3}}, Authors: []models.BookAuthor{{Author: models.Author{ID: 3}, Role: models.RoleAuthor}}},
+	{ID: 4, Title: "Same genre", Genres: []models.Genre{{ID: 1}}, Authors: []models.BookAuthor{{Author: models.Author{ID: 4}, Role: models.RoleAuthor}}},
+	{ID: 5, Title: "Neighbour", Genres: []models.Genre{{ID: 3}}, Authors: []models.BookAuthor{{Author: models.Author{ID: 5}, Role: models.RoleAuthor}}},
+}
+
+func TestRecommendationMediator_Recommend(t *testing.T) {
+	ids := func(recommendations []models.Recommendation) []int64 {
+		res := make([]int64, 0, len(recommendations))
+		for _, recommendation := range recommendations {
+			res = append(res, recommendation.ID)
+		}
+		return res
+	}
+
+	outOfPool := &BookStoreMock{BookField: recommendationCatalog, OutOfPoolField: []int64{5}}
+
+	var cases = []struct {
+		name        string
+		ratingStore *RatingStoreMock
+		bookStore   *BookStoreMock
+		request     models.BookRequest
+		assert      func(recommendations []models.Recommendation, err error)
+	}{
+		{
+			name: "success - collaborative",
+			ratingStore: &RatingStoreMock{
+				RatingField: []models.UserRating{
+					{UserID: 1, BookID: 1, Rating: 5},
+					{UserID: 1, BookID: 2, Rating: 2},
+				},
+				SimilarityField: []models.BookSimilarity{
+					{BookID: 1, SimilarBookID: 5, Score: 0.8},
+					{BookID: 2, SimilarBookID: 5, Score: 0.2},
+				},
+			},
+			bookStore: &BookStoreMock{BookField: recommendationCatalog},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{5, 4, 3}, ids(recommendations))
+				assert.Equal(t, models.ReasonCollaborative, recommendations[0].Reason)
+				assert.InDelta(t, 4.4, recommendations[0].Score, 0.0001)
+				assert.Equal(t, models.ReasonPreferences, recommendations[1].Reason)
+				assert.Equal(t, models.ReasonPopular, recommendations[2].Reason)
+			},
+		},
+		{
+			name: "success - neighbour out of the pool",
+			ratingStore: &RatingStoreMock{
+				RatingField: []models.UserRating{
+					{UserID: 1, BookID: 1, Rating: 5},
+					{UserID: 1, BookID: 2, Rating: 2},
+				},
+				SimilarityField: []models.BookSimilarity{
+					{BookID: 1, SimilarBookID: 5, Score: 0.8},
+				},
+			},
+			bookStore: outOfPool,
+			request:   models.BookRequest{Genres: "3", Limit: "1"},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{5}, ids(recommendations))
+				assert.Equal(t, models.ReasonCollaborative, recommendations[0].Reason)
+				assert.Equal(t, models.BookRequest{Genres: "3"}, outOfPool.ByIDsRequestField, "the neighbours match the filters of the request")
+			},
+		},
+		{
+			name: "success - cold start",
+			ratingStore: &RatingStoreMock{
+				RatingField: []models.UserRating{
+					{UserID: 1, BookID: 1, Rating: 4},
+				},
+				SimilarityField: []models.BookSimilarity{
+					{BookID: 1, SimilarBookID: 5, Score: 0.8},
+				},
+			},
+			bookStore: &BookStoreMock{BookField: recommendationCatalog},
+			request:   models.BookRequest{Limit: "2"},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{4, 2}, ids(recommendations))
+				assert.Equal(t, models.ReasonPreferences, recommendations[0].Reason)
+				assert.Equal(t, models.ReasonPopular, recommendations[1].Reason)
+			},
+		},
+		{
+			name: "success - deleted liked book",
+			ratingStore: &RatingStoreMock{
+				RatingField: []models.UserRating{
+					{UserID: 1, BookID: 1, Rating: 4},
+					{UserID: 1, BookID: 3, Rating: 5},
+				},
+			},
+			bookStore: &BookStoreMock{BookField: recommendationCatalog, DeletedField: []int64{3}},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, int64(4), recommendations[0].ID)
+				assert.Equal(t, models.ReasonPreferences, recommendations[0].Reason)
+				assert.Equal(t, []int64{4, 2, 5}, ids(recommendations))
+				assert.Equal(t, models.ReasonPopular, recommendations[2].Reason, "the genre of the deleted book is no preference")
+			},
+		},
+		{
+			name:        "success - no ratings",
+			ratingStore: &RatingStoreMock{},
+			bookStore:   &BookStoreMock{BookField: recommendationCatalog},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids(recommendations))
+			},
+		},
+		{
+			name:        "failure - ratings",
+			ratingStore: &RatingStoreMock{RatingsErrorField: errors.New("Error")},
+			bookStore:   &BookStoreMock{BookField: recommendationCatalog},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name:        "failure - books",
+			ratingStore: &RatingStoreMock{},
+			bookStore:   &BookStoreMock{ErrorField: errors.New("Error")},
+			assert: func(recommendations []models.Recommendation, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		m := mediators.NewRecommendationMediator(log.NewEntry(log.New()), c.bookStore, c.ratingStore, recommendationConfig, 100)
+		res, err := m.Recommend(context.Background(), 1, c.request)
+		c.assert(res, err)
+	}
+}
+
+func TestRecommendationMediator_RefreshSimilarities(t *testing.T) {
+	ratingStore := &RatingStoreMock{
+		RatingField: []models.UserRating{
+			{UserID: 1, BookID: 1, Rating: 5},
+			{UserID: 1, BookID: 2, Rating: 5},
+			{UserID: 1, BookID: 3, Rating: 1},
+			{UserID: 2, BookID: 1, Rating: 4},
+			{UserID: 2, BookID: 2, Rating: 5},
+			{UserID: 2, BookID: 3, Rating: 2},
+		},
+	}
+	m := mediators.NewRecommendationMediator(log.NewEntry(log.New()), &BookStoreMock{}, ratingStore, recommendationConfig, 100)
+
+	err := m.RefreshSimilarities(context.Background())
+	require.Nil(t, err)
+	require.Len(t, ratingStore.ReplacedField, 2)
+	assert.Equal(t, int64(1), ratingStore.ReplacedField[0].BookID)
+	assert.Equal(t, int64(2), ratingStore.ReplacedField[0].SimilarBookID)
+	assert.Equal(t, int64(2), ratingStore.ReplacedField[0].CoRaters)
+	assert.InDelta(t, 0.8575, ratingStore.ReplacedField[0].Score, 0.0001)
+	assert.Equal(t, int64(2), ratingStore.ReplacedField[1].BookID)
+	assert.Equal(t, int64(1), ratingStore.ReplacedField[1].SimilarBookID)
+
+	ratingStore.ErrorField = errors.New("Error")
+	assert.NotNil(t, m.RefreshSimilarities(context.Background()))
+}
+
+func TestComputeSimilarities(t *testing.T) {
+	ratings := []models.UserRating{
+		{UserID: 1, BookID: 1, Rating: 5},
+		{UserID: 1, BookID: 2, Rating: 4},
+		{UserID: 1, BookID: 3, Rating: 1},
+		{UserID: 2, BookID: 1, Rating: 5},
+		{UserID: 2, BookID: 2, Rating: 5},
+		{UserID: 2, BookID: 3, Rating: 1},
+		{UserID: 3, BookID: 4, Rating: 3},
+	}
+
+	similarities := mediators.ComputeSimilarities(ratings, 10, 2)
+	for _, similarity := range similarities {
+		assert.NotEqual(t, int64(3), similarity.BookID, "books rated against each other should not be similar")
+		assert.NotEqual(t, int64(4), similarity.BookID, "books without co-raters should not be similar")
+	}
+	assert.Len(t, similarities, 2)
+
+	assert.Empty(t, mediators.ComputeSimilarities(ratings, 10, 3))
+	assert.Len(t, mediators.ComputeSimilarities(ratings, 1, 2), 2)
+}
@@ -0,0 +1,50 @@
+package mediators
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// SeriesMediator specifies the methods to get series of books
+type SeriesMediator interface {
+	Get(ctx context.Context) ([]models.Series, error)
+	GetBooks(ctx context.Context, req models.SeriesBooksRequest) ([]models.Book, error)
+}
+
+// seriesMediator is the concrete implementation of the SeriesMediator interface
+type seriesMediator struct {
+	logger *log.Entry
+	store  stores.SeriesStore
+}
+
+// NewSeriesMediator returns a new instance of SeriesMediator
+func NewSeriesMediator(logger *log.Entry, seriesStore stores.SeriesStore) SeriesMediator {
+	return &seriesMediator{
+		logger: logger,
+		store:  seriesStore,
+	}
+}
+
+// Get returns a list of Series
+func (m *seriesMediator) Get(ctx context.Context) ([]models.Series, error) {
+	return m.store.GetAllSeries(ctx)
+}
+
+// GetBooks returns the books of a series in reading order, or models.ErrNotFound
+// when there is no such series
+func (m *seriesMediator) GetBooks(ctx context.Context, req models.SeriesBooksRequest) ([]models.Book, error) {
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.store.GetSeries(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetSeriesBooks(ctx, id)
+}
@@ -0,0 +1,81 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type SeriesStoreMock struct {
+	SeriesField []models.Series
+	BookField   []models.Book
+	ErrorField  error
+}
+
+func (m *SeriesStoreMock) GetAllSeries(ctx context.Context) ([]models.Series, error) {
+	return m.SeriesField, m.ErrorField
+}
+
+func (m *SeriesStoreMock) GetSeries(ctx context.Context, id int64) (models.Series, error) {
+	for _, series := range m.SeriesField {
+		if series.ID == id {
+			return series, nil
+		}
+	}
+	return models.Series{}, models.ErrNotFound
+}
+
+func (m *SeriesStoreMock) GetSeriesBooks(ctx context.Context, id int64) ([]models.Book, error) {
+	return m.BookField, m.ErrorField
+}
+
+func TestSeriesMediator_Get(t *testing.T) {
+	store := &SeriesStoreMock{SeriesField: []models.Series{{ID: 1, Name: "Discworld"}}}
+	m := mediators.NewSeriesMediator(log.NewEntry(log.New()), store)
+
+	series, err := m.Get(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, series, 1)
+
+	store.ErrorField = errors.New("Error")
+	_, err = m.Get(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestSeriesMediator_GetBooks(t *testing.T) {
+	var cases = []struct {
+		name   string
+		req    models.SeriesBooksRequest
+		assert func(books []models.Book, err error)
+	}{
+		{
+			name: "success",
+			req:  models.SeriesBooksRequest{ID: "1"},
+			assert: func(books []models.Book, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, books, 2)
+			},
+		},
+		{
+			name: "not found",
+			req:  models.SeriesBooksRequest{ID: "2"},
+			assert: func(books []models.Book, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			},
+		},
+	}
+	for _, c := range cases {
+		store := &SeriesStoreMock{
+			SeriesField: []models.Series{{ID: 1, Name: "Discworld"}},
+			BookField:   []models.Book{{ID: 4}, {ID: 5}},
+		}
+		m := mediators.NewSeriesMediator(log.NewEntry(log.New()), store)
+		books, err := m.GetBooks(context.Background(), c.req)
+		c.assert(books, err)
+	}
+}
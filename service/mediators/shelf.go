@@ -0,0 +1,200 @@
+package mediators
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+// shareTokenSize is the number of random bytes of the token of a shared shelf
+const shareTokenSize = 18
+
+// ShelfMediator specifies the methods to manage the shelves of the users
+type ShelfMediator interface {
+	GetAll(ctx context.Context, userID int64) ([]models.Shelf, error)
+	Get(ctx context.Context, userID, shelfID int64) (models.Shelf, error)
+	GetShared(ctx context.Context, token string) (models.Shelf, error)
+	Create(ctx context.Context, userID int64, req models.ShelfRequest) (models.Shelf, error)
+	Rename(ctx context.Context, userID, shelfID int64, req models.ShelfRequest) (models.Shelf, error)
+	Delete(ctx context.Context, userID, shelfID int64) error
+	AddBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error)
+	MoveBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error)
+	RemoveBook(ctx context.Context, userID, shelfID, bookID int64) error
+	Share(ctx context.Context, userID, shelfID int64) (string, error)
+	Unshare(ctx context.Context, userID, shelfID int64) error
+}
+
+// shelfMediator is the concrete implementation of the ShelfMediator interface
+type shelfMediator struct {
+	logger *log.Entry
+	store  stores.ShelfStore
+}
+
+// NewShelfMediator returns a new instance of ShelfMediator
+func NewShelfMediator(logger *log.Entry, shelfStore stores.ShelfStore) ShelfMediator {
+	return &shelfMediator{
+		logger: logger,
+		store:  shelfStore,
+	}
+}
+
+// GetAll returns the shelves of the user, built-in ones first
+func (m *shelfMediator) GetAll(ctx context.Context, userID int64) ([]models.Shelf, error) {
+	if err := m.store.EnsureDefaultShelves(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetShelves(ctx, userID)
+}
+
+// Get returns a shelf of the user along with its books
+func (m *shelfMediator) Get(ctx context.Context, userID, shelfID int64) (models.Shelf, error) {
+	shelf, err := m.store.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return models.Shelf{}, err
+	}
+
+	return m.withBooks(ctx, shelf)
+}
+
+// GetShared returns the shelf shared with the given token along with its books
+func (m *shelfMediator) GetShared(ctx context.Context, token string) (models.Shelf, error) {
+	shelf, err := m.store.GetSharedShelf(ctx, token)
+	if err != nil {
+		return models.Shelf{}, err
+	}
+
+	return m.withBooks(ctx, shelf)
+}
+
+// Create creates a custom shelf for the user
+func (m *shelfMediator) Create(ctx context.Context, userID int64, req models.ShelfRequest) (models.Shelf, error) {
+	shelf, err := m.store.CreateShelf(ctx, userID, strings.TrimSpace(req.Name))
+	if err != nil {
+		return models.Shelf{}, err
+	}
+	shelf.Books = make([]models.ShelfEntry, 0)
+
+	return shelf, nil
+}
+
+// Rename renames a custom shelf of the user, built-in shelves keep their name
+func (m *shelfMediator) Rename(ctx context.Context, userID, shelfID int64, req models.ShelfRequest) (models.Shelf, error) {
+	if _, err := m.customShelf(ctx, userID, shelfID); err != nil {
+		return models.Shelf{}, err
+	}
+	if err := m.store.RenameShelf(ctx, shelfID, strings.TrimSpace(req.Name)); err != nil {
+		return models.Shelf{}, err
+	}
+
+	return m.Get(ctx, userID, shelfID)
+}
+
+// Delete deletes a custom shelf of the user, built-in shelves cannot be deleted
+func (m *shelfMediator) Delete(ctx context.Context, userID, shelfID int64) error {
+	if _, err := m.customShelf(ctx, userID, shelfID); err != nil {
+		return err
+	}
+
+	return m.store.DeleteShelf(ctx, shelfID)
+}
+
+// AddBook puts a book on a shelf of the user
+func (m *shelfMediator) AddBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error) {
+	if _, err := m.store.GetShelf(ctx, userID, shelfID); err != nil {
+		return models.Shelf{}, err
+	}
+	if err := m.store.AddEntry(ctx, shelfID, req); err != nil {
+		return models.Shelf{}, err
+	}
+
+	return m.Get(ctx, userID, shelfID)
+}
+
+// MoveBook moves a book of a shelf of the user to another position
+func (m *shelfMediator) MoveBook(ctx context.Context, userID, shelfID int64, req models.ShelfEntryRequest) (models.Shelf, error) {
+	if _, err := m.store.GetShelf(ctx, userID, shelfID); err != nil {
+		return models.Shelf{}, err
+	}
+	if err := m.store.MoveEntry(ctx, shelfID, req); err != nil {
+		return models.Shelf{}, err
+	}
+
+	return m.Get(ctx, userID, shelfID)
+}
+
+// RemoveBook takes a book off a shelf of the user
+func (m *shelfMediator) RemoveBook(ctx context.Context, userID, shelfID, bookID int64) error {
+	if _, err := m.store.GetShelf(ctx, userID, shelfID); err != nil {
+		return err
+	}
+
+	return m.store.RemoveEntry(ctx, shelfID, bookID)
+}
+
+// Share returns the token of the public read-only link of a shelf of the user,
+// creating it when the shelf is not shared yet
+func (m *shelfMediator) Share(ctx context.Context, userID, shelfID int64) (string, error) {
+	shelf, err := m.store.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return "", err
+	}
+	if shelf.ShareToken != nil {
+		return *shelf.ShareToken, nil
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return "", err
+	}
+	if err := m.store.SetShareToken(ctx, shelfID, &token); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Unshare revokes the public link of a shelf of the user
+func (m *shelfMediator) Unshare(ctx context.Context, userID, shelfID int64) error {
+	if _, err := m.store.GetShelf(ctx, userID, shelfID); err != nil {
+		return err
+	}
+
+	return m.store.SetShareToken(ctx, shelfID, nil)
+}
+
+// customShelf returns a custom shelf of the user, or models.ErrConflict for built-in shelves
+func (m *shelfMediator) customShelf(ctx context.Context, userID, shelfID int64) (models.Shelf, error) {
+	shelf, err := m.store.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return models.Shelf{}, err
+	}
+	if shelf.Kind != models.ShelfCustom {
+		return models.Shelf{}, models.NewError(models.KindConflict, "the built-in shelf %q cannot be changed", shelf.Name)
+	}
+
+	return shelf, nil
+}
+
+func (m *shelfMediator) withBooks(ctx context.Context, shelf models.Shelf) (models.Shelf, error) {
+	entries, err := m.store.GetShelfEntries(ctx, shelf.ID)
+	if err != nil {
+		return models.Shelf{}, err
+	}
+	shelf.Books = entries
+
+	return shelf, nil
+}
+
+func newShareToken() (string, error) {
+	token := make([]byte, shareTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
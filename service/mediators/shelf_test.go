@@ -0,0 +1,229 @@
+package mediators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type ShelfStoreMock struct {
+	ShelvesField    []models.Shelf
+	EntriesField    []models.ShelfEntry
+	ShareTokenField *string
+	EnsuredField    bool
+	RenamedField    string
+	DeletedField    bool
+	EntryField      models.ShelfEntryRequest
+	ErrorField      error
+	EntryErrorField error
+}
+
+func (m *ShelfStoreMock) EnsureDefaultShelves(ctx context.Context, userID int64) error {
+	m.EnsuredField = true
+	return m.ErrorField
+}
+
+func (m *ShelfStoreMock) GetShelves(ctx context.Context, userID int64) ([]models.Shelf, error) {
+	return m.ShelvesField, m.ErrorField
+}
+
+func (m *ShelfStoreMock) GetShelf(ctx context.Context, userID, shelfID int64) (models.Shelf, error) {
+	if m.ErrorField != nil {
+		return models.Shelf{}, m.ErrorField
+	}
+	for _, shelf := range m.ShelvesField {
+		if shelf.ID == shelfID {
+			return shelf, nil
+		}
+	}
+	return models.Shelf{}, models.ErrNotFound
+}
+
+func (m *ShelfStoreMock) GetSharedShelf(ctx context.Context, token string) (models.Shelf, error) {
+	for _, shelf := range m.ShelvesField {
+		if shelf.ShareToken != nil && *shelf.ShareToken == token {
+			return shelf, nil
+		}
+	}
+	return models.Shelf{}, models.ErrNotFound
+}
+
+func (m *ShelfStoreMock) GetShelfEntries(ctx context.Context, shelfID int64) ([]models.ShelfEntry, error) {
+	return m.EntriesField, m.ErrorField
+}
+
+func (m *ShelfStoreMock) CreateShelf(ctx context.Context, userID int64, name string) (models.Shelf, error) {
+	return models.Shelf{ID: 10, Name: name, Kind: models.ShelfCustom}, m.ErrorField
+}
+
+func (m *ShelfStoreMock) RenameShelf(ctx context.Context, shelfID int64, name string) error {
+	m.RenamedField = name
+	return m.ErrorField
+}
+
+func (m *ShelfStoreMock) DeleteShelf(ctx context.Context, shelfID int64) error {
+	m.DeletedField = true
+	return m.ErrorField
+}
+
+func (m *ShelfStoreMock) SetShareToken(ctx context.Context, shelfID int64, token *string) error {
+	m.ShareTokenField = token
+	return m.ErrorField
+}
+
+func (m *ShelfStoreMock) AddEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error {
+	m.EntryField = entry
+	return m.EntryErrorField
+}
+
+func (m *ShelfStoreMock) MoveEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error {
+	m.EntryField = entry
+	return m.EntryErrorField
+}
+
+func (m *ShelfStoreMock) RemoveEntry(ctx context.Context, shelfID, bookID int64) error {
+	m.EntryField = models.ShelfEntryRequest{BookID: bookID}
+	return m.EntryErrorField
+}
+
+func shelves() []models.Shelf {
+	token := "token"
+	return []models.Shelf{
+		{ID: 1, Name: "Want to read", Kind: models.ShelfWantToRead},
+		{ID: 2, Name: "Read", Kind: models.ShelfRead, ShareToken: &token},
+		{ID: 3, Name: "Favourites", Kind: models.ShelfCustom},
+	}
+}
+
+func TestShelfMediator_GetAll(t *testing.T) {
+	store := &ShelfStoreMock{ShelvesField: shelves()}
+	m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+
+	result, err := m.GetAll(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.True(t, store.EnsuredField, "default shelves should be created")
+	assert.Len(t, result, 3)
+
+	store = &ShelfStoreMock{ErrorField: errors.New("Error")}
+	m = mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+	_, err = m.GetAll(context.Background(), 1)
+	assert.NotNil(t, err)
+}
+
+func TestShelfMediator_Get(t *testing.T) {
+	entries := []models.ShelfEntry{{Book: models.Book{ID: 5}, Position: 1}}
+	store := &ShelfStoreMock{ShelvesField: shelves(), EntriesField: entries}
+	m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+
+	result, err := m.Get(context.Background(), 1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "Favourites", result.Name)
+	assert.Equal(t, entries, result.Books)
+
+	_, err = m.Get(context.Background(), 1, 4)
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+
+	result, err = m.GetShared(context.Background(), "token")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.ID)
+	assert.Equal(t, entries, result.Books)
+
+	_, err = m.GetShared(context.Background(), "other")
+	assert.True(t, errors.Is(err, models.ErrNotFound))
+}
+
+func TestShelfMediator_Rename(t *testing.T) {
+	var cases = []struct {
+		name    string
+		shelfID int64
+		assert  func(store *ShelfStoreMock, result models.Shelf, err error)
+	}{
+		{
+			name:    "success",
+			shelfID: 3,
+			assert: func(store *ShelfStoreMock, result models.Shelf, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "Classics", store.RenamedField, "name should be trimmed")
+			},
+		},
+		{
+			name:    "conflict - built-in shelf",
+			shelfID: 1,
+			assert: func(store *ShelfStoreMock, result models.Shelf, err error) {
+				assert.True(t, errors.Is(err, models.ErrConflict))
+				assert.Empty(t, store.RenamedField)
+			},
+		},
+		{
+			name:    "not found",
+			shelfID: 4,
+			assert: func(store *ShelfStoreMock, result models.Shelf, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			},
+		},
+	}
+	for _, c := range cases {
+		store := &ShelfStoreMock{ShelvesField: shelves()}
+		m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+		result, err := m.Rename(context.Background(), 1, c.shelfID, models.ShelfRequest{Name: " Classics "})
+		c.assert(store, result, err)
+	}
+}
+
+func TestShelfMediator_Delete(t *testing.T) {
+	store := &ShelfStoreMock{ShelvesField: shelves()}
+	m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+
+	err := m.Delete(context.Background(), 1, 2)
+	assert.True(t, errors.Is(err, models.ErrConflict))
+	assert.False(t, store.DeletedField)
+
+	err = m.Delete(context.Background(), 1, 3)
+	assert.Nil(t, err)
+	assert.True(t, store.DeletedField)
+}
+
+func TestShelfMediator_Entries(t *testing.T) {
+	store := &ShelfStoreMock{ShelvesField: shelves()}
+	m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+
+	_, err := m.AddBook(context.Background(), 1, 1, models.ShelfEntryRequest{BookID: 7, Position: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, models.ShelfEntryRequest{BookID: 7, Position: 2}, store.EntryField)
+
+	_, err = m.MoveBook(context.Background(), 1, 4, models.ShelfEntryRequest{BookID: 7, Position: 1})
+	assert.True(t, errors.Is(err, models.ErrNotFound), "shelves of other users should not be found")
+
+	store.EntryErrorField = models.ErrConflict
+	_, err = m.AddBook(context.Background(), 1, 1, models.ShelfEntryRequest{BookID: 7})
+	assert.True(t, errors.Is(err, models.ErrConflict))
+
+	store.EntryErrorField = nil
+	err = m.RemoveBook(context.Background(), 1, 3, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), store.EntryField.BookID)
+}
+
+func TestShelfMediator_Share(t *testing.T) {
+	store := &ShelfStoreMock{ShelvesField: shelves()}
+	m := mediators.NewShelfMediator(log.NewEntry(log.New()), store)
+
+	token, err := m.Share(context.Background(), 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token, "shared shelves should keep their token")
+	assert.Nil(t, store.ShareTokenField)
+
+	token, err = m.Share(context.Background(), 1, 3)
+	assert.Nil(t, err)
+	assert.Len(t, token, 24)
+	assert.Equal(t, &token, store.ShareTokenField)
+
+	err = m.Unshare(context.Background(), 1, 2)
+	assert.Nil(t, err)
+	assert.Nil(t, store.ShareTokenField)
+}
@@ -2,15 +2,20 @@ package mediators
 
 import (
 	"context"
+	"strings"
 
 	"github.com/book-recommendations/service/models"
 	"github.com/book-recommendations/service/stores"
 	log "github.com/sirupsen/logrus"
 )
 
-// SizeMediator specifies the methods to get sizes
+// SizeMediator specifies the methods to get and manage sizes
 type SizeMediator interface {
 	Get(ctx context.Context) ([]models.Size, error)
+	GetAll(ctx context.Context) ([]models.Size, error)
+	Create(ctx context.Context, req models.SizeRequest) (models.Size, error)
+	Update(ctx context.Context, id int64, req models.SizeRequest) (models.Size, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 // sizeMediator is the concrete implementation of the SizeMediator interface
@@ -27,14 +32,54 @@ func NewSizeMediator(logger *log.Entry, sizeStore stores.SizeStore) SizeMediator
 	}
 }
 
-// Get returns a list of Sizes
+// Get returns a list of the active Sizes
 func (m *sizeMediator) Get(ctx context.Context) ([]models.Size, error) {
 	var sizes []models.Size
 
-	sizes, err := m.store.GetAllSizes(ctx)
+	sizes, err := m.store.GetAllSizes(ctx, false)
 	if err != nil {
 		return nil, err
 	}
 
 	return sizes, nil
 }
+
+// GetAll returns every size, the retired ones included
+func (m *sizeMediator) GetAll(ctx context.Context) ([]models.Size, error) {
+	return m.store.GetAllSizes(ctx, true)
+}
+
+// Create creates a size, unless it overlaps an active one and the request does not allow it
+func (m *sizeMediator) Create(ctx context.Context, req models.SizeRequest) (models.Size, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	size := req.Size(0)
+	return m.store.CreateSize(ctx, size, sizeOverlapCheck(size, req.AllowOverlap))
+}
+
+// Update replaces a size, unless it overlaps an active one and the request does not allow it
+func (m *sizeMediator) Update(ctx context.Context, id int64, req models.SizeRequest) (models.Size, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	size := req.Size(id)
+	return m.store.UpdateSize(ctx, size, sizeOverlapCheck(size, req.AllowOverlap))
+}
+
+// Delete deletes a size
+func (m *sizeMediator) Delete(ctx context.Context, id int64) error {
+	return m.store.DeleteSize(ctx, id)
+}
+
+// sizeOverlapCheck returns the check of the store that fails with a conflict when the pages of an
+// active size overlap the ones of another active size, unless allowed
+func sizeOverlapCheck(size models.Size, allowed bool) func(active []models.Size) error {
+	return func(active []models.Size) error {
+		if allowed || !size.Active {
+			return nil
+		}
+		for _, other := range active {
+			if other.ID != size.ID && models.Overlaps(size.MinPages, size.MaxPages, other.MinPages, other.MaxPages) {
+				return models.NewError(models.KindConflict, "the pages overlap the ones of size %d %q, which allowOverlap allows", other.ID, other.Title)
+			}
+		}
+		return nil
+	}
+}
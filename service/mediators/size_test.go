@@ -13,14 +13,46 @@ import (
 )
 
 type SizeStoreMock struct {
-	SizeField  []models.Size
-	ErrorField error
+	SizeField     []models.Size
+	SavedField    *models.Size
+	DeletedField  int64
+	InactiveField bool
+	ErrorField    error
 }
 
-func (m *SizeStoreMock) GetAllSizes(ctx context.Context) ([]models.Size, error) {
+func (m *SizeStoreMock) GetAllSizes(ctx context.Context, includeInactive bool) ([]models.Size, error) {
+	m.InactiveField = includeInactive
 	return m.SizeField, m.ErrorField
 }
 
+func (m *SizeStoreMock) CreateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error) {
+	if m.ErrorField != nil {
+		return models.Size{}, m.ErrorField
+	}
+	if err := check(m.SizeField); err != nil {
+		return models.Size{}, err
+	}
+	size.ID = int64(len(m.SizeField) + 1)
+	m.SavedField = &size
+	return size, nil
+}
+
+func (m *SizeStoreMock) UpdateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error) {
+	if m.ErrorField != nil {
+		return models.Size{}, m.ErrorField
+	}
+	if err := check(m.SizeField); err != nil {
+		return models.Size{}, err
+	}
+	m.SavedField = &size
+	return size, nil
+}
+
+func (m *SizeStoreMock) DeleteSize(ctx context.Context, id int64) error {
+	m.DeletedField = id
+	return m.ErrorField
+}
+
 func TestSizeController_Get(t *testing.T) {
 	MaxPages1 := int64(34)
 	MinPages1 := int64(35)
@@ -81,3 +113,148 @@ func TestSizeController_Get(t *testing.T) {
 		c.assert(res, err)
 	}
 }
+
+func TestSizeMediator_Create(t *testing.T) {
+	pages := func(value int64) *int64 { return &value }
+	inactive := false
+	sizes := []models.Size{
+		{ID: 1, Title: "Any", Active: true},
+		{ID: 2, Title: "Novel – 200 to 500 pages", MinPages: pages(200), MaxPages: pages(499), Active: true},
+		{ID: 3, Title: "Monument – 800 pages and up", MinPages: pages(800), Active: true},
+	}
+
+	var cases = []struct {
+		name   string
+		req    models.SizeRequest
+		store  *SizeStoreMock
+		assert func(store *SizeStoreMock, size models.Size, err error)
+	}{
+		{
+			name:  "success between sizes",
+			req:   models.SizeRequest{Title: " Brick – 500 to 800 pages ", MinPages: pages(500), MaxPages: pages(799), SortOrder: 6},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), size.ID)
+				assert.Equal(t, "Brick – 500 to 800 pages", size.Title)
+				assert.True(t, size.Active)
+				assert.Equal(t, int64(6), size.SortOrder)
+				assert.False(t, store.InactiveField, "only the active sizes are checked")
+			},
+		},
+		{
+			name:  "overlap",
+			req:   models.SizeRequest{Title: "Epic – 450 pages and up", MinPages: pages(450)},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.True(t, errors.Is(err, models.ErrConflict))
+				assert.Contains(t, err.Error(), "size 2")
+				assert.Nil(t, store.SavedField)
+			},
+		},
+		{
+			name:  "overlap of an open bound",
+			req:   models.SizeRequest{Title: "Huge – 1000 pages and up", MinPages: pages(1000)},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.True(t, errors.Is(err, models.ErrConflict))
+				assert.Contains(t, err.Error(), "size 3")
+			},
+		},
+		{
+			name:  "overlap allowed",
+			req:   models.SizeRequest{Title: "Epic – 450 pages and up", MinPages: pages(450), AllowOverlap: true},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, store.SavedField)
+			},
+		},
+		{
+			name:  "retired size not checked",
+			req:   models.SizeRequest{Title: "Epic – 450 pages and up", MinPages: pages(450), Active: &inactive},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.NoError(t, err)
+				assert.False(t, size.Active)
+			},
+		},
+		{
+			name:  "catch-all overlapping none",
+			req:   models.SizeRequest{Title: "All"},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "failure",
+			req:   models.SizeRequest{Title: "Epic – 1500 pages and up", MinPages: pages(1500)},
+			store: &SizeStoreMock{ErrorField: errors.New("Error")},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewSizeMediator(log.NewEntry(log.New()), c.store)
+			size, err := m.Create(context.Background(), c.req)
+			c.assert(c.store, size, err)
+		})
+	}
+}
+
+func TestSizeMediator_Update(t *testing.T) {
+	pages := func(value int64) *int64 { return &value }
+	sizes := []models.Size{
+		{ID: 2, Title: "Novel – 200 to 500 pages", MinPages: pages(200), MaxPages: pages(499), Active: true},
+		{ID: 3, Title: "Monument – 800 pages and up", MinPages: pages(800), Active: true},
+	}
+
+	var cases = []struct {
+		name   string
+		id     int64
+		req    models.SizeRequest
+		store  *SizeStoreMock
+		assert func(store *SizeStoreMock, size models.Size, err error)
+	}{
+		{
+			name:  "success overlapping itself",
+			id:    2,
+			req:   models.SizeRequest{Title: "Novel – 200 to 600 pages", MinPages: pages(200), MaxPages: pages(599)},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), size.ID)
+				assert.Equal(t, pages(599), store.SavedField.MaxPages)
+			},
+		},
+		{
+			name:  "overlap",
+			id:    2,
+			req:   models.SizeRequest{Title: "Novel – 200 pages and up", MinPages: pages(200)},
+			store: &SizeStoreMock{SizeField: sizes},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.True(t, errors.Is(err, models.ErrConflict))
+				assert.Nil(t, store.SavedField)
+			},
+		},
+		{
+			name:  "not found",
+			id:    9,
+			req:   models.SizeRequest{Title: "Tiny", MaxPages: pages(10)},
+			store: &SizeStoreMock{ErrorField: models.ErrNotFound},
+			assert: func(store *SizeStoreMock, size models.Size, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewSizeMediator(log.NewEntry(log.New()), c.store)
+			size, err := m.Update(context.Background(), c.id, c.req)
+			c.assert(c.store, size, err)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package mediators
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// WebhookEventHeader and the following headers are set on every delivery
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader holds "sha256=" followed by the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body of the delivery, keyed by the secret of the subscription
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	// maxReceiverBody is the number of bytes of the answer of a receiver read at most
+	maxReceiverBody = 64 * 1024
+)
+
+// WebhookMediator specifies the methods to manage the webhook subscriptions and deliver
+// the events of the outbox to them
+type WebhookMediator interface {
+	GetAll(ctx context.Context) ([]models.WebhookSubscription, error)
+	Get(ctx context.Context, id int64) (models.WebhookSubscription, error)
+	Create(ctx context.Context, req models.WebhookRequest) (models.WebhookSubscription, error)
+	Update(ctx context.Context, id int64, req models.WebhookRequest) (models.WebhookSubscription, error)
+	Delete(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, id int64, req models.DeliveriesRequest) ([]models.WebhookDelivery, error)
+	Retry(ctx context.Context, id, deliveryID int64) (models.WebhookDelivery, error)
+	Ping(ctx context.Context, id int64) (models.WebhookDelivery, error)
+	Dispatch(ctx context.Context) error
+}
+
+// webhookMediator is the concrete implementation of the WebhookMediator interface
+type webhookMediator struct {
+	logger *log.Entry
+	store  stores.WebhookStore
+	client *http.Client
+	cfg    config.WebhookConfig
+	now    func() time.Time
+}
+
+// NewWebhookMediator returns a new instance of WebhookMediator, delivering with the given client
+func NewWebhookMediator(logger *log.Entry, webhookStore stores.WebhookStore, client *http.Client, cfg config.WebhookConfig) WebhookMediator {
+	return &webhookMediator{
+		logger: logger,
+		store:  webhookStore,
+		client: client,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// GetAll returns every subscription
+func (m *webhookMediator) GetAll(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return m.store.GetSubscriptions(ctx)
+}
+
+// Get returns a subscription
+func (m *webhookMediator) Get(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	return m.store.GetSubscription(ctx, id)
+}
+
+// Create creates a subscription
+func (m *webhookMediator) Create(ctx context.Context, req models.WebhookRequest) (models.WebhookSubscription, error) {
+	req.URL = strings.TrimSpace(req.URL)
+	return m.store.CreateSubscription(ctx, req.Subscription(0))
+}
+
+// Update replaces a subscription, its pending deliveries are delivered to its new URL
+func (m *webhookMediator) Update(ctx context.Context, id int64, req models.WebhookRequest) (models.WebhookSubscription, error) {
+	req.URL = strings.TrimSpace(req.URL)
+	return m.store.UpdateSubscription(ctx, req.Subscription(id))
+}
+
+// Delete deletes a subscription along with its deliveries
+func (m *webhookMediator) Delete(ctx context.Context, id int64) error {
+	return m.store.DeleteSubscription(ctx, id)
+}
+
+// GetDeliveries returns the latest deliveries of a subscription with the log of their attempts
+func (m *webhookMediator) GetDeliveries(ctx context.Context, id int64, req models.DeliveriesRequest) ([]models.WebhookDelivery, error) {
+	if _, err := m.store.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	limit := int64(models.DefaultDeliveries)
+	if req.Limit != "" {
+		var err error
+		if limit, err = strconv.ParseInt(req.Limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.store.GetDeliveries(ctx, id, req.Status, limit)
+}
+
+// Retry makes a dead delivery of a subscription pending again, with all its attempts
+func (m *webhookMediator) Retry(ctx context.Context, id, deliveryID int64) (models.WebhookDelivery, error) {
+	return m.store.RetryDelivery(ctx, id, deliveryID)
+}
+
+// Ping delivers a ping event to a subscription only, to check its receiver
+func (m *webhookMediator) Ping(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	return m.store.Ping(ctx, id)
+}
+
+// Dispatch fans the new events of the outbox out to the subscriptions to their type, and
+// attempts the due deliveries at once. The failed deliveries are attempted again after a
+// backoff doubling at every attempt, until their attempts run out and they are dead.
+func (m *webhookMediator) Dispatch(ctx context.Context) error {
+	events, err := m.store.FanOutEvents(ctx, m.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	// the attempts all run at once, the lease only expires if the dispatcher stops before the end
+	due, err := m.store.ClaimDeliveries(ctx, m.cfg.BatchSize, 2*m.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, delivery := range due {
+		wg.Add(1)
+		go func(delivery models.DueDelivery) {
+			defer wg.Done()
+			if err := m.deliver(ctx, delivery); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	if events > 0 || len(due) > 0 {
+		m.logger.WithField("events", events).WithField("deliveries", len(due)).Info("webhooks dispatched")
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver attempts a delivery and records the attempt along with the new status of the delivery
+func (m *webhookMediator) deliver(ctx context.Context, due models.DueDelivery) error {
+	delivery := due.Delivery
+	delivery.Attempts++
+	attempt := models.WebhookAttempt{Attempt: delivery.Attempts, AttemptedAt: m.now()}
+
+	statusCode, err := m.post(ctx, due, attempt.AttemptedAt)
+	attempt.DurationMS = m.now().Sub(attempt.AttemptedAt).Milliseconds()
+	attempt.StatusCode = statusCode
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		deliveredAt := m.now()
+		delivery.DeliveredAt = &deliveredAt
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= m.cfg.MaxAttempts:
+		attempt.Error = err.Error()
+		delivery.Status = models.DeliveryDead
+		delivery.NextAttemptAt = nil
+		m.logger.WithField("delivery", delivery.ID).WithError(err).Warn("webhook delivery dead")
+	default:
+		attempt.Error = err.Error()
+		nextAttemptAt := m.now().Add(m.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	return m.store.RecordAttempt(ctx, delivery, attempt)
+}
+
+// post posts the event of a delivery to the URL of its subscription, signed with its
+// secret, and returns the status answered by the receiver, if any. Only a 2xx status
+// delivers the event, the redirects are not followed.
+func (m *webhookMediator) post(ctx context.Context, due models.DueDelivery, at time.Time) (*int64, error) {
+	body, err := json.Marshal(due.Event)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event: %w", err)
+	}
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, due.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(due.Delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+sign(due.Subscription.Secret, timestamp, body))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxReceiverBody))
+
+	statusCode := int64(resp.StatusCode)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &statusCode, fmt.Errorf("receiver answered %d", resp.StatusCode)
+	}
+	return &statusCode, nil
+}
+
+// backoff returns the time to wait after the given failed attempt, doubling from the
+// base at every attempt up to the max
+func (m *webhookMediator) backoff(attempt int64) time.Duration {
+	wait := m.cfg.BackoffBase
+	for i := int64(1); i < attempt && wait < m.cfg.BackoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, m.cfg.BackoffMax)
+}
+
+// sign returns the hex HMAC-SHA256 of the timestamp, a dot and the body, keyed by the secret
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,256 @@
+package mediators_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/book-recommendations/service/config"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type WebhookStoreMock struct {
+	SubscriptionField models.WebhookSubscription
+	DueField          []models.DueDelivery
+	EventsField       int64
+	SavedField        *models.WebhookSubscription
+	StatusField       string
+	LimitField        int64
+	LeaseField        time.Duration
+	AttemptsField     map[int64]models.WebhookAttempt
+	RecordedField     map[int64]models.WebhookDelivery
+	ErrorField        error
+
+	mu sync.Mutex
+}
+
+func (m *WebhookStoreMock) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return []models.WebhookSubscription{m.SubscriptionField}, m.ErrorField
+}
+
+func (m *WebhookStoreMock) GetSubscription(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	return m.SubscriptionField, m.ErrorField
+}
+
+func (m *WebhookStoreMock) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	m.SavedField = &subscription
+	subscription.ID = 1
+	return subscription, m.ErrorField
+}
+
+func (m *WebhookStoreMock) UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	m.SavedField = &subscription
+	return subscription, m.ErrorField
+}
+
+func (m *WebhookStoreMock) DeleteSubscription(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+func (m *WebhookStoreMock) GetDeliveries(ctx context.Context, subscriptionID int64, status string, limit int64) ([]models.WebhookDelivery, error) {
+	m.StatusField = status
+	m.LimitField = limit
+	return nil, nil
+}
+
+func (m *WebhookStoreMock) RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (models.WebhookDelivery, error) {
+	return models.WebhookDelivery{ID: deliveryID, Status: models.DeliveryPending}, m.ErrorField
+}
+
+func (m *WebhookStoreMock) Ping(ctx context.Context, subscriptionID int64) (models.WebhookDelivery, error) {
+	return models.WebhookDelivery{SubscriptionID: subscriptionID, EventType: models.ChangePing}, m.ErrorField
+}
+
+func (m *WebhookStoreMock) FanOutEvents(ctx context.Context, limit int64) (int64, error) {
+	return m.EventsField, m.ErrorField
+}
+
+func (m *WebhookStoreMock) ClaimDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]models.DueDelivery, error) {
+	m.LeaseField = lease
+	return m.DueField, m.ErrorField
+}
+
+func (m *WebhookStoreMock) RecordAttempt(ctx context.Context, delivery models.WebhookDelivery, attempt models.WebhookAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.RecordedField == nil {
+		m.RecordedField = map[int64]models.WebhookDelivery{}
+		m.AttemptsField = map[int64]models.WebhookAttempt{}
+	}
+	m.RecordedField[delivery.ID] = delivery
+	m.AttemptsField[delivery.ID] = attempt
+	return nil
+}
+
+func TestWebhookMediator_Create(t *testing.T) {
+	inactive := false
+	store := &WebhookStoreMock{}
+	m := mediators.NewWebhookMediator(log.NewEntry(log.New()), store, http.DefaultClient, config.WebhookConfig{})
+
+	subscription, err := m.Create(context.Background(), models.WebhookRequest{
+		URL:        " https://partner.example.com/hooks ",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{models.ChangeBookCreated},
+		Active:     &inactive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), subscription.ID)
+	assert.Equal(t, "https://partner.example.com/hooks", store.SavedField.URL)
+	assert.Equal(t, "0123456789abcdef", store.SavedField.Secret)
+	assert.False(t, store.SavedField.Active)
+}
+
+func TestWebhookMediator_GetDeliveries(t *testing.T) {
+	var cases = []struct {
+		name   string
+		req    models.DeliveriesRequest
+		store  *WebhookStoreMock
+		assert func(store *WebhookStoreMock, err error)
+	}{
+		{
+			name:  "default limit",
+			store: &WebhookStoreMock{},
+			assert: func(store *WebhookStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(models.DefaultDeliveries), store.LimitField)
+			},
+		},
+		{
+			name:  "dead only",
+			req:   models.DeliveriesRequest{Status: models.DeliveryDead, Limit: "5"},
+			store: &WebhookStoreMock{},
+			assert: func(store *WebhookStoreMock, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, models.DeliveryDead, store.StatusField)
+				assert.Equal(t, int64(5), store.LimitField)
+			},
+		},
+		{
+			name:  "unknown subscription",
+			store: &WebhookStoreMock{ErrorField: models.NewError(models.KindNotFound, "webhook 7 not found")},
+			assert: func(store *WebhookStoreMock, err error) {
+				assert.True(t, errors.Is(err, models.ErrNotFound))
+				assert.Zero(t, store.LimitField)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mediators.NewWebhookMediator(log.NewEntry(log.New()), c.store, http.DefaultClient, config.WebhookConfig{})
+			_, err := m.GetDeliveries(context.Background(), 7, c.req)
+			c.assert(c.store, err)
+		})
+	}
+}
+
+func TestWebhookMediator_Dispatch(t *testing.T) {
+	const secret = "0123456789abcdef"
+	cfg := config.WebhookConfig{
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+		BackoffBase: time.Minute,
+		BackoffMax:  3 * time.Minute,
+		BatchSize:   10,
+	}
+
+	var (
+		mu     sync.Mutex
+		signed = map[string]bool{}
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(r.Header.Get(mediators.WebhookTimestampHeader) + "."))
+		mac.Write(body)
+
+		mu.Lock()
+		signed[r.Header.Get(mediators.WebhookDeliveryHeader)] = r.Header.Get(mediators.WebhookSignatureHeader) == "sha256="+hex.EncodeToString(mac.Sum(nil)) &&
+			r.Header.Get(mediators.WebhookEventHeader) == models.ChangeBookUpdated
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusNoContent)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	due := func(id int64, path string, attempts int64) models.DueDelivery {
+		return models.DueDelivery{
+			Delivery:     models.WebhookDelivery{ID: id, Status: models.DeliveryPending, Attempts: attempts},
+			Subscription: models.WebhookSubscription{ID: 1, URL: receiver.URL + path, Secret: secret},
+			Event:        models.ChangeEvent{ID: 310, Type: models.ChangeBookUpdated, EntityID: 4, Data: []byte(`{"id":4}`)},
+		}
+	}
+	store := &WebhookStoreMock{
+		EventsField: 2,
+		DueField: []models.DueDelivery{
+			due(1, "/ok", 0),
+			due(2, "/down", 0),
+			due(3, "/down", 1),
+			due(4, "/down", 2),
+			due(5, "/moved", 0),
+		},
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	m := mediators.NewWebhookMediator(log.NewEntry(log.New()), store, client, cfg)
+
+	start := time.Now()
+	require.NoError(t, m.Dispatch(context.Background()))
+	assert.Equal(t, 2*cfg.Timeout, store.LeaseField)
+	assert.Len(t, store.RecordedField, 5)
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		assert.True(t, signed[id], "delivery %s should be signed", id)
+	}
+
+	delivered := store.RecordedField[1]
+	assert.Equal(t, models.DeliveryDelivered, delivered.Status)
+	assert.Equal(t, int64(1), delivered.Attempts)
+	assert.NotNil(t, delivered.DeliveredAt)
+	assert.Nil(t, delivered.NextAttemptAt)
+	assert.Equal(t, int64(http.StatusNoContent), *store.AttemptsField[1].StatusCode)
+	assert.Empty(t, store.AttemptsField[1].Error)
+
+	first := store.RecordedField[2]
+	assert.Equal(t, models.DeliveryPending, first.Status)
+	assert.WithinDuration(t, start.Add(time.Minute), *first.NextAttemptAt, 5*time.Second)
+	assert.Equal(t, "receiver answered 503", store.AttemptsField[2].Error)
+
+	second := store.RecordedField[3]
+	assert.Equal(t, models.DeliveryPending, second.Status)
+	assert.Equal(t, int64(2), store.AttemptsField[3].Attempt)
+	assert.WithinDuration(t, start.Add(2*time.Minute), *second.NextAttemptAt, 5*time.Second)
+
+	dead := store.RecordedField[4]
+	assert.Equal(t, models.DeliveryDead, dead.Status)
+	assert.Equal(t, int64(3), dead.Attempts)
+	assert.Nil(t, dead.NextAttemptAt)
+
+	redirected := store.RecordedField[5]
+	assert.Equal(t, models.DeliveryPending, redirected.Status, "redirects are not followed")
+	assert.Equal(t, int64(http.StatusFound), *store.AttemptsField[5].StatusCode)
+}
+
+func TestWebhookMediator_DispatchFailure(t *testing.T) {
+	store := &WebhookStoreMock{ErrorField: errors.New("Error")}
+	m := mediators.NewWebhookMediator(log.NewEntry(log.New()), store, http.DefaultClient, config.WebhookConfig{})
+
+	assert.Error(t, m.Dispatch(context.Background()))
+	assert.Empty(t, store.RecordedField)
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// AuditCreate and the following actions are the writes recorded by the audit log
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditMerge   = "merge"
+	AuditRetry   = "retry"
+	AuditPing    = "ping"
+	AuditRebuild = "rebuild"
+
+	// AuditBook and the following entities are the ones whose writes are recorded
+	AuditBook            = "book"
+	AuditAuthor          = "author"
+	AuditSize            = "size"
+	AuditEra             = "era"
+	AuditWebhook         = "webhook"
+	AuditWebhookDelivery = "webhook-delivery"
+	AuditShelf           = "shelf"
+	AuditShelfEntry      = "shelf-entry"
+	AuditSimilarity      = "similarity"
+	AuditRankingPrior    = "ranking-prior"
+	AuditEvent           = "event"
+
+	// AuditSystem is the actor of the writes made by the service itself, such as its jobs
+	AuditSystem = "system"
+	// AuditAdmin is the actor of the admin requests made on behalf of no known user
+	AuditAdmin = "admin"
+	// AuditAnonymous is the actor of the other requests made on behalf of no known user
+	AuditAnonymous = "anonymous"
+
+	// DefaultAuditEntries is the number of entries listed when no limit is given
+	DefaultAuditEntries = 50
+)
+
+// AuditEntry is a write of the store: the entity before and after it, nothing for the
+// entities it created or deleted, and Diff holding the before and after value of every
+// top-level field that changed
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entityId"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Diff      json.RawMessage `json:"diff"`
+	RequestID string          `json:"requestId,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// AuditRequest holds the parameters to list the entries of the audit log, latest first
+type AuditRequest struct {
+	Entity string `json:"entity"`
+	ID     string `json:"id"`
+	Actor  string `json:"actor"`
+	Since  string `json:"since"`
+	Until  string `json:"until"`
+	Limit  string `json:"limit"`
+	Offset string `json:"offset"`
+}
+
+// AuditFilter is the parsed AuditRequest, a zero field matching every entry
+type AuditFilter struct {
+	Entity   string
+	EntityID int64
+	Actor    string
+	Since    time.Time
+	Until    time.Time
+	Limit    int64
+	Offset   int64
+}
+
+var (
+	auditEntityRules = []validation.Rule{
+		validation.In(AuditBook, AuditAuthor, AuditSize, AuditEra, AuditWebhook, AuditWebhookDelivery, AuditShelf,
+			AuditShelfEntry, AuditSimilarity, AuditRankingPrior, AuditEvent).
+			Error("should be one of: book, author, size, era, webhook, webhook-delivery, shelf, shelf-entry, similarity, ranking-prior, event"),
+	}
+	timestampRules = []validation.Rule{
+		validation.Date(time.RFC3339).Error("should be an RFC 3339 timestamp, such as 2024-03-01T10:00:00Z"),
+	}
+)
+
+func (ar AuditRequest) Validate() error {
+	reqCopy := ar
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Entity, auditEntityRules...),
+		validation.Field(&reqCopy.ID, validation.By(reqCopy.validateEntityID)),
+		validation.Field(&reqCopy.Since, timestampRules...),
+		validation.Field(&reqCopy.Until, timestampRules...),
+		validation.Field(&reqCopy.Limit, limitRules...),
+		validation.Field(&reqCopy.Offset, offsetRules...),
+	)
+}
+
+// validateEntityID checks the optional ID of the entity, which only makes sense along with it
+func (ar AuditRequest) validateEntityID(value interface{}) error {
+	if ar.ID == "" {
+		return nil
+	}
+	if ar.Entity == "" {
+		return errors.New("needs the entity")
+	}
+	return validation.Validate(ar.ID, idRules...)
+}
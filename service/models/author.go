@@ -1,7 +1,142 @@
 package models
 
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	// MainGenres is the number of genres of an author detailed as their main genres
+	MainGenres = 3
+
+	// DefaultDuplicateAuthors is the number of candidate duplicates listed when no limit is given
+	DefaultDuplicateAuthors = 50
+)
+
 type Author struct {
 	ID        int64  `json:"id"`
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 }
+
+// AuthorDetail is an author along with the figures of the books they contributed to
+type AuthorDetail struct {
+	Author
+	BookCount int64 `json:"bookCount"`
+	// AverageRating is the average of the ratings of their books weighted by their counts, their
+	// plain average when no user rated them, and 0 when they have no book
+	AverageRating float64 `json:"averageRating"`
+	// YearsActive is nil when the author has no book
+	YearsActive *YearsActive `json:"yearsActive"`
+	MainGenres  []GenreCount `json:"mainGenres"`
+}
+
+// YearsActive are the years the first and the last books of an author were published
+type YearsActive struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// GenreCount is a genre along with the number of books of an author in it
+type GenreCount struct {
+	Genre
+	BookCount int64 `json:"bookCount"`
+}
+
+// AuthorRequest holds the parameters to get an author
+type AuthorRequest struct {
+	ID string `json:"id"`
+}
+
+// AuthorBooksRequest holds the parameters to get the books of an author, filtered and
+// ranked as any request of books
+type AuthorBooksRequest struct {
+	ID    string      `json:"id"`
+	Books BookRequest `json:"-"`
+}
+
+// AuthorCandidate is an author along with the number of their books, deleted ones aside
+type AuthorCandidate struct {
+	Author
+	BookCount int64 `json:"bookCount"`
+}
+
+// DuplicateAuthors is a pair of authors that may be the same person, for review before
+// merging Merge into Keep, the author of the most books. The pair is a candidate when the
+// names are the same once normalized, similar enough, or the authors share titles.
+type DuplicateAuthors struct {
+	Keep  AuthorCandidate `json:"keep"`
+	Merge AuthorCandidate `json:"merge"`
+	// SameNormalizedName is true when the names only differ by case, spacing and punctuation
+	SameNormalizedName bool `json:"sameNormalizedName"`
+	// NameSimilarity is the trigram similarity of the full names, from 0 to 1
+	NameSimilarity float64  `json:"nameSimilarity"`
+	SharedTitles   []string `json:"sharedTitles"`
+}
+
+// DuplicateAuthorsRequest holds the parameters to list the candidate duplicate authors
+type DuplicateAuthorsRequest struct {
+	MinSimilarity string `json:"min-similarity"`
+	Limit         string `json:"limit"`
+}
+
+// DuplicateAuthorsFilter is the parsed DuplicateAuthorsRequest
+type DuplicateAuthorsFilter struct {
+	MinSimilarity float64
+	Limit         int64
+}
+
+// MergeAuthorRequest holds the author another one is merged into
+type MergeAuthorRequest struct {
+	Into int64 `json:"into"`
+}
+
+var similarityRules = []validation.Rule{
+	is.Float.Error("should be numeric"),
+	validation.By(validateMinMaxFloat(0, 1)),
+}
+
+func (dr DuplicateAuthorsRequest) Validate() error {
+	reqCopy := dr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.MinSimilarity, similarityRules...),
+		validation.Field(&reqCopy.Limit, limitRules...),
+	)
+}
+
+func (mr MergeAuthorRequest) Validate() error {
+	reqCopy := mr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Into,
+			validation.Required.Error("is required"),
+			validation.Min(int64(1)).Error("should be a positive author ID"),
+		),
+	)
+}
+
+func (ar AuthorRequest) Validate() error {
+	reqCopy := ar
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.ID, idRules...),
+	)
+}
+
+// Validate reports the invalid parameters of the books along with an invalid ID
+func (ar AuthorBooksRequest) Validate() error {
+	errs := validation.Errors{"id": validation.Validate(ar.ID, idRules...)}
+
+	var bookErrs validation.Errors
+	if err := ar.Books.Validate(); errors.As(err, &bookErrs) {
+		for field, fieldErr := range bookErrs {
+			errs[field] = fieldErr
+		}
+	} else if err != nil {
+		return err
+	}
+	return errs.Filter()
+}
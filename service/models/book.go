@@ -3,8 +3,10 @@ package models
 import (
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
@@ -17,16 +19,87 @@ const (
 	MaxYear  = 2100
 	MinBooks = 1
 	MaxBooks = 1000
+	// MaxOffset is the number of books that can be skipped at most
+	MaxOffset = 10000
+	// MinRating and MaxRating bound the average rating of a book
+	MinRating = 0
+	MaxRating = 5
+
+	// DefaultSimilarBooks is the number of similar books returned when no limit is given
+	DefaultSimilarBooks = 10
+
+	// RankingRaw sorts books by their average rating
+	RankingRaw = "raw"
+	// RankingWeighted sorts books by their confidence-weighted (Bayesian) rating
+	RankingWeighted = "weighted"
+
+	// RankerRating orders books by their average rating
+	RankerRating = "rating"
+	// RankerWeighted orders books by their confidence-weighted rating
+	RankerWeighted = "weighted"
+	// RankerRecency orders books by their weighted rating boosted by how recent they are
+	RankerRecency = "recency"
+	// RankerRandom shuffles the books
+	RankerRandom = "random"
+
+	// GenreMatchAny matches the books with at least one of the requested genres
+	GenreMatchAny = "any"
+	// GenreMatchAll matches the books with every requested genre
+	GenreMatchAll = "all"
+
+	// RoleAuthor is the role of the writers of a book
+	RoleAuthor = "author"
+	// RoleTranslator is the role of the translators of a book
+	RoleTranslator = "translator"
+	// RoleIllustrator is the role of the illustrators of a book
+	RoleIllustrator = "illustrator"
 )
 
 type Book struct {
-	ID            int64   `json:"id"`
-	Title         string  `json:"title"`
-	YearPublished int64   `json:"yearPublished"`
-	Rating        float64 `json:"rating"`
-	Pages         int64   `json:"pages"`
-	Genre         Genre   `json:"genre"`
-	Author        Author  `json:"author"`
+	ID             int64        `json:"id"`
+	Title          string       `json:"title"`
+	YearPublished  int64        `json:"yearPublished"`
+	Rating         float64      `json:"rating"`
+	RatingCount    int64        `json:"ratingCount"`
+	WeightedRating float64      `json:"weightedRating"`
+	Pages          int64        `json:"pages"`
+	Genres         []Genre      `json:"genres"`
+	Authors        []BookAuthor `json:"authors"`
+	Series         *BookSeries  `json:"series,omitempty"`
+	ISBN10         string       `json:"isbn10,omitempty"`
+	ISBN13         string       `json:"isbn13,omitempty"`
+	Language       string       `json:"language,omitempty"`
+	Publisher      string       `json:"publisher,omitempty"`
+	Description    string       `json:"description,omitempty"`
+	CoverURL       string       `json:"coverUrl,omitempty"`
+}
+
+// BookAuthor is a contributor of a book along with their role
+type BookAuthor struct {
+	Author
+	Role string `json:"role"`
+}
+
+// PrimaryAuthor returns the first author of the book, or its first contributor
+// when no one has the author role
+func (b Book) PrimaryAuthor() Author {
+	for _, author := range b.Authors {
+		if author.Role == RoleAuthor {
+			return author.Author
+		}
+	}
+	if len(b.Authors) > 0 {
+		return b.Authors[0].Author
+	}
+	return Author{}
+}
+
+// PrimaryGenre returns the first genre of the book
+func (b Book) PrimaryGenre() Genre {
+	if len(b.Genres) > 0 {
+		return b.Genres[0]
+	}
+	return Genre{}
 }
 
 type BookRequest struct {
@@ -37,9 +110,69 @@ type BookRequest struct {
 	MinYear  string `json:"min-year"`
 	MaxYear  string `json:"max-year"`
 	Limit    string `json:"limit"`
+	// Offset is the number of ranked books to skip, for paging through the results
+	Offset  string `json:"offset"`
+	Ranking string `json:"ranking"`
+	Ranker  string `json:"ranker"`
+	// ExcludeAuthors and ExcludeGenres hide the books with any of the given authors or genres
+	ExcludeAuthors string `json:"exclude-authors"`
+	ExcludeGenres  string `json:"exclude-genres"`
+	// MinRating hides the books rated below it
+	MinRating string `json:"min-rating"`
+	// GenreMatch tells whether a book needs any or all of Genres, any by default
+	GenreMatch string `json:"genre-match"`
+	// ISBN looks a book up by its ISBN-10 or ISBN-13, with or without hyphens
+	ISBN string `json:"isbn"`
+	// Languages is a comma-delimited list of BCP-47 tags, "en" matches "en-GB" as well
+	Languages string `json:"language"`
+	// FirstInSeriesOnly hides the books following another one of their series when "true"
+	FirstInSeriesOnly string `json:"first-in-series-only"`
+	// ExcludeRead hides the books on the "read" shelf of the user when "true"
+	ExcludeRead string `json:"exclude-read"`
+	// Facets is a comma-delimited list of the facets to count along with the books
+	Facets string `json:"facets"`
+	// UserID is the caller, it is only set when a filter depends on the user
+	UserID int64 `json:"-"`
+}
+
+// SimilarBooksRequest holds the parameters to get the books similar to a given one
+type SimilarBooksRequest struct {
+	ID    string `json:"id"`
+	Limit string `json:"limit"`
+}
+
+// BookVersion is a version of the catalog fields of a book, the current one having no
+// ValidUntil. The versions of a deleted book have the time it was deleted at.
+type BookVersion struct {
+	Version        int64      `json:"version"`
+	Title          string     `json:"title"`
+	YearPublished  int64      `json:"yearPublished"`
+	Pages          int64      `json:"pages"`
+	ISBN10         string     `json:"isbn10,omitempty"`
+	ISBN13         string     `json:"isbn13,omitempty"`
+	Language       string     `json:"language,omitempty"`
+	Publisher      string     `json:"publisher,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	CoverURL       string     `json:"coverUrl,omitempty"`
+	SeriesID       *int64     `json:"seriesId,omitempty"`
+	SeriesPosition *int64     `json:"seriesPosition,omitempty"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+	ValidFrom      time.Time  `json:"validFrom"`
+	ValidUntil     *time.Time `json:"validUntil,omitempty"`
+}
+
+// SimilarBook is a book along with its content-similarity score to another book
+type SimilarBook struct {
+	Book
+	Score float64 `json:"score"`
 }
 
 var (
+	idRules = []validation.Rule{
+		validation.Required.Error("is required"),
+		is.Int.Error("should be numeric"),
+		validation.By(validateMinMax(1, math.MaxInt32)),
+	}
 	idsRules = []validation.Rule{
 		validation.Match(regexp.MustCompile("^([0-9]+,)*[0-9]+$")).Error("should be for exaple: 123,456,789"),
 	}
@@ -55,6 +188,35 @@ var (
 		is.Int.Error("should be numeric"),
 		validation.By(validateMinMax(MinBooks, MaxBooks)),
 	}
+	ratingRules = []validation.Rule{
+		is.Float.Error("should be numeric"),
+		validation.By(validateMinMaxFloat(MinRating, MaxRating)),
+	}
+	genreMatchRules = []validation.Rule{
+		validation.In(GenreMatchAny, GenreMatchAll).Error("should be one of: any, all"),
+	}
+	offsetRules = []validation.Rule{
+		is.Int.Error("should be numeric"),
+		validation.By(validateMinMax(0, MaxOffset)),
+	}
+	rankingRules = []validation.Rule{
+		validation.In(RankingRaw, RankingWeighted).Error("should be one of: raw, weighted"),
+	}
+	isbnRules = []validation.Rule{
+		validation.By(validateISBN),
+	}
+	languagesRules = []validation.Rule{
+		validation.Match(regexp.MustCompile("^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*(,[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*)*$")).Error("should be for example: en,pt-BR"),
+	}
+	boolRules = []validation.Rule{
+		validation.In("true", "false").Error("should be one of: true, false"),
+	}
+	facetsRules = []validation.Rule{
+		validation.By(validateFacets),
+	}
+	rankerRules = []validation.Rule{
+		validation.In(RankerRating, RankerWeighted, RankerRecency, RankerRandom).Error("should be one of: rating, weighted, recency, random"),
+	}
 )
 
 func (br BookRequest) Validate() error {
@@ -63,11 +225,32 @@ func (br BookRequest) Validate() error {
 	return validation.ValidateStruct(&reqCopy,
 		validation.Field(&reqCopy.Authors, idsRules...),
 		validation.Field(&reqCopy.Genres, idsRules...),
+		validation.Field(&reqCopy.ExcludeAuthors, idsRules...),
+		validation.Field(&reqCopy.ExcludeGenres, idsRules...),
+		validation.Field(&reqCopy.MinRating, ratingRules...),
+		validation.Field(&reqCopy.GenreMatch, genreMatchRules...),
 		validation.Field(&reqCopy.MinPages, pagesRules...),
 		validation.Field(&reqCopy.MaxPages, pagesRules...),
 		validation.Field(&reqCopy.MinYear, yearRules...),
 		validation.Field(&reqCopy.MaxYear, yearRules...),
 		validation.Field(&reqCopy.Limit, limitRules...),
+		validation.Field(&reqCopy.Offset, offsetRules...),
+		validation.Field(&reqCopy.Ranking, rankingRules...),
+		validation.Field(&reqCopy.Ranker, rankerRules...),
+		validation.Field(&reqCopy.ISBN, isbnRules...),
+		validation.Field(&reqCopy.Languages, languagesRules...),
+		validation.Field(&reqCopy.FirstInSeriesOnly, boolRules...),
+		validation.Field(&reqCopy.ExcludeRead, boolRules...),
+		validation.Field(&reqCopy.Facets, facetsRules...),
+	)
+}
+
+func (sr SimilarBooksRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.ID, idRules...),
+		validation.Field(&reqCopy.Limit, limitRules...),
 	)
 }
 
@@ -83,3 +266,16 @@ func validateMinMax(minValue, maxValue int) validation.RuleFunc {
 		return nil
 	}
 }
+
+func validateMinMaxFloat(minValue, maxValue float64) validation.RuleFunc {
+	return func(value interface{}) error {
+		if value.(string) != "" {
+			f, _ := strconv.ParseFloat(value.(string), 64)
+			if f < minValue || f > maxValue {
+				errorLimit := fmt.Sprintf("should be between %v and %v", minValue, maxValue)
+				return errors.New(errorLimit)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// BookSearchRequest is the JSON body of a search of books. It holds the same criteria
+// as the query string of a BookRequest, with typed values.
+type BookSearchRequest struct {
+	Authors           []int64  `json:"authors"`
+	Genres            []int64  `json:"genres"`
+	GenreMatch        string   `json:"genreMatch"`
+	ExcludeAuthors    []int64  `json:"excludeAuthors"`
+	ExcludeGenres     []int64  `json:"excludeGenres"`
+	Pages             *Range   `json:"pages"`
+	Years             *Range   `json:"years"`
+	MinRating         *float64 `json:"minRating"`
+	ISBN              string   `json:"isbn"`
+	Languages         []string `json:"languages"`
+	FirstInSeriesOnly bool     `json:"firstInSeriesOnly"`
+	ExcludeRead       bool     `json:"excludeRead"`
+	Sort              string   `json:"sort"`
+	Limit             *int64   `json:"limit"`
+	Offset            *int64   `json:"offset"`
+	Facets            []string `json:"facets"`
+}
+
+// GraphQLRequest is the JSON body of a GraphQL request
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Range is an inclusive range of numbers, a missing bound leaves it open
+type Range struct {
+	Min *int64 `json:"min"`
+	Max *int64 `json:"max"`
+}
+
+func (sr BookSearchRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Authors, validation.By(validateIDs)),
+		validation.Field(&reqCopy.Genres, validation.By(validateIDs)),
+		validation.Field(&reqCopy.GenreMatch, genreMatchRules...),
+		validation.Field(&reqCopy.ExcludeAuthors, validation.By(validateIDs)),
+		validation.Field(&reqCopy.ExcludeGenres, validation.By(validateIDs)),
+		validation.Field(&reqCopy.Pages, validation.By(validateRange(MinPages, MaxPages))),
+		validation.Field(&reqCopy.Years, validation.By(validateRange(MinYear, MaxYear))),
+		validation.Field(&reqCopy.MinRating, validation.Min(float64(MinRating)), validation.Max(float64(MaxRating))),
+		validation.Field(&reqCopy.ISBN, isbnRules...),
+		validation.Field(&reqCopy.Languages, validation.By(validateLanguages)),
+		validation.Field(&reqCopy.Sort, rankerRules...),
+		validation.Field(&reqCopy.Limit, validation.Min(int64(MinBooks)), validation.Max(int64(MaxBooks))),
+		validation.Field(&reqCopy.Offset, validation.Min(int64(0)), validation.Max(int64(MaxOffset))),
+		validation.Field(&reqCopy.Facets, validation.By(func(value interface{}) error {
+			return validateFacets(strings.Join(value.([]string), ","))
+		})),
+	)
+}
+
+// ToBookRequest returns the BookRequest holding the criteria of the search, the sort
+// being the ranker of the books
+func (sr BookSearchRequest) ToBookRequest() BookRequest {
+	req := BookRequest{
+		Authors:        joinIDs(sr.Authors),
+		Genres:         joinIDs(sr.Genres),
+		GenreMatch:     sr.GenreMatch,
+		ExcludeAuthors: joinIDs(sr.ExcludeAuthors),
+		ExcludeGenres:  joinIDs(sr.ExcludeGenres),
+		ISBN:           sr.ISBN,
+		Languages:      strings.Join(sr.Languages, ","),
+		Ranker:         sr.Sort,
+		Facets:         strings.Join(sr.Facets, ","),
+	}
+	if sr.Pages != nil {
+		req.MinPages, req.MaxPages = formatBound(sr.Pages.Min), formatBound(sr.Pages.Max)
+	}
+	if sr.Years != nil {
+		req.MinYear, req.MaxYear = formatBound(sr.Years.Min), formatBound(sr.Years.Max)
+	}
+	if sr.MinRating != nil {
+		req.MinRating = strconv.FormatFloat(*sr.MinRating, 'f', -1, 64)
+	}
+	if sr.FirstInSeriesOnly {
+		req.FirstInSeriesOnly = "true"
+	}
+	if sr.ExcludeRead {
+		req.ExcludeRead = "true"
+	}
+	req.Limit = formatBound(sr.Limit)
+	req.Offset = formatBound(sr.Offset)
+
+	return req
+}
+
+func joinIDs(ids []int64) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(values, ",")
+}
+
+func formatBound(bound *int64) string {
+	if bound == nil {
+		return ""
+	}
+	return strconv.FormatInt(*bound, 10)
+}
+
+func validateIDs(value interface{}) error {
+	for _, id := range value.([]int64) {
+		if id < 1 || id > math.MaxInt32 {
+			return fmt.Errorf("should be between 1 and %v", math.MaxInt32)
+		}
+	}
+	return nil
+}
+
+func validateLanguages(value interface{}) error {
+	languages := value.([]string)
+	if len(languages) == 0 {
+		return nil
+	}
+	if languagesRules[0].Validate(strings.Join(languages, ",")) != nil {
+		return errors.New("should be for example: [\"en\", \"pt-BR\"]")
+	}
+	return nil
+}
+
+func validateRange(minValue, maxValue int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		bounds := value.(*Range)
+		if bounds == nil {
+			return nil
+		}
+		for _, bound := range []*int64{bounds.Min, bounds.Max} {
+			if bound != nil && (*bound < minValue || *bound > maxValue) {
+				return fmt.Errorf("should be between %v and %v", minValue, maxValue)
+			}
+		}
+		if bounds.Min != nil && bounds.Max != nil && *bounds.Min > *bounds.Max {
+			return errors.New("min should not be greater than max")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	MaxBucketTitle     = 100
+	MaxBucketSortOrder = 100000
+)
+
+// titleRules are the validation rules of the title of a size or era
+var titleRules = []validation.Rule{
+	validation.Required.Error("is required"),
+	validation.RuneLength(1, MaxBucketTitle).Error("should be at most 100 characters"),
+}
+
+// sortOrderRules are the validation rules of the sort order of a size or era
+var sortOrderRules = []validation.Rule{
+	validation.Min(int64(0)).Error("should be between 0 and 100000"),
+	validation.Max(int64(MaxBucketSortOrder)).Error("should be between 0 and 100000"),
+}
+
+// inBounds checks that an optional bound of a size or era is between lower and upper
+func inBounds(lower, upper int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		bound := value.(*int64)
+		if bound != nil && (*bound < lower || *bound > upper) {
+			return fmt.Errorf("should be between %d and %d", lower, upper)
+		}
+		return nil
+	}
+}
+
+// notBelow checks that the upper bound of a size or era is not below its lower bound
+func notBelow(lower *int64, name string) validation.RuleFunc {
+	return func(value interface{}) error {
+		upper := value.(*int64)
+		if lower != nil && upper != nil && *upper < *lower {
+			return errors.New("should not be less than " + name)
+		}
+		return nil
+	}
+}
+
+// Overlaps tells whether two buckets bounded by the given values, both included, share
+// a value. A missing bound leaves a bucket open on its side, while a bucket without any
+// bound, such as "Any", is a catch-all that overlaps nothing.
+func Overlaps(minA, maxA, minB, maxB *int64) bool {
+	if (minA == nil && maxA == nil) || (minB == nil && maxB == nil) {
+		return false
+	}
+	return (minA == nil || maxB == nil || *minA <= *maxB) &&
+		(minB == nil || maxA == nil || *minB <= *maxA)
+}
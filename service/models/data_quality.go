@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// RuleBookPages and the following rules are the checks of the data of the catalog
+	RuleBookPages      = "book-pages"
+	RuleBookYear       = "book-year"
+	RuleBookRating     = "book-rating"
+	RuleOrphanAuthor   = "orphan-author"
+	RuleOrphanGenre    = "orphan-genre"
+	RuleDuplicateTitle = "duplicate-title"
+)
+
+// DataQualityRules are the rules of the data-quality checks, in the order they run
+var DataQualityRules = []string{RuleBookPages, RuleBookYear, RuleBookRating, RuleOrphanAuthor, RuleOrphanGenre, RuleDuplicateTitle}
+
+// DataQualityDescriptions tell what the data breaking each rule is
+var DataQualityDescriptions = map[string]string{
+	RuleBookPages:      "books whose pages are outside of the range of the API",
+	RuleBookYear:       "books whose publication year is outside of the range of the API",
+	RuleBookRating:     "books whose average rating is outside of the range of the ratings",
+	RuleOrphanAuthor:   "authors of no book",
+	RuleOrphanGenre:    "genres of no book, nor parent of another genre",
+	RuleDuplicateTitle: "books of the same authors and title, ignoring case and spacing",
+}
+
+// DataQualityRow is a row breaking a rule: the entity and its ID, along with what is wrong with it
+type DataQualityRow struct {
+	Entity string `json:"entity"`
+	ID     int64  `json:"id"`
+	Detail string `json:"detail"`
+}
+
+// DataQualityResult is the outcome of a rule: the number of rows breaking it, and the first of them
+type DataQualityResult struct {
+	Rule        string           `json:"rule"`
+	Description string           `json:"description"`
+	Passed      bool             `json:"passed"`
+	Violations  int64            `json:"violations"`
+	Rows        []DataQualityRow `json:"rows"`
+}
+
+// DataQualityReport is the outcome of every rule checked, passed when they all are
+type DataQualityReport struct {
+	Passed    bool                `json:"passed"`
+	CheckedAt time.Time           `json:"checkedAt"`
+	Results   []DataQualityResult `json:"results"`
+}
+
+// DataQualityRequest holds the comma-delimited rules to check instead of the configured ones
+type DataQualityRequest struct {
+	Rules string `json:"rules"`
+}
+
+func (dr DataQualityRequest) Validate() error {
+	reqCopy := dr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Rules, validation.By(validateDataQualityRules)),
+	)
+}
+
+// ParseDataQualityRules returns the rules of a comma-delimited list, nil when it is empty
+func ParseDataQualityRules(list string) []string {
+	var rules []string
+	for _, rule := range strings.Split(list, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func validateDataQualityRules(value interface{}) error {
+	for _, rule := range ParseDataQualityRules(value.(string)) {
+		if _, ok := DataQualityDescriptions[rule]; !ok {
+			return errors.New("should be a list of: " + strings.Join(DataQualityRules, ", "))
+		}
+	}
+	return nil
+}
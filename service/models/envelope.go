@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Envelope is the shape of the lists of the v2 API, their items along with the metadata
+// of the list and the links to navigate it
+type Envelope struct {
+	Data  interface{} `json:"data"`
+	Meta  Meta        `json:"meta"`
+	Links Links       `json:"links"`
+}
+
+// Meta describes the list of an envelope
+type Meta struct {
+	// Total is the number of items matching the request, whatever its limit and offset
+	Total    int64    `json:"total"`
+	Limit    int64    `json:"limit,omitempty"`
+	Offset   int64    `json:"offset"`
+	Facets   Facets   `json:"facets,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Links are the URLs of the list of an envelope and of its other pages, relative to the host
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+}
+
+// FieldsRequest holds the sparse fieldset of a list, the comma-delimited fields of its
+// items to return, empty for every field
+type FieldsRequest struct {
+	Fields string `json:"fields"`
+	// Known are the fields the items of the list have
+	Known []string `json:"-"`
+}
+
+// Names returns the distinct fields of the request, in order
+func (fr FieldsRequest) Names() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(fr.Fields, ",") {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (fr FieldsRequest) Validate() error {
+	reqCopy := fr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Fields, validation.By(fr.validateFields)),
+	)
+}
+
+func (fr FieldsRequest) validateFields(value interface{}) error {
+	list := value.(string)
+	if list == "" {
+		return nil
+	}
+	for _, name := range strings.Split(list, ",") {
+		known := false
+		for _, field := range fr.Known {
+			known = known || name == field
+		}
+		if !known {
+			return errors.New("should be a list of: " + strings.Join(fr.Known, ", "))
+		}
+	}
+	return nil
+}
@@ -1,8 +1,48 @@
 package models
 
+import validation "github.com/go-ozzo/ozzo-validation"
+
+// Era is a bucket of publication years offered to filter the books, both bounds included.
+// The retired eras are not active and only listed by the admin API.
 type Era struct {
-	ID      int64  `json:"id"`
-	Title   string `json:"title"`
-	MinYear *int64 `json:"minYear,omitempty"`
-	MaxYear *int64 `json:"maxYear,omitempty"`
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	MinYear   *int64 `json:"minYear,omitempty"`
+	MaxYear   *int64 `json:"maxYear,omitempty"`
+	SortOrder int64  `json:"sortOrder"`
+	Active    bool   `json:"active"`
+}
+
+// EraRequest holds the fields of an era to create or replace. An era is active unless
+// told otherwise, and AllowOverlap lets its years overlap the ones of another active era.
+type EraRequest struct {
+	Title        string `json:"title"`
+	MinYear      *int64 `json:"minYear"`
+	MaxYear      *int64 `json:"maxYear"`
+	SortOrder    int64  `json:"sortOrder"`
+	Active       *bool  `json:"active"`
+	AllowOverlap bool   `json:"allowOverlap"`
+}
+
+func (sr EraRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Title, titleRules...),
+		validation.Field(&reqCopy.MinYear, validation.By(inBounds(MinYear, MaxYear))),
+		validation.Field(&reqCopy.MaxYear, validation.By(inBounds(MinYear, MaxYear)), validation.By(notBelow(reqCopy.MinYear, "minYear"))),
+		validation.Field(&reqCopy.SortOrder, sortOrderRules...),
+	)
+}
+
+// Era returns the era with the given ID described by the request
+func (sr EraRequest) Era(id int64) Era {
+	return Era{
+		ID:        id,
+		Title:     sr.Title,
+		MinYear:   sr.MinYear,
+		MaxYear:   sr.MaxYear,
+		SortOrder: sr.SortOrder,
+		Active:    sr.Active == nil || *sr.Active,
+	}
 }
@@ -1,5 +1,84 @@
 package models
 
-type ResponseError struct {
-	Message string `json:"message"`
+import "fmt"
+
+// ErrorKind is the category of a domain error, it tells how the error is reported to clients
+type ErrorKind string
+
+const (
+	// KindNotFound is the kind of the errors about a missing resource
+	KindNotFound ErrorKind = "not-found"
+	// KindConflict is the kind of the errors about a change conflicting with the current state
+	KindConflict ErrorKind = "conflict"
+	// KindInvalid is the kind of the errors about a request that cannot be served as is
+	KindInvalid ErrorKind = "invalid-request"
+	// KindTimeout is the kind of the errors about work that did not finish in time
+	KindTimeout ErrorKind = "timeout"
+	// KindGone is the kind of the errors about a resource that was deleted
+	KindGone ErrorKind = "gone"
+)
+
+// Error is a domain error. Detail is safe to show to clients, Err is the cause, if any.
+// Every error of a kind matches the sentinel error of that kind with errors.Is.
+type Error struct {
+	Kind   ErrorKind
+	Detail string
+	Err    error
+}
+
+// ErrNotFound is returned when the requested resource does not exist
+var ErrNotFound = &Error{Kind: KindNotFound, Detail: "resource not found"}
+
+// ErrConflict is returned when a change conflicts with the current state of the resource
+var ErrConflict = &Error{Kind: KindConflict, Detail: "resource conflict"}
+
+// ErrGone is returned when the requested resource was deleted
+var ErrGone = &Error{Kind: KindGone, Detail: "resource deleted"}
+
+// ErrUnknownVariant is returned when an event refers to a variant of no running experiment
+var ErrUnknownVariant = &Error{Kind: KindInvalid, Detail: "unknown experiment variant"}
+
+// ErrTimeout is returned when the work for a request did not finish in time
+var ErrTimeout = &Error{Kind: KindTimeout, Detail: "request timed out"}
+
+// NewError returns an error of the given kind with a detail for clients
+func NewError(kind ErrorKind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Detail: fmt.Sprintf(format, args...)}
+}
+
+// WrapError returns an error of the given kind caused by err
+func WrapError(kind ErrorKind, err error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Detail: fmt.Sprintf(format, args...), Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Detail + ": " + e.Err.Error()
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is tells whether target is the sentinel error of the kind of e
+func (e *Error) Is(target error) bool {
+	for _, sentinel := range []*Error{ErrNotFound, ErrConflict, ErrTimeout, ErrGone} {
+		if target == sentinel {
+			return e.Kind == sentinel.Kind
+		}
+	}
+	return false
+}
+
+// Problem is an RFC 7807 problem details response. Instance is the ID of the request,
+// and Errors holds the messages of every invalid parameter or field, by name.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   map[string][]string `json:"errors,omitempty"`
 }
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// EventImpression records that a book was shown to the user
+	EventImpression = "impression"
+	// EventClick records that the user clicked on a book
+	EventClick = "click"
+
+	// TrafficBuckets is the number of buckets the subjects are hashed into,
+	// the traffic of a variant is the percentage of buckets assigned to it
+	TrafficBuckets = 100
+)
+
+// Experiment splits the traffic of /books between ranking strategies
+type Experiment struct {
+	Name     string    `json:"name"`
+	Variants []Variant `json:"variants"`
+}
+
+// Running tells whether the experiment enrolls any subject, the ones without traffic being paused
+func (x Experiment) Running() bool {
+	for _, variant := range x.Variants {
+		if variant.Traffic > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Variant is one arm of an experiment. Traffic is the percentage of the subjects
+// assigned to it, the subjects left over by the variants are not enrolled.
+type Variant struct {
+	Name    string `json:"name"`
+	Ranker  string `json:"ranker"`
+	Traffic int64  `json:"traffic"`
+}
+
+// Assignment is the variant of an experiment a subject was assigned to
+type Assignment struct {
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+	Ranker     string `json:"ranker"`
+}
+
+// Event is an impression or a click of a subject enrolled in an experiment
+type Event struct {
+	Type       string    `json:"type"`
+	Experiment string    `json:"experiment"`
+	Variant    string    `json:"variant"`
+	BookID     int64     `json:"bookId"`
+	Subject    string    `json:"-"`
+	CreatedAt  time.Time `json:"-"`
+}
+
+var (
+	nameRules = []validation.Rule{
+		validation.Required.Error("is required"),
+		validation.Length(1, 100).Error("should be at most 100 characters"),
+		validation.Match(regexp.MustCompile("^[A-Za-z0-9_.-]+$")).Error("should only contain letters, digits, '_', '.' and '-'"),
+	}
+)
+
+func (e Event) Validate() error {
+	eventCopy := e
+
+	return validation.ValidateStruct(&eventCopy,
+		validation.Field(&eventCopy.Type,
+			validation.Required.Error("is required"),
+			validation.In(EventImpression, EventClick).Error("should be one of: impression, click"),
+		),
+		validation.Field(&eventCopy.Experiment, nameRules...),
+		validation.Field(&eventCopy.Variant, nameRules...),
+		validation.Field(&eventCopy.BookID,
+			validation.Required.Error("is required"),
+			validation.Min(int64(1)).Error("should be a positive number"),
+		),
+	)
+}
+
+func (x Experiment) Validate() error {
+	experimentCopy := x
+
+	return validation.ValidateStruct(&experimentCopy,
+		validation.Field(&experimentCopy.Name, nameRules...),
+		validation.Field(&experimentCopy.Variants,
+			validation.Required.Error("is required"),
+			validation.By(validateTraffic),
+			validation.By(validateVariantNames),
+		),
+	)
+}
+
+func (v Variant) Validate() error {
+	variantCopy := v
+
+	return validation.ValidateStruct(&variantCopy,
+		validation.Field(&variantCopy.Name, nameRules...),
+		validation.Field(&variantCopy.Ranker,
+			validation.Required.Error("is required"),
+			validation.In(RankerRating, RankerWeighted, RankerRecency, RankerRandom).Error("should be one of: rating, weighted, recency, random"),
+		),
+		validation.Field(&variantCopy.Traffic,
+			validation.Min(int64(0)).Error("should be between 0 and 100"),
+			validation.Max(int64(TrafficBuckets)).Error("should be between 0 and 100"),
+		),
+	)
+}
+
+// validateTraffic checks the variants do not share more than the whole traffic
+func validateTraffic(value interface{}) error {
+	var total int64
+	for _, variant := range value.([]Variant) {
+		total += variant.Traffic
+	}
+	if total > TrafficBuckets {
+		return errors.New("traffic should add up to at most 100")
+	}
+	return nil
+}
+
+// validateVariantNames checks no two variants share a name, as the events name their variant
+func validateVariantNames(value interface{}) error {
+	names := map[string]bool{}
+	for _, variant := range value.([]Variant) {
+		if names[variant.Name] {
+			return fmt.Errorf("variant %q is duplicated", variant.Name)
+		}
+		names[variant.Name] = true
+	}
+	return nil
+}
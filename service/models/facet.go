@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// FacetAuthors counts the books of every author
+	FacetAuthors = "authors"
+	// FacetGenres counts the books of every genre
+	FacetGenres = "genres"
+	// FacetSizes counts the books of every size
+	FacetSizes = "sizes"
+	// FacetEras counts the books of every era
+	FacetEras = "eras"
+)
+
+// FacetNames are the names of every available facet
+var FacetNames = []string{FacetAuthors, FacetGenres, FacetSizes, FacetEras}
+
+// FacetCount is the number of books matching a value of a facet, the value being the
+// ID of an author, a genre, a size or an era
+type FacetCount struct {
+	ID    int64 `json:"id"`
+	Count int64 `json:"count"`
+}
+
+// Facets holds the counts of every requested facet by facet name
+type Facets map[string][]FacetCount
+
+// FacetedBooks is a page of books along with the facets of the request
+type FacetedBooks struct {
+	Books  []Book `json:"books"`
+	Facets Facets `json:"facets"`
+}
+
+// ParseFacets returns the distinct facet names of a comma-delimited list, in order
+func ParseFacets(list string) []string {
+	var facets []string
+	seen := make(map[string]bool)
+	for _, facet := range strings.Split(list, ",") {
+		if facet != "" && !seen[facet] {
+			seen[facet] = true
+			facets = append(facets, facet)
+		}
+	}
+	return facets
+}
+
+func validateFacets(value interface{}) error {
+	list := value.(string)
+	if list == "" {
+		return nil
+	}
+	for _, facet := range strings.Split(list, ",") {
+		known := false
+		for _, name := range FacetNames {
+			known = known || facet == name
+		}
+		if !known {
+			return errors.New("should be a list of: " + strings.Join(FacetNames, ", "))
+		}
+	}
+	return nil
+}
@@ -1,6 +1,37 @@
 package models
 
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
 type Genre struct {
 	ID    int64  `json:"id"`
 	Title string `json:"title"`
+	// ParentID is nil for the genres at the top of the hierarchy
+	ParentID *int64 `json:"parentId,omitempty"`
+	// Children are the direct subgenres of the genre, without their own children
+	Children []Genre `json:"children,omitempty"`
+}
+
+// GenreStats is a genre along with the figures of its books, the books of its
+// descendants included
+type GenreStats struct {
+	Genre
+	BookCount int64 `json:"bookCount"`
+	// AverageRating is the average of the ratings of the books weighted by their counts, their
+	// plain average when no user rated them, and 0 when there is no book
+	AverageRating float64 `json:"averageRating"`
+}
+
+// GenresRequest holds the parameters to get the genres
+type GenresRequest struct {
+	Stats string `json:"stats"`
+}
+
+func (gr GenresRequest) Validate() error {
+	reqCopy := gr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Stats, boolRules...),
+	)
 }
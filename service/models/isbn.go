@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// NormalizeISBN removes the hyphens and spaces of an ISBN and upper-cases its check digit
+func NormalizeISBN(isbn string) string {
+	isbn = strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	return strings.ToUpper(isbn)
+}
+
+// ValidISBN10 tells whether the normalized ISBN-10 has a valid check digit: the sum of the
+// digits weighted from 10 down to 1, where X stands for 10, is a multiple of 11
+func ValidISBN10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+	var sum int
+	for i, c := range isbn {
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case c == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// ValidISBN13 tells whether the normalized ISBN-13 has a valid check digit: the sum of the
+// digits alternately weighted by 1 and 3 is a multiple of 10
+func ValidISBN13(isbn string) bool {
+	if len(isbn) != 13 {
+		return false
+	}
+	var sum int
+	for i, c := range isbn {
+		if c < '0' || c > '9' {
+			return false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+	return sum%10 == 0
+}
+
+// ISBN10To13 returns the ISBN-13 of a valid normalized ISBN-10, which is prefixed by 978
+func ISBN10To13(isbn string) string {
+	prefixed := "978" + isbn[:9]
+	var sum int
+	for i, c := range prefixed {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+	return prefixed + string(rune('0'+(10-sum%10)%10))
+}
+
+// ISBN13To10 returns the ISBN-10 of a valid normalized ISBN-13, ok is false for the
+// ISBN-13 that have no ISBN-10, i.e. the ones not prefixed by 978
+func ISBN13To10(isbn string) (isbn10 string, ok bool) {
+	if !strings.HasPrefix(isbn, "978") {
+		return "", false
+	}
+	body := isbn[3:12]
+	var sum int
+	for i, c := range body {
+		sum += int(c-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X", true
+	}
+	return body + string(rune('0'+check)), true
+}
+
+// validateISBN checks the check digit of an ISBN-10 or ISBN-13, with or without hyphens
+func validateISBN(value interface{}) error {
+	isbn := NormalizeISBN(value.(string))
+	if isbn == "" || ValidISBN10(isbn) || ValidISBN13(isbn) {
+		return nil
+	}
+	return errors.New("should be a valid ISBN-10 or ISBN-13")
+}
@@ -0,0 +1,35 @@
+package models
+
+const (
+	// ReasonCollaborative recommends a book liked by the users who liked the same books as the user
+	ReasonCollaborative = "collaborative"
+	// ReasonPreferences recommends a book sharing the genre or the author of books the user liked
+	ReasonPreferences = "preferences"
+	// ReasonPopular recommends a well rated book to a user whose tastes are unknown
+	ReasonPopular = "popular"
+
+	// LikedRating is the minimum rating of a book for the user to be considered to like it
+	LikedRating = 3.5
+)
+
+// UserRating is the rating a user gave to a book
+type UserRating struct {
+	UserID int64   `json:"userId"`
+	BookID int64   `json:"bookId"`
+	Rating float64 `json:"rating"`
+}
+
+// BookSimilarity is how similar the ratings of two books are, from -1 to 1
+type BookSimilarity struct {
+	BookID        int64   `json:"bookId"`
+	SimilarBookID int64   `json:"similarBookId"`
+	Score         float64 `json:"score"`
+	CoRaters      int64   `json:"coRaters"`
+}
+
+// Recommendation is a book recommended to a user along with why it was
+type Recommendation struct {
+	Book
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Series is a sequence of books meant to be read in order
+type Series struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BookCount   int64  `json:"bookCount"`
+}
+
+// BookSeries is the series a book belongs to, along with the position of the book in it
+type BookSeries struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Position int64  `json:"position"`
+}
+
+// SeriesBooksRequest holds the parameters to get the books of a series
+type SeriesBooksRequest struct {
+	ID string `json:"id"`
+}
+
+func (sr SeriesBooksRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.ID, idRules...),
+	)
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// ShelfWantToRead holds the books the user wants to read
+	ShelfWantToRead = "want-to-read"
+	// ShelfReading holds the books the user is reading
+	ShelfReading = "reading"
+	// ShelfRead holds the books the user has read
+	ShelfRead = "read"
+	// ShelfCustom is any shelf created by the user
+	ShelfCustom = "custom"
+
+	// ExportJSON exports a shelf as JSON
+	ExportJSON = "json"
+	// ExportCSV exports a shelf as CSV
+	ExportCSV = "csv"
+
+	MaxShelfName     = 100
+	MaxShelfPosition = 100000
+)
+
+// DefaultShelves are the built-in shelves every user has, by kind
+var DefaultShelves = map[string]string{
+	ShelfWantToRead: "Want to read",
+	ShelfReading:    "Reading",
+	ShelfRead:       "Read",
+}
+
+// Shelf is a named and ordered list of books of a user
+type Shelf struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Kind       string       `json:"kind"`
+	BookCount  int64        `json:"bookCount"`
+	ShareToken *string      `json:"shareToken,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+	Books      []ShelfEntry `json:"books,omitempty"`
+}
+
+// ShelfEntry is a book on a shelf
+type ShelfEntry struct {
+	Book
+	Position int64     `json:"position"`
+	AddedAt  time.Time `json:"addedAt"`
+}
+
+// ShelfShare is the public link of a shared shelf
+type ShelfShare struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// ShelfRequest holds the editable fields of a shelf
+type ShelfRequest struct {
+	Name string `json:"name"`
+}
+
+// ShelfEntryRequest holds the book to put on a shelf and where. A zero position
+// appends the book at the end of the shelf.
+type ShelfEntryRequest struct {
+	BookID   int64 `json:"bookId"`
+	Position int64 `json:"position"`
+}
+
+func (sr ShelfRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Name,
+			validation.Required.Error("is required"),
+			validation.RuneLength(1, MaxShelfName).Error("should be at most 100 characters"),
+		),
+	)
+}
+
+func (er ShelfEntryRequest) Validate() error {
+	reqCopy := er
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.BookID,
+			validation.Required.Error("is required"),
+			validation.Min(int64(1)).Error("should be a positive number"),
+		),
+		validation.Field(&reqCopy.Position,
+			validation.Min(int64(0)).Error("should be between 1 and 100000"),
+			validation.Max(int64(MaxShelfPosition)).Error("should be between 1 and 100000"),
+		),
+	)
+}
@@ -1,8 +1,48 @@
 package models
 
+import validation "github.com/go-ozzo/ozzo-validation"
+
+// Size is a bucket of page counts offered to filter the books, both bounds included.
+// The retired sizes are not active and only listed by the admin API.
 type Size struct {
-	ID       int64  `json:"id"`
-	Title    string `json:"title"`
-	MinPages *int64 `json:"minPages,omitempty"`
-	MaxPages *int64 `json:"maxPages,omitempty"`
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	MinPages  *int64 `json:"minPages,omitempty"`
+	MaxPages  *int64 `json:"maxPages,omitempty"`
+	SortOrder int64  `json:"sortOrder"`
+	Active    bool   `json:"active"`
+}
+
+// SizeRequest holds the fields of a size to create or replace. A size is active unless
+// told otherwise, and AllowOverlap lets its pages overlap the ones of another active size.
+type SizeRequest struct {
+	Title        string `json:"title"`
+	MinPages     *int64 `json:"minPages"`
+	MaxPages     *int64 `json:"maxPages"`
+	SortOrder    int64  `json:"sortOrder"`
+	Active       *bool  `json:"active"`
+	AllowOverlap bool   `json:"allowOverlap"`
+}
+
+func (sr SizeRequest) Validate() error {
+	reqCopy := sr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Title, titleRules...),
+		validation.Field(&reqCopy.MinPages, validation.By(inBounds(MinPages, MaxPages))),
+		validation.Field(&reqCopy.MaxPages, validation.By(inBounds(MinPages, MaxPages)), validation.By(notBelow(reqCopy.MinPages, "minPages"))),
+		validation.Field(&reqCopy.SortOrder, sortOrderRules...),
+	)
+}
+
+// Size returns the size with the given ID described by the request
+func (sr SizeRequest) Size(id int64) Size {
+	return Size{
+		ID:        id,
+		Title:     sr.Title,
+		MinPages:  sr.MinPages,
+		MaxPages:  sr.MaxPages,
+		SortOrder: sr.SortOrder,
+		Active:    sr.Active == nil || *sr.Active,
+	}
 }
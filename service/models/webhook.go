@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	// ChangeBookCreated and the following types are the catalog changes a subscription
+	// can be notified of
+	ChangeBookCreated   = "book.created"
+	ChangeBookUpdated   = "book.updated"
+	ChangeBookDeleted   = "book.deleted"
+	ChangeBookRestored  = "book.restored"
+	ChangeAuthorCreated = "author.created"
+	ChangeAuthorUpdated = "author.updated"
+	ChangeAuthorDeleted = "author.deleted"
+	ChangeRatingCreated = "rating.created"
+	ChangeRatingUpdated = "rating.updated"
+	ChangeRatingDeleted = "rating.deleted"
+	// ChangePing is only sent to the subscription it is asked for, to check its receiver
+	ChangePing = "ping"
+
+	// DeliveryPending is the status of the deliveries waiting for their next attempt
+	DeliveryPending = "pending"
+	// DeliveryDelivered is the status of the deliveries accepted by their receiver
+	DeliveryDelivered = "delivered"
+	// DeliveryDead is the status of the deliveries whose attempts ran out
+	DeliveryDead = "dead"
+
+	MinWebhookSecret = 16
+	MaxWebhookSecret = 200
+	MaxWebhookURL    = 2000
+	// DefaultDeliveries is the number of deliveries listed when no limit is given
+	DefaultDeliveries = 50
+)
+
+// ChangeTypes are the types of every catalog change a subscription can be notified of
+var ChangeTypes = []string{
+	ChangeBookCreated, ChangeBookUpdated, ChangeBookDeleted, ChangeBookRestored,
+	ChangeAuthorCreated, ChangeAuthorUpdated, ChangeAuthorDeleted,
+	ChangeRatingCreated, ChangeRatingUpdated, ChangeRatingDeleted,
+}
+
+// WebhookSubscription is a partner system notified of the catalog changes of the given
+// types. The secret signs the deliveries and is never returned.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"eventTypes"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// WebhookRequest holds the fields of a subscription to create or replace, which is
+// active unless told otherwise
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+	Active     *bool    `json:"active"`
+}
+
+// ChangeEvent is a change of the catalog, written to the outbox along with the change
+// and delivered to the matching subscriptions afterwards
+type ChangeEvent struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	EntityID  int64           `json:"entityId"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// WebhookDelivery is the delivery of an event to a subscription along with the log of
+// its attempts, latest first
+type WebhookDelivery struct {
+	ID             int64            `json:"id"`
+	SubscriptionID int64            `json:"subscriptionId"`
+	EventID        int64            `json:"eventId"`
+	EventType      string           `json:"eventType"`
+	Status         string           `json:"status"`
+	Attempts       int64            `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *time.Time       `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	Log            []WebhookAttempt `json:"log"`
+}
+
+// WebhookAttempt is an attempt of a delivery. StatusCode is the status answered by the
+// receiver, if it answered, and Error tells why the attempt failed.
+type WebhookAttempt struct {
+	Attempt     int64     `json:"attempt"`
+	AttemptedAt time.Time `json:"attemptedAt"`
+	StatusCode  *int64    `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"durationMs"`
+}
+
+// DueDelivery is a delivery claimed by the dispatcher, along with its subscription and event
+type DueDelivery struct {
+	Delivery     WebhookDelivery
+	Subscription WebhookSubscription
+	Event        ChangeEvent
+}
+
+// DeliveriesRequest holds the parameters to list the deliveries of a subscription
+type DeliveriesRequest struct {
+	Status string `json:"status"`
+	Limit  string `json:"limit"`
+}
+
+var (
+	webhookURLRules = []validation.Rule{
+		validation.Required.Error("is required"),
+		validation.RuneLength(1, MaxWebhookURL).Error("should be at most 2000 characters"),
+		is.URL.Error("should be a URL"),
+		validation.Match(regexp.MustCompile("^https?://")).Error("should be an http or https URL"),
+	}
+	webhookSecretRules = []validation.Rule{
+		validation.Required.Error("is required"),
+		validation.RuneLength(MinWebhookSecret, MaxWebhookSecret).Error("should be between 16 and 200 characters"),
+	}
+	deliveryStatusRules = []validation.Rule{
+		validation.In(DeliveryPending, DeliveryDelivered, DeliveryDead).Error("should be one of: pending, delivered, dead"),
+	}
+)
+
+func (wr WebhookRequest) Validate() error {
+	reqCopy := wr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.URL, webhookURLRules...),
+		validation.Field(&reqCopy.Secret, webhookSecretRules...),
+		validation.Field(&reqCopy.EventTypes,
+			validation.Required.Error("is required"),
+			validation.By(validateChangeTypes),
+		),
+	)
+}
+
+func (dr DeliveriesRequest) Validate() error {
+	reqCopy := dr
+
+	return validation.ValidateStruct(&reqCopy,
+		validation.Field(&reqCopy.Status, deliveryStatusRules...),
+		validation.Field(&reqCopy.Limit, limitRules...),
+	)
+}
+
+// Subscription returns the subscription with the given ID described by the request
+func (wr WebhookRequest) Subscription(id int64) WebhookSubscription {
+	return WebhookSubscription{
+		ID:         id,
+		URL:        wr.URL,
+		Secret:     wr.Secret,
+		EventTypes: wr.EventTypes,
+		Active:     wr.Active == nil || *wr.Active,
+	}
+}
+
+func validateChangeTypes(value interface{}) error {
+	types := value.([]string)
+	seen := make(map[string]bool, len(types))
+	for _, eventType := range types {
+		known := false
+		for _, changeType := range ChangeTypes {
+			known = known || eventType == changeType
+		}
+		if !known {
+			return fmt.Errorf("should be a list of: %s", strings.Join(ChangeTypes, ", "))
+		}
+		if seen[eventType] {
+			return errors.New("should not repeat a type")
+		}
+		seen[eventType] = true
+	}
+	return nil
+}
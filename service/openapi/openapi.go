@@ -0,0 +1,73 @@
+// Package openapi holds the OpenAPI specification of the REST API, embedded in the binary
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+var (
+	// Spec is the OpenAPI specification of the REST API, as written
+	//go:embed open-api.yaml
+	Spec []byte
+
+	//go:embed docs.html
+	docsPage string
+)
+
+// Load parses the specification and checks that it is valid
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(Spec)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	return doc, nil
+}
+
+// NewRouter returns the router finding the operations of the requests. It matches the
+// paths under the path of the server of the specification, whatever the host.
+func NewRouter(doc *openapi3.T) (routers.Router, error) {
+	basePath := ""
+	if len(doc.Servers) > 0 {
+		serverURL, err := url.Parse(doc.Servers[0].URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid openapi server: %w", err)
+		}
+		basePath = serverURL.Path
+	}
+
+	routed := *doc
+	routed.Servers = openapi3.Servers{{URL: basePath}}
+	return gorillamux.NewRouter(&routed)
+}
+
+// Docs returns the documentation page of the specification. The page holds the
+// specification and renders it by itself, without loading any other asset.
+func Docs(doc *openapi3.T) ([]byte, error) {
+	content, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := template.New("docs").Parse(docsPage)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = page.Execute(&buf, map[string]interface{}{
+		"Title": doc.Info.Title,
+		"Spec":  template.JS(content),
+	})
+	return buf.Bytes(), err
+}
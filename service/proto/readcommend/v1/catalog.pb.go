@@ -0,0 +1,1442 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: readcommend/v1/catalog.proto
+
+package readcommendv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GenreMatch tells whether books need one of the genres or all of them
+type GenreMatch int32
+
+const (
+	GenreMatch_GENRE_MATCH_UNSPECIFIED GenreMatch = 0
+	GenreMatch_GENRE_MATCH_ANY         GenreMatch = 1
+	GenreMatch_GENRE_MATCH_ALL         GenreMatch = 2
+)
+
+// Enum value maps for GenreMatch.
+var (
+	GenreMatch_name = map[int32]string{
+		0: "GENRE_MATCH_UNSPECIFIED",
+		1: "GENRE_MATCH_ANY",
+		2: "GENRE_MATCH_ALL",
+	}
+	GenreMatch_value = map[string]int32{
+		"GENRE_MATCH_UNSPECIFIED": 0,
+		"GENRE_MATCH_ANY":         1,
+		"GENRE_MATCH_ALL":         2,
+	}
+)
+
+func (x GenreMatch) Enum() *GenreMatch {
+	p := new(GenreMatch)
+	*p = x
+	return p
+}
+
+func (x GenreMatch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GenreMatch) Descriptor() protoreflect.EnumDescriptor {
+	return file_readcommend_v1_catalog_proto_enumTypes[0].Descriptor()
+}
+
+func (GenreMatch) Type() protoreflect.EnumType {
+	return &file_readcommend_v1_catalog_proto_enumTypes[0]
+}
+
+func (x GenreMatch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GenreMatch.Descriptor instead.
+func (GenreMatch) EnumDescriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+// Ranker orders the books, the configured default one applies when unspecified
+type Ranker int32
+
+const (
+	Ranker_RANKER_UNSPECIFIED Ranker = 0
+	Ranker_RANKER_RATING      Ranker = 1
+	Ranker_RANKER_WEIGHTED    Ranker = 2
+	Ranker_RANKER_RECENCY     Ranker = 3
+	Ranker_RANKER_RANDOM      Ranker = 4
+)
+
+// Enum value maps for Ranker.
+var (
+	Ranker_name = map[int32]string{
+		0: "RANKER_UNSPECIFIED",
+		1: "RANKER_RATING",
+		2: "RANKER_WEIGHTED",
+		3: "RANKER_RECENCY",
+		4: "RANKER_RANDOM",
+	}
+	Ranker_value = map[string]int32{
+		"RANKER_UNSPECIFIED": 0,
+		"RANKER_RATING":      1,
+		"RANKER_WEIGHTED":    2,
+		"RANKER_RECENCY":     3,
+		"RANKER_RANDOM":      4,
+	}
+)
+
+func (x Ranker) Enum() *Ranker {
+	p := new(Ranker)
+	*p = x
+	return p
+}
+
+func (x Ranker) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Ranker) Descriptor() protoreflect.EnumDescriptor {
+	return file_readcommend_v1_catalog_proto_enumTypes[1].Descriptor()
+}
+
+func (Ranker) Type() protoreflect.EnumType {
+	return &file_readcommend_v1_catalog_proto_enumTypes[1]
+}
+
+func (x Ranker) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Ranker.Descriptor instead.
+func (Ranker) EnumDescriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+// Range is an inclusive range, a missing bound leaves it open
+type Range struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           *int64                 `protobuf:"varint,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max           *int64                 `protobuf:"varint,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Range) GetMin() int64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *Range) GetMax() int64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+// ListBooksRequest holds the criteria of POST /books/search. exclude_read needs the
+// x-user-id metadata of the caller.
+type ListBooksRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Authors           []int64                `protobuf:"varint,1,rep,packed,name=authors,proto3" json:"authors,omitempty"`
+	Genres            []int64                `protobuf:"varint,2,rep,packed,name=genres,proto3" json:"genres,omitempty"`
+	GenreMatch        GenreMatch             `protobuf:"varint,3,opt,name=genre_match,json=genreMatch,proto3,enum=readcommend.v1.GenreMatch" json:"genre_match,omitempty"`
+	ExcludeAuthors    []int64                `protobuf:"varint,4,rep,packed,name=exclude_authors,json=excludeAuthors,proto3" json:"exclude_authors,omitempty"`
+	ExcludeGenres     []int64                `protobuf:"varint,5,rep,packed,name=exclude_genres,json=excludeGenres,proto3" json:"exclude_genres,omitempty"`
+	Pages             *Range                 `protobuf:"bytes,6,opt,name=pages,proto3" json:"pages,omitempty"`
+	Years             *Range                 `protobuf:"bytes,7,opt,name=years,proto3" json:"years,omitempty"`
+	MinRating         *float64               `protobuf:"fixed64,8,opt,name=min_rating,json=minRating,proto3,oneof" json:"min_rating,omitempty"`
+	Isbn              string                 `protobuf:"bytes,9,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Languages         []string               `protobuf:"bytes,10,rep,name=languages,proto3" json:"languages,omitempty"`
+	FirstInSeriesOnly bool                   `protobuf:"varint,11,opt,name=first_in_series_only,json=firstInSeriesOnly,proto3" json:"first_in_series_only,omitempty"`
+	ExcludeRead       bool                   `protobuf:"varint,12,opt,name=exclude_read,json=excludeRead,proto3" json:"exclude_read,omitempty"`
+	Sort              Ranker                 `protobuf:"varint,13,opt,name=sort,proto3,enum=readcommend.v1.Ranker" json:"sort,omitempty"`
+	Limit             *int64                 `protobuf:"varint,14,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	Offset            *int64                 `protobuf:"varint,15,opt,name=offset,proto3,oneof" json:"offset,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksRequest) ProtoMessage() {}
+
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListBooksRequest) GetAuthors() []int64 {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetGenres() []int64 {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetGenreMatch() GenreMatch {
+	if x != nil {
+		return x.GenreMatch
+	}
+	return GenreMatch_GENRE_MATCH_UNSPECIFIED
+}
+
+func (x *ListBooksRequest) GetExcludeAuthors() []int64 {
+	if x != nil {
+		return x.ExcludeAuthors
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetExcludeGenres() []int64 {
+	if x != nil {
+		return x.ExcludeGenres
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetPages() *Range {
+	if x != nil {
+		return x.Pages
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetYears() *Range {
+	if x != nil {
+		return x.Years
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetMinRating() float64 {
+	if x != nil && x.MinRating != nil {
+		return *x.MinRating
+	}
+	return 0
+}
+
+func (x *ListBooksRequest) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *ListBooksRequest) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *ListBooksRequest) GetFirstInSeriesOnly() bool {
+	if x != nil {
+		return x.FirstInSeriesOnly
+	}
+	return false
+}
+
+func (x *ListBooksRequest) GetExcludeRead() bool {
+	if x != nil {
+		return x.ExcludeRead
+	}
+	return false
+}
+
+func (x *ListBooksRequest) GetSort() Ranker {
+	if x != nil {
+		return x.Sort
+	}
+	return Ranker_RANKER_UNSPECIFIED
+}
+
+func (x *ListBooksRequest) GetLimit() int64 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListBooksRequest) GetOffset() int64 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+type GetBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRequest) Reset() {
+	*x = GetBookRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRequest) ProtoMessage() {}
+
+func (x *GetBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRequest.ProtoReflect.Descriptor instead.
+func (*GetBookRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetBookRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Book struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	YearPublished  int64                  `protobuf:"varint,3,opt,name=year_published,json=yearPublished,proto3" json:"year_published,omitempty"`
+	Rating         float64                `protobuf:"fixed64,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	RatingCount    int64                  `protobuf:"varint,5,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	WeightedRating float64                `protobuf:"fixed64,6,opt,name=weighted_rating,json=weightedRating,proto3" json:"weighted_rating,omitempty"`
+	Pages          int64                  `protobuf:"varint,7,opt,name=pages,proto3" json:"pages,omitempty"`
+	Authors        []*BookAuthor          `protobuf:"bytes,8,rep,name=authors,proto3" json:"authors,omitempty"`
+	Genres         []*Genre               `protobuf:"bytes,9,rep,name=genres,proto3" json:"genres,omitempty"`
+	Series         *BookSeries            `protobuf:"bytes,10,opt,name=series,proto3" json:"series,omitempty"`
+	Isbn10         string                 `protobuf:"bytes,11,opt,name=isbn10,proto3" json:"isbn10,omitempty"`
+	Isbn13         string                 `protobuf:"bytes,12,opt,name=isbn13,proto3" json:"isbn13,omitempty"`
+	Language       string                 `protobuf:"bytes,13,opt,name=language,proto3" json:"language,omitempty"`
+	Publisher      string                 `protobuf:"bytes,14,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Description    string                 `protobuf:"bytes,15,opt,name=description,proto3" json:"description,omitempty"`
+	CoverUrl       string                 `protobuf:"bytes,16,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Book) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Book) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Book) GetYearPublished() int64 {
+	if x != nil {
+		return x.YearPublished
+	}
+	return 0
+}
+
+func (x *Book) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Book) GetRatingCount() int64 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Book) GetWeightedRating() float64 {
+	if x != nil {
+		return x.WeightedRating
+	}
+	return 0
+}
+
+func (x *Book) GetPages() int64 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+func (x *Book) GetAuthors() []*BookAuthor {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *Book) GetGenres() []*Genre {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *Book) GetSeries() *BookSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *Book) GetIsbn10() string {
+	if x != nil {
+		return x.Isbn10
+	}
+	return ""
+}
+
+func (x *Book) GetIsbn13() string {
+	if x != nil {
+		return x.Isbn13
+	}
+	return ""
+}
+
+func (x *Book) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Book) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *Book) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Book) GetCoverUrl() string {
+	if x != nil {
+		return x.CoverUrl
+	}
+	return ""
+}
+
+// BookAuthor is an author of a book along with their role
+type BookAuthor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookAuthor) Reset() {
+	*x = BookAuthor{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookAuthor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookAuthor) ProtoMessage() {}
+
+func (x *BookAuthor) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookAuthor.ProtoReflect.Descriptor instead.
+func (*BookAuthor) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BookAuthor) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *BookAuthor) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type BookSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Position      int64                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookSeries) Reset() {
+	*x = BookSeries{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookSeries) ProtoMessage() {}
+
+func (x *BookSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookSeries.ProtoReflect.Descriptor instead.
+func (*BookSeries) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BookSeries) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BookSeries) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BookSeries) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type Author struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName     string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Author) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Author) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Author) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+type Genre struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Genre) Reset() {
+	*x = Genre{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Genre) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Genre) ProtoMessage() {}
+
+func (x *Genre) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Genre.ProtoReflect.Descriptor instead.
+func (*Genre) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Genre) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Genre) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type Size struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	MinPages      *int64                 `protobuf:"varint,3,opt,name=min_pages,json=minPages,proto3,oneof" json:"min_pages,omitempty"`
+	MaxPages      *int64                 `protobuf:"varint,4,opt,name=max_pages,json=maxPages,proto3,oneof" json:"max_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Size) Reset() {
+	*x = Size{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Size) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Size) ProtoMessage() {}
+
+func (x *Size) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Size.ProtoReflect.Descriptor instead.
+func (*Size) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Size) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Size) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Size) GetMinPages() int64 {
+	if x != nil && x.MinPages != nil {
+		return *x.MinPages
+	}
+	return 0
+}
+
+func (x *Size) GetMaxPages() int64 {
+	if x != nil && x.MaxPages != nil {
+		return *x.MaxPages
+	}
+	return 0
+}
+
+type Era struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	MinYear       *int64                 `protobuf:"varint,3,opt,name=min_year,json=minYear,proto3,oneof" json:"min_year,omitempty"`
+	MaxYear       *int64                 `protobuf:"varint,4,opt,name=max_year,json=maxYear,proto3,oneof" json:"max_year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Era) Reset() {
+	*x = Era{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Era) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Era) ProtoMessage() {}
+
+func (x *Era) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Era.ProtoReflect.Descriptor instead.
+func (*Era) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Era) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Era) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Era) GetMinYear() int64 {
+	if x != nil && x.MinYear != nil {
+		return *x.MinYear
+	}
+	return 0
+}
+
+func (x *Era) GetMaxYear() int64 {
+	if x != nil && x.MaxYear != nil {
+		return *x.MaxYear
+	}
+	return 0
+}
+
+type ListAuthorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsRequest) Reset() {
+	*x = ListAuthorsRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsRequest) ProtoMessage() {}
+
+func (x *ListAuthorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorsRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+type ListAuthorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorsResponse) Reset() {
+	*x = ListAuthorsResponse{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorsResponse) ProtoMessage() {}
+
+func (x *ListAuthorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthorsResponse) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAuthorsResponse) GetAuthors() []*Author {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+type ListGenresRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGenresRequest) Reset() {
+	*x = ListGenresRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGenresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGenresRequest) ProtoMessage() {}
+
+func (x *ListGenresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGenresRequest.ProtoReflect.Descriptor instead.
+func (*ListGenresRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+type ListGenresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Genres        []*Genre               `protobuf:"bytes,1,rep,name=genres,proto3" json:"genres,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGenresResponse) Reset() {
+	*x = ListGenresResponse{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGenresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGenresResponse) ProtoMessage() {}
+
+func (x *ListGenresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGenresResponse.ProtoReflect.Descriptor instead.
+func (*ListGenresResponse) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListGenresResponse) GetGenres() []*Genre {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+type ListSizesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSizesRequest) Reset() {
+	*x = ListSizesRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSizesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSizesRequest) ProtoMessage() {}
+
+func (x *ListSizesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSizesRequest.ProtoReflect.Descriptor instead.
+func (*ListSizesRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+type ListSizesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sizes         []*Size                `protobuf:"bytes,1,rep,name=sizes,proto3" json:"sizes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSizesResponse) Reset() {
+	*x = ListSizesResponse{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSizesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSizesResponse) ProtoMessage() {}
+
+func (x *ListSizesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSizesResponse.ProtoReflect.Descriptor instead.
+func (*ListSizesResponse) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListSizesResponse) GetSizes() []*Size {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+type ListErasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListErasRequest) Reset() {
+	*x = ListErasRequest{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListErasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListErasRequest) ProtoMessage() {}
+
+func (x *ListErasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListErasRequest.ProtoReflect.Descriptor instead.
+func (*ListErasRequest) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+type ListErasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Eras          []*Era                 `protobuf:"bytes,1,rep,name=eras,proto3" json:"eras,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListErasResponse) Reset() {
+	*x = ListErasResponse{}
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListErasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListErasResponse) ProtoMessage() {}
+
+func (x *ListErasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_readcommend_v1_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListErasResponse.ProtoReflect.Descriptor instead.
+func (*ListErasResponse) Descriptor() ([]byte, []int) {
+	return file_readcommend_v1_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListErasResponse) GetEras() []*Era {
+	if x != nil {
+		return x.Eras
+	}
+	return nil
+}
+
+var File_readcommend_v1_catalog_proto protoreflect.FileDescriptor
+
+const file_readcommend_v1_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\x1creadcommend/v1/catalog.proto\x12\x0ereadcommend.v1\"E\n" +
+	"\x05Range\x12\x15\n" +
+	"\x03min\x18\x01 \x01(\x03H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x03H\x01R\x03max\x88\x01\x01B\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_max\"\xdd\x04\n" +
+	"\x10ListBooksRequest\x12\x18\n" +
+	"\aauthors\x18\x01 \x03(\x03R\aauthors\x12\x16\n" +
+	"\x06genres\x18\x02 \x03(\x03R\x06genres\x12;\n" +
+	"\vgenre_match\x18\x03 \x01(\x0e2\x1a.readcommend.v1.GenreMatchR\n" +
+	"genreMatch\x12'\n" +
+	"\x0fexclude_authors\x18\x04 \x03(\x03R\x0eexcludeAuthors\x12%\n" +
+	"\x0eexclude_genres\x18\x05 \x03(\x03R\rexcludeGenres\x12+\n" +
+	"\x05pages\x18\x06 \x01(\v2\x15.readcommend.v1.RangeR\x05pages\x12+\n" +
+	"\x05years\x18\a \x01(\v2\x15.readcommend.v1.RangeR\x05years\x12\"\n" +
+	"\n" +
+	"min_rating\x18\b \x01(\x01H\x00R\tminRating\x88\x01\x01\x12\x12\n" +
+	"\x04isbn\x18\t \x01(\tR\x04isbn\x12\x1c\n" +
+	"\tlanguages\x18\n" +
+	" \x03(\tR\tlanguages\x12/\n" +
+	"\x14first_in_series_only\x18\v \x01(\bR\x11firstInSeriesOnly\x12!\n" +
+	"\fexclude_read\x18\f \x01(\bR\vexcludeRead\x12*\n" +
+	"\x04sort\x18\r \x01(\x0e2\x16.readcommend.v1.RankerR\x04sort\x12\x19\n" +
+	"\x05limit\x18\x0e \x01(\x03H\x01R\x05limit\x88\x01\x01\x12\x1b\n" +
+	"\x06offset\x18\x0f \x01(\x03H\x02R\x06offset\x88\x01\x01B\r\n" +
+	"\v_min_ratingB\b\n" +
+	"\x06_limitB\t\n" +
+	"\a_offset\" \n" +
+	"\x0eGetBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x8f\x04\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\x0eyear_published\x18\x03 \x01(\x03R\ryearPublished\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x01R\x06rating\x12!\n" +
+	"\frating_count\x18\x05 \x01(\x03R\vratingCount\x12'\n" +
+	"\x0fweighted_rating\x18\x06 \x01(\x01R\x0eweightedRating\x12\x14\n" +
+	"\x05pages\x18\a \x01(\x03R\x05pages\x124\n" +
+	"\aauthors\x18\b \x03(\v2\x1a.readcommend.v1.BookAuthorR\aauthors\x12-\n" +
+	"\x06genres\x18\t \x03(\v2\x15.readcommend.v1.GenreR\x06genres\x122\n" +
+	"\x06series\x18\n" +
+	" \x01(\v2\x1a.readcommend.v1.BookSeriesR\x06series\x12\x16\n" +
+	"\x06isbn10\x18\v \x01(\tR\x06isbn10\x12\x16\n" +
+	"\x06isbn13\x18\f \x01(\tR\x06isbn13\x12\x1a\n" +
+	"\blanguage\x18\r \x01(\tR\blanguage\x12\x1c\n" +
+	"\tpublisher\x18\x0e \x01(\tR\tpublisher\x12 \n" +
+	"\vdescription\x18\x0f \x01(\tR\vdescription\x12\x1b\n" +
+	"\tcover_url\x18\x10 \x01(\tR\bcoverUrl\"P\n" +
+	"\n" +
+	"BookAuthor\x12.\n" +
+	"\x06author\x18\x01 \x01(\v2\x16.readcommend.v1.AuthorR\x06author\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"L\n" +
+	"\n" +
+	"BookSeries\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\x03R\bposition\"T\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\"-\n" +
+	"\x05Genre\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"\x8c\x01\n" +
+	"\x04Size\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\tmin_pages\x18\x03 \x01(\x03H\x00R\bminPages\x88\x01\x01\x12 \n" +
+	"\tmax_pages\x18\x04 \x01(\x03H\x01R\bmaxPages\x88\x01\x01B\f\n" +
+	"\n" +
+	"_min_pagesB\f\n" +
+	"\n" +
+	"_max_pages\"\x85\x01\n" +
+	"\x03Era\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1e\n" +
+	"\bmin_year\x18\x03 \x01(\x03H\x00R\aminYear\x88\x01\x01\x12\x1e\n" +
+	"\bmax_year\x18\x04 \x01(\x03H\x01R\amaxYear\x88\x01\x01B\v\n" +
+	"\t_min_yearB\v\n" +
+	"\t_max_year\"\x14\n" +
+	"\x12ListAuthorsRequest\"G\n" +
+	"\x13ListAuthorsResponse\x120\n" +
+	"\aauthors\x18\x01 \x03(\v2\x16.readcommend.v1.AuthorR\aauthors\"\x13\n" +
+	"\x11ListGenresRequest\"C\n" +
+	"\x12ListGenresResponse\x12-\n" +
+	"\x06genres\x18\x01 \x03(\v2\x15.readcommend.v1.GenreR\x06genres\"\x12\n" +
+	"\x10ListSizesRequest\"?\n" +
+	"\x11ListSizesResponse\x12*\n" +
+	"\x05sizes\x18\x01 \x03(\v2\x14.readcommend.v1.SizeR\x05sizes\"\x11\n" +
+	"\x0fListErasRequest\";\n" +
+	"\x10ListErasResponse\x12'\n" +
+	"\x04eras\x18\x01 \x03(\v2\x13.readcommend.v1.EraR\x04eras*S\n" +
+	"\n" +
+	"GenreMatch\x12\x1b\n" +
+	"\x17GENRE_MATCH_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fGENRE_MATCH_ANY\x10\x01\x12\x13\n" +
+	"\x0fGENRE_MATCH_ALL\x10\x02*o\n" +
+	"\x06Ranker\x12\x16\n" +
+	"\x12RANKER_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rRANKER_RATING\x10\x01\x12\x13\n" +
+	"\x0fRANKER_WEIGHTED\x10\x02\x12\x12\n" +
+	"\x0eRANKER_RECENCY\x10\x03\x12\x11\n" +
+	"\rRANKER_RANDOM\x10\x042\xe6\x03\n" +
+	"\x0eCatalogService\x12E\n" +
+	"\tListBooks\x12 .readcommend.v1.ListBooksRequest\x1a\x14.readcommend.v1.Book0\x01\x12?\n" +
+	"\aGetBook\x12\x1e.readcommend.v1.GetBookRequest\x1a\x14.readcommend.v1.Book\x12V\n" +
+	"\vListAuthors\x12\".readcommend.v1.ListAuthorsRequest\x1a#.readcommend.v1.ListAuthorsResponse\x12S\n" +
+	"\n" +
+	"ListGenres\x12!.readcommend.v1.ListGenresRequest\x1a\".readcommend.v1.ListGenresResponse\x12P\n" +
+	"\tListSizes\x12 .readcommend.v1.ListSizesRequest\x1a!.readcommend.v1.ListSizesResponse\x12M\n" +
+	"\bListEras\x12\x1f.readcommend.v1.ListErasRequest\x1a .readcommend.v1.ListErasResponseBLZJgithub.com/book-recommendations/service/proto/readcommend/v1;readcommendv1b\x06proto3"
+
+var (
+	file_readcommend_v1_catalog_proto_rawDescOnce sync.Once
+	file_readcommend_v1_catalog_proto_rawDescData []byte
+)
+
+func file_readcommend_v1_catalog_proto_rawDescGZIP() []byte {
+	file_readcommend_v1_catalog_proto_rawDescOnce.Do(func() {
+		file_readcommend_v1_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_readcommend_v1_catalog_proto_rawDesc), len(file_readcommend_v1_catalog_proto_rawDesc)))
+	})
+	return file_readcommend_v1_catalog_proto_rawDescData
+}
+
+var file_readcommend_v1_catalog_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_readcommend_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_readcommend_v1_catalog_proto_goTypes = []any{
+	(GenreMatch)(0),             // 0: readcommend.v1.GenreMatch
+	(Ranker)(0),                 // 1: readcommend.v1.Ranker
+	(*Range)(nil),               // 2: readcommend.v1.Range
+	(*ListBooksRequest)(nil),    // 3: readcommend.v1.ListBooksRequest
+	(*GetBookRequest)(nil),      // 4: readcommend.v1.GetBookRequest
+	(*Book)(nil),                // 5: readcommend.v1.Book
+	(*BookAuthor)(nil),          // 6: readcommend.v1.BookAuthor
+	(*BookSeries)(nil),          // 7: readcommend.v1.BookSeries
+	(*Author)(nil),              // 8: readcommend.v1.Author
+	(*Genre)(nil),               // 9: readcommend.v1.Genre
+	(*Size)(nil),                // 10: readcommend.v1.Size
+	(*Era)(nil),                 // 11: readcommend.v1.Era
+	(*ListAuthorsRequest)(nil),  // 12: readcommend.v1.ListAuthorsRequest
+	(*ListAuthorsResponse)(nil), // 13: readcommend.v1.ListAuthorsResponse
+	(*ListGenresRequest)(nil),   // 14: readcommend.v1.ListGenresRequest
+	(*ListGenresResponse)(nil),  // 15: readcommend.v1.ListGenresResponse
+	(*ListSizesRequest)(nil),    // 16: readcommend.v1.ListSizesRequest
+	(*ListSizesResponse)(nil),   // 17: readcommend.v1.ListSizesResponse
+	(*ListErasRequest)(nil),     // 18: readcommend.v1.ListErasRequest
+	(*ListErasResponse)(nil),    // 19: readcommend.v1.ListErasResponse
+}
+var file_readcommend_v1_catalog_proto_depIdxs = []int32{
+	0,  // 0: readcommend.v1.ListBooksRequest.genre_match:type_name -> readcommend.v1.GenreMatch
+	2,  // 1: readcommend.v1.ListBooksRequest.pages:type_name -> readcommend.v1.Range
+	2,  // 2: readcommend.v1.ListBooksRequest.years:type_name -> readcommend.v1.Range
+	1,  // 3: readcommend.v1.ListBooksRequest.sort:type_name -> readcommend.v1.Ranker
+	6,  // 4: readcommend.v1.Book.authors:type_name -> readcommend.v1.BookAuthor
+	9,  // 5: readcommend.v1.Book.genres:type_name -> readcommend.v1.Genre
+	7,  // 6: readcommend.v1.Book.series:type_name -> readcommend.v1.BookSeries
+	8,  // 7: readcommend.v1.BookAuthor.author:type_name -> readcommend.v1.Author
+	8,  // 8: readcommend.v1.ListAuthorsResponse.authors:type_name -> readcommend.v1.Author
+	9,  // 9: readcommend.v1.ListGenresResponse.genres:type_name -> readcommend.v1.Genre
+	10, // 10: readcommend.v1.ListSizesResponse.sizes:type_name -> readcommend.v1.Size
+	11, // 11: readcommend.v1.ListErasResponse.eras:type_name -> readcommend.v1.Era
+	3,  // 12: readcommend.v1.CatalogService.ListBooks:input_type -> readcommend.v1.ListBooksRequest
+	4,  // 13: readcommend.v1.CatalogService.GetBook:input_type -> readcommend.v1.GetBookRequest
+	12, // 14: readcommend.v1.CatalogService.ListAuthors:input_type -> readcommend.v1.ListAuthorsRequest
+	14, // 15: readcommend.v1.CatalogService.ListGenres:input_type -> readcommend.v1.ListGenresRequest
+	16, // 16: readcommend.v1.CatalogService.ListSizes:input_type -> readcommend.v1.ListSizesRequest
+	18, // 17: readcommend.v1.CatalogService.ListEras:input_type -> readcommend.v1.ListErasRequest
+	5,  // 18: readcommend.v1.CatalogService.ListBooks:output_type -> readcommend.v1.Book
+	5,  // 19: readcommend.v1.CatalogService.GetBook:output_type -> readcommend.v1.Book
+	13, // 20: readcommend.v1.CatalogService.ListAuthors:output_type -> readcommend.v1.ListAuthorsResponse
+	15, // 21: readcommend.v1.CatalogService.ListGenres:output_type -> readcommend.v1.ListGenresResponse
+	17, // 22: readcommend.v1.CatalogService.ListSizes:output_type -> readcommend.v1.ListSizesResponse
+	19, // 23: readcommend.v1.CatalogService.ListEras:output_type -> readcommend.v1.ListErasResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_readcommend_v1_catalog_proto_init() }
+func file_readcommend_v1_catalog_proto_init() {
+	if File_readcommend_v1_catalog_proto != nil {
+		return
+	}
+	file_readcommend_v1_catalog_proto_msgTypes[0].OneofWrappers = []any{}
+	file_readcommend_v1_catalog_proto_msgTypes[1].OneofWrappers = []any{}
+	file_readcommend_v1_catalog_proto_msgTypes[8].OneofWrappers = []any{}
+	file_readcommend_v1_catalog_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_readcommend_v1_catalog_proto_rawDesc), len(file_readcommend_v1_catalog_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_readcommend_v1_catalog_proto_goTypes,
+		DependencyIndexes: file_readcommend_v1_catalog_proto_depIdxs,
+		EnumInfos:         file_readcommend_v1_catalog_proto_enumTypes,
+		MessageInfos:      file_readcommend_v1_catalog_proto_msgTypes,
+	}.Build()
+	File_readcommend_v1_catalog_proto = out.File
+	file_readcommend_v1_catalog_proto_goTypes = nil
+	file_readcommend_v1_catalog_proto_depIdxs = nil
+}
@@ -0,0 +1,4 @@
+// Package readcommendv1 holds the gRPC API of the service, generated from catalog.proto
+package readcommendv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative readcommend/v1/catalog.proto
@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/book-recommendations/service/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fail returns the status matching a domain error, as the HTTP controllers write the
+// matching problem. The errors without a kind are internal errors, logged and hidden.
+func (s *CatalogServer) fail(ctx context.Context, err error) error {
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) && errors.Is(err, context.DeadlineExceeded) {
+		domainErr = models.ErrTimeout
+	}
+
+	if domainErr != nil {
+		switch domainErr.Kind {
+		case models.KindNotFound, models.KindGone:
+			return status.Error(codes.NotFound, domainErr.Detail)
+		case models.KindConflict:
+			return status.Error(codes.AlreadyExists, domainErr.Detail)
+		case models.KindInvalid:
+			return status.Error(codes.InvalidArgument, domainErr.Detail)
+		case models.KindTimeout:
+			return status.Error(codes.DeadlineExceeded, domainErr.Detail)
+		}
+	}
+
+	s.Logger.WithField("requestId", requestIDFrom(ctx)).WithError(err).Error("internal server error")
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type requestIDKey struct{}
+
+// unaryInterceptor gives every call a request ID and a timeout, and logs it as the HTTP
+// controllers log their requests
+func unaryInterceptor(logger *log.Entry, timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := prepare(ctx, logger, info.FullMethod, timeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
+// streamInterceptor does for the streaming calls what unaryInterceptor does for the unary ones
+func streamInterceptor(logger *log.Entry, timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := prepare(stream.Context(), logger, info.FullMethod, timeout)
+		defer cancel()
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// prepare returns the context of a call, holding its request ID, which is sent back in
+// the header metadata, and canceled after the timeout unless it is zero
+func prepare(ctx context.Context, logger *log.Entry, method string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	id := translators.ToRequestID(firstMetadata(ctx, translators.RequestIDHeader))
+	grpc.SetHeader(ctx, metadata.Pairs(translators.RequestIDHeader, id))
+	logger.WithField("method", method).WithField("requestId", id).Info("request")
+
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// contextStream is a server stream with the context prepared by the interceptor
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func requestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// userIDFrom returns the ID of the authenticated user from the x-user-id metadata, set and
+// signed in x-user-signature by the gateway as it does the headers of the HTTP requests.
+// ok is false when the metadata is missing or its signature is not the one of the gateway.
+func userIDFrom(ctx context.Context, secret string) (int64, bool) {
+	header := firstMetadata(ctx, translators.UserIDHeader)
+	if !translators.VerifyUserID(secret, header, firstMetadata(ctx, translators.UserSignatureHeader)) {
+		return 0, false
+	}
+	return translators.ParseUserID(header)
+}
+
+// firstMetadata returns the first value of the incoming metadata with the given key
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-recommendations/service/mediators"
+	pb "github.com/book-recommendations/service/proto/readcommend/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// CatalogServer implements the gRPC CatalogService over the mediators of the REST API
+type CatalogServer struct {
+	pb.UnimplementedCatalogServiceServer
+
+	Logger                *log.Entry
+	BookMediatorFactory   func() mediators.BookMediator
+	AuthorMediatorFactory func() mediators.AuthorMediator
+	GenreMediatorFactory  func() mediators.GenreMediator
+	SizeMediatorFactory   func() mediators.SizeMediator
+	EraMediatorFactory    func() mediators.EraMediator
+	// UserIDSecret is the secret the gateway signs the x-user-id metadata with
+	UserIDSecret string
+}
+
+// NewServer returns a gRPC server serving the catalog, along with the health checking
+// and reflection services. Every call gets a request ID and the timeout of the requests.
+func NewServer(logger *log.Entry, catalog *CatalogServer, timeout time.Duration) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptor(logger, timeout)),
+		grpc.ChainStreamInterceptor(streamInterceptor(logger, timeout)),
+	)
+	pb.RegisterCatalogServiceServer(server, catalog)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.CatalogService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+// ListBooks streams the books matching the criteria of the request
+func (s *CatalogServer) ListBooks(req *pb.ListBooksRequest, stream pb.CatalogService_ListBooksServer) error {
+	ctx := stream.Context()
+
+	searchReq := toBookSearchRequest(req)
+	if err := searchReq.Validate(); err != nil {
+		s.Logger.WithField("requestId", requestIDFrom(ctx)).WithError(err).Error("invalid request for list books")
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	bookReq := searchReq.ToBookRequest()
+	if req.ExcludeRead {
+		userID, ok := userIDFrom(ctx, s.UserIDSecret)
+		if !ok {
+			return status.Error(codes.Unauthenticated, "exclude_read needs the signed x-user-id metadata")
+		}
+		bookReq.UserID = userID
+	}
+
+	books, err := s.BookMediatorFactory().Get(ctx, bookReq)
+	if err != nil {
+		return s.fail(ctx, err)
+	}
+	for _, book := range books {
+		if err := stream.Send(toBook(book)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBook returns the book with the ID of the request
+func (s *CatalogServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.Book, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id: must be a positive number")
+	}
+
+	book, err := s.BookMediatorFactory().GetBook(ctx, req.Id)
+	if err != nil {
+		return nil, s.fail(ctx, err)
+	}
+	return toBook(book), nil
+}
+
+// ListAuthors returns every author
+func (s *CatalogServer) ListAuthors(ctx context.Context, req *pb.ListAuthorsRequest) (*pb.ListAuthorsResponse, error) {
+	authors, err := s.AuthorMediatorFactory().Get(ctx)
+	if err != nil {
+		return nil, s.fail(ctx, err)
+	}
+
+	resp := &pb.ListAuthorsResponse{Authors: make([]*pb.Author, 0, len(authors))}
+	for _, author := range authors {
+		resp.Authors = append(resp.Authors, toAuthor(author))
+	}
+	return resp, nil
+}
+
+// ListGenres returns every genre
+func (s *CatalogServer) ListGenres(ctx context.Context, req *pb.ListGenresRequest) (*pb.ListGenresResponse, error) {
+	genres, err := s.GenreMediatorFactory().Get(ctx)
+	if err != nil {
+		return nil, s.fail(ctx, err)
+	}
+
+	resp := &pb.ListGenresResponse{Genres: make([]*pb.Genre, 0, len(genres))}
+	for _, genre := range genres {
+		resp.Genres = append(resp.Genres, toGenre(genre))
+	}
+	return resp, nil
+}
+
+// ListSizes returns every size
+func (s *CatalogServer) ListSizes(ctx context.Context, req *pb.ListSizesRequest) (*pb.ListSizesResponse, error) {
+	sizes, err := s.SizeMediatorFactory().Get(ctx)
+	if err != nil {
+		return nil, s.fail(ctx, err)
+	}
+
+	resp := &pb.ListSizesResponse{Sizes: make([]*pb.Size, 0, len(sizes))}
+	for _, size := range sizes {
+		resp.Sizes = append(resp.Sizes, &pb.Size{Id: size.ID, Title: size.Title, MinPages: size.MinPages, MaxPages: size.MaxPages})
+	}
+	return resp, nil
+}
+
+// ListEras returns every era
+func (s *CatalogServer) ListEras(ctx context.Context, req *pb.ListErasRequest) (*pb.ListErasResponse, error) {
+	eras, err := s.EraMediatorFactory().Get(ctx)
+	if err != nil {
+		return nil, s.fail(ctx, err)
+	}
+
+	resp := &pb.ListErasResponse{Eras: make([]*pb.Era, 0, len(eras))}
+	for _, era := range eras {
+		resp.Eras = append(resp.Eras, &pb.Era{Id: era.ID, Title: era.Title, MinYear: era.MinYear, MaxYear: era.MaxYear})
+	}
+	return resp, nil
+}
@@ -0,0 +1,396 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/book-recommendations/service/controllers/translators"
+	"github.com/book-recommendations/service/mediators"
+	"github.com/book-recommendations/service/models"
+	pb "github.com/book-recommendations/service/proto/readcommend/v1"
+	"github.com/book-recommendations/service/rpc"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type BookMediatorMock struct {
+	BookField    []models.Book
+	ErrorField   error
+	RequestField models.BookRequest
+}
+
+func (m *BookMediatorMock) Get(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
+	m.RequestField = req
+	return m.BookField, m.ErrorField
+}
+
+func (m *BookMediatorMock) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	if m.ErrorField != nil {
+		return models.Book{}, m.ErrorField
+	}
+	for _, book := range m.BookField {
+		if book.ID == id {
+			return book, nil
+		}
+	}
+	return models.Book{}, models.NewError(models.KindNotFound, "book %d not found", id)
+}
+
+func (m *BookMediatorMock) GetHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	return nil, m.ErrorField
+}
+
+func (m *BookMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+func (m *BookMediatorMock) Restore(ctx context.Context, id int64) (models.Book, error) {
+	return models.Book{ID: id}, m.ErrorField
+}
+
+func (m *BookMediatorMock) Count(ctx context.Context, req models.BookRequest) (int64, error) {
+	return int64(len(m.BookField)), m.ErrorField
+}
+
+func (m *BookMediatorMock) Similar(ctx context.Context, req models.SimilarBooksRequest) ([]models.SimilarBook, error) {
+	return nil, m.ErrorField
+}
+
+func (m *BookMediatorMock) Facets(ctx context.Context, req models.BookRequest) (models.Facets, error) {
+	return nil, m.ErrorField
+}
+
+type AuthorMediatorMock struct {
+	AuthorField []models.Author
+	ErrorField  error
+}
+
+func (m *AuthorMediatorMock) Get(ctx context.Context) ([]models.Author, error) {
+	return m.AuthorField, m.ErrorField
+}
+
+func (m *AuthorMediatorMock) GetAuthor(ctx context.Context, req models.AuthorRequest) (models.AuthorDetail, error) {
+	return models.AuthorDetail{}, m.ErrorField
+}
+
+func (m *AuthorMediatorMock) GetBooks(ctx context.Context, req models.AuthorBooksRequest) ([]models.Book, error) {
+	return nil, m.ErrorField
+}
+
+type EraMediatorMock struct {
+	EraField   []models.Era
+	ErrorField error
+}
+
+func (m *EraMediatorMock) Get(ctx context.Context) ([]models.Era, error) {
+	return m.EraField, m.ErrorField
+}
+
+func (m *EraMediatorMock) GetAll(ctx context.Context) ([]models.Era, error) {
+	return m.EraField, m.ErrorField
+}
+
+func (m *EraMediatorMock) Create(ctx context.Context, req models.EraRequest) (models.Era, error) {
+	return models.Era{}, m.ErrorField
+}
+
+func (m *EraMediatorMock) Update(ctx context.Context, id int64, req models.EraRequest) (models.Era, error) {
+	return models.Era{}, m.ErrorField
+}
+
+func (m *EraMediatorMock) Delete(ctx context.Context, id int64) error {
+	return m.ErrorField
+}
+
+// dial serves the catalog over an in-memory connection and returns a client of it
+func dial(t *testing.T, catalog *rpc.CatalogServer) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := rpc.NewServer(log.NewEntry(log.New()), catalog, time.Second)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+var books = []models.Book{
+	{
+		ID:      1,
+		Title:   "Alanna Saves the Day",
+		Rating:  1.62,
+		Genres:  []models.Genre{{ID: 8, Title: "Childrens"}},
+		Authors: []models.BookAuthor{{Author: models.Author{ID: 6, FirstName: "Bernard", LastName: "Hopf"}, Role: models.RoleAuthor}},
+		Series:  &models.BookSeries{ID: 2, Name: "Alanna", Position: 1},
+	},
+	{
+		ID:    2,
+		Title: "Adventures of Kaya",
+	},
+}
+
+func TestCatalogServer_ListBooks(t *testing.T) {
+	var cases = []struct {
+		name      string
+		mediator  *BookMediatorMock
+		request   *pb.ListBooksRequest
+		userID    string
+		signature string
+		assert    func(received []*pb.Book, err error, mediator *BookMediatorMock)
+	}{
+		{
+			name:     "success",
+			mediator: &BookMediatorMock{BookField: books},
+			request: &pb.ListBooksRequest{
+				Authors:    []int64{6},
+				GenreMatch: pb.GenreMatch_GENRE_MATCH_ALL,
+				Pages:      &pb.Range{Min: ptr(int64(100))},
+				Sort:       pb.Ranker_RANKER_RECENCY,
+				Limit:      ptr(int64(5)),
+			},
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				require.NoError(t, err)
+				require.Len(t, received, 2)
+				assert.Equal(t, "Alanna Saves the Day", received[0].Title)
+				assert.Equal(t, "Hopf", received[0].Authors[0].Author.LastName)
+				assert.Equal(t, models.RoleAuthor, received[0].Authors[0].Role)
+				assert.Equal(t, int64(1), received[0].Series.Position)
+				assert.Nil(t, received[1].Series)
+
+				assert.Equal(t, "6", mediator.RequestField.Authors)
+				assert.Equal(t, models.GenreMatchAll, mediator.RequestField.GenreMatch)
+				assert.Equal(t, "100", mediator.RequestField.MinPages)
+				assert.Equal(t, models.RankerRecency, mediator.RequestField.Ranker)
+				assert.Equal(t, "5", mediator.RequestField.Limit)
+			},
+		},
+		{
+			name:     "invalid",
+			mediator: &BookMediatorMock{},
+			request:  &pb.ListBooksRequest{MinRating: ptr(6.0), Sort: pb.Ranker(9)},
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				assert.Contains(t, status.Convert(err).Message(), "minRating")
+				assert.Contains(t, status.Convert(err).Message(), "sort")
+			},
+		},
+		{
+			name:     "exclude read anonymous",
+			mediator: &BookMediatorMock{},
+			request:  &pb.ListBooksRequest{ExcludeRead: true},
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:      "exclude read",
+			mediator:  &BookMediatorMock{},
+			request:   &pb.ListBooksRequest{ExcludeRead: true},
+			userID:    "42",
+			signature: translators.SignUserID("secret", "42"),
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(42), mediator.RequestField.UserID)
+			},
+		},
+		{
+			name:      "exclude read forged user",
+			mediator:  &BookMediatorMock{},
+			request:   &pb.ListBooksRequest{ExcludeRead: true},
+			userID:    "42",
+			signature: translators.SignUserID("guess", "42"),
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+				assert.Zero(t, mediator.RequestField.UserID)
+			},
+		},
+		{
+			name:     "failure",
+			mediator: &BookMediatorMock{ErrorField: errors.New("connection refused")},
+			request:  &pb.ListBooksRequest{},
+			assert: func(received []*pb.Book, err error, mediator *BookMediatorMock) {
+				assert.Equal(t, codes.Internal, status.Code(err))
+				assert.Equal(t, "internal server error", status.Convert(err).Message())
+			},
+		},
+	}
+	for _, c := range cases {
+		conn := dial(t, &rpc.CatalogServer{
+			Logger:              log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator { return c.mediator },
+			UserIDSecret:        "secret",
+		})
+
+		ctx := context.Background()
+		if c.userID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-user-id", c.userID, "x-user-signature", c.signature)
+		}
+		stream, err := pb.NewCatalogServiceClient(conn).ListBooks(ctx, c.request)
+		require.NoError(t, err)
+
+		var received []*pb.Book
+		for {
+			book, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				c.assert(received, err, c.mediator)
+				break
+			}
+			received = append(received, book)
+		}
+	}
+}
+
+func TestCatalogServer_GetBook(t *testing.T) {
+	var cases = []struct {
+		name     string
+		mediator *BookMediatorMock
+		id       int64
+		assert   func(book *pb.Book, err error)
+	}{
+		{
+			name:     "success",
+			mediator: &BookMediatorMock{BookField: books},
+			id:       1,
+			assert: func(book *pb.Book, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "Alanna Saves the Day", book.Title)
+				assert.Equal(t, "Childrens", book.Genres[0].Title)
+			},
+		},
+		{
+			name:     "not found",
+			mediator: &BookMediatorMock{BookField: books},
+			id:       99,
+			assert: func(book *pb.Book, err error) {
+				assert.Equal(t, codes.NotFound, status.Code(err))
+				assert.Equal(t, "book 99 not found", status.Convert(err).Message())
+			},
+		},
+		{
+			name:     "invalid id",
+			mediator: &BookMediatorMock{},
+			id:       0,
+			assert: func(book *pb.Book, err error) {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:     "timeout",
+			mediator: &BookMediatorMock{ErrorField: models.WrapError(models.KindTimeout, context.DeadlineExceeded, "the query did not finish in time")},
+			id:       1,
+			assert: func(book *pb.Book, err error) {
+				assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+			},
+		},
+	}
+	for _, c := range cases {
+		conn := dial(t, &rpc.CatalogServer{
+			Logger:              log.NewEntry(log.New()),
+			BookMediatorFactory: func() mediators.BookMediator { return c.mediator },
+		})
+
+		book, err := pb.NewCatalogServiceClient(conn).GetBook(context.Background(), &pb.GetBookRequest{Id: c.id})
+		c.assert(book, err)
+	}
+}
+
+func TestCatalogServer_ListAuthors(t *testing.T) {
+	var cases = []struct {
+		name     string
+		mediator *AuthorMediatorMock
+		assert   func(resp *pb.ListAuthorsResponse, err error)
+	}{
+		{
+			name: "success",
+			mediator: &AuthorMediatorMock{AuthorField: []models.Author{
+				{ID: 1, FirstName: "Abraham", LastName: "Stackhouse"},
+				{ID: 2, FirstName: "Amelia", LastName: "Wangerin, Jr."},
+			}},
+			assert: func(resp *pb.ListAuthorsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Authors, 2)
+				assert.Equal(t, "Abraham", resp.Authors[0].FirstName)
+			},
+		},
+		{
+			name:     "failure",
+			mediator: &AuthorMediatorMock{ErrorField: errors.New("Error")},
+			assert: func(resp *pb.ListAuthorsResponse, err error) {
+				assert.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+	for _, c := range cases {
+		conn := dial(t, &rpc.CatalogServer{
+			Logger:                log.NewEntry(log.New()),
+			AuthorMediatorFactory: func() mediators.AuthorMediator { return c.mediator },
+		})
+
+		resp, err := pb.NewCatalogServiceClient(conn).ListAuthors(context.Background(), &pb.ListAuthorsRequest{})
+		c.assert(resp, err)
+	}
+}
+
+func TestCatalogServer_ListEras(t *testing.T) {
+	conn := dial(t, &rpc.CatalogServer{
+		Logger: log.NewEntry(log.New()),
+		EraMediatorFactory: func() mediators.EraMediator {
+			return &EraMediatorMock{EraField: []models.Era{
+				{ID: 1, Title: "Classic", MaxYear: ptr(int64(1969))},
+				{ID: 2, Title: "Modern", MinYear: ptr(int64(1970))},
+			}}
+		},
+	})
+
+	resp, err := pb.NewCatalogServiceClient(conn).ListEras(context.Background(), &pb.ListErasRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Eras, 2)
+	assert.Equal(t, int64(1969), resp.Eras[0].GetMaxYear())
+	assert.Nil(t, resp.Eras[0].MinYear)
+}
+
+func TestNewServer(t *testing.T) {
+	conn := dial(t, &rpc.CatalogServer{
+		Logger: log.NewEntry(log.New()),
+		AuthorMediatorFactory: func() mediators.AuthorMediator {
+			return &AuthorMediatorMock{}
+		},
+	})
+
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: pb.CatalogService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.Status)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-42")
+	_, err = pb.NewCatalogServiceClient(conn).ListAuthors(ctx, &pb.ListAuthorsRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-42"}, header.Get("x-request-id"))
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
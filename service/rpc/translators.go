@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"github.com/book-recommendations/service/models"
+	pb "github.com/book-recommendations/service/proto/readcommend/v1"
+)
+
+var (
+	genreMatches = map[pb.GenreMatch]string{
+		pb.GenreMatch_GENRE_MATCH_ANY: models.GenreMatchAny,
+		pb.GenreMatch_GENRE_MATCH_ALL: models.GenreMatchAll,
+	}
+	rankers = map[pb.Ranker]string{
+		pb.Ranker_RANKER_RATING:   models.RankerRating,
+		pb.Ranker_RANKER_WEIGHTED: models.RankerWeighted,
+		pb.Ranker_RANKER_RECENCY:  models.RankerRecency,
+		pb.Ranker_RANKER_RANDOM:   models.RankerRandom,
+	}
+)
+
+// toBookSearchRequest returns the search of books of a request, whose criteria it shares
+func toBookSearchRequest(req *pb.ListBooksRequest) models.BookSearchRequest {
+	return models.BookSearchRequest{
+		Authors:           req.Authors,
+		Genres:            req.Genres,
+		GenreMatch:        enumValue(genreMatches, req.GenreMatch),
+		ExcludeAuthors:    req.ExcludeAuthors,
+		ExcludeGenres:     req.ExcludeGenres,
+		Pages:             toRange(req.Pages),
+		Years:             toRange(req.Years),
+		MinRating:         req.MinRating,
+		ISBN:              req.Isbn,
+		Languages:         req.Languages,
+		FirstInSeriesOnly: req.FirstInSeriesOnly,
+		ExcludeRead:       req.ExcludeRead,
+		Sort:              enumValue(rankers, req.Sort),
+		Limit:             req.Limit,
+		Offset:            req.Offset,
+	}
+}
+
+// enumValue returns the value of the model matching an enum, none for the unspecified
+// value and the number of the unknown ones, for the validation to reject them
+func enumValue[E interface {
+	~int32
+	String() string
+}](values map[E]string, value E) string {
+	if name, ok := values[value]; ok {
+		return name
+	}
+	if value == 0 {
+		return ""
+	}
+	return value.String()
+}
+
+func toRange(r *pb.Range) *models.Range {
+	if r == nil {
+		return nil
+	}
+	return &models.Range{Min: r.Min, Max: r.Max}
+}
+
+func toBook(book models.Book) *pb.Book {
+	msg := &pb.Book{
+		Id:             book.ID,
+		Title:          book.Title,
+		YearPublished:  book.YearPublished,
+		Rating:         book.Rating,
+		RatingCount:    book.RatingCount,
+		WeightedRating: book.WeightedRating,
+		Pages:          book.Pages,
+		Authors:        make([]*pb.BookAuthor, 0, len(book.Authors)),
+		Genres:         make([]*pb.Genre, 0, len(book.Genres)),
+		Isbn10:         book.ISBN10,
+		Isbn13:         book.ISBN13,
+		Language:       book.Language,
+		Publisher:      book.Publisher,
+		Description:    book.Description,
+		CoverUrl:       book.CoverURL,
+	}
+	for _, author := range book.Authors {
+		msg.Authors = append(msg.Authors, &pb.BookAuthor{Author: toAuthor(author.Author), Role: author.Role})
+	}
+	for _, genre := range book.Genres {
+		msg.Genres = append(msg.Genres, toGenre(genre))
+	}
+	if book.Series != nil {
+		msg.Series = &pb.BookSeries{Id: book.Series.ID, Name: book.Series.Name, Position: book.Series.Position}
+	}
+	return msg
+}
+
+func toAuthor(author models.Author) *pb.Author {
+	return &pb.Author{Id: author.ID, FirstName: author.FirstName, LastName: author.LastName}
+}
+
+func toGenre(genre models.Genre) *pb.Genre {
+	return &pb.Genre{Id: genre.ID, Title: genre.Title}
+}
@@ -0,0 +1,260 @@
+package stores
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableAuditLog = "audit_log"
+
+	auditColumns = "id, actor, action, entity, entity_id, before, after, diff, COALESCE(request_id, ''), created_at"
+)
+
+type auditSourceKey struct{}
+
+// auditSource is who makes the writes of a context, and from which request
+type auditSource struct {
+	actor     string
+	requestID string
+}
+
+// WithAuditSource returns a context whose writes are recorded in the audit log as made by
+// the actor, from the request with the given ID
+func WithAuditSource(ctx context.Context, actor, requestID string) context.Context {
+	return context.WithValue(ctx, auditSourceKey{}, auditSource{actor: actor, requestID: requestID})
+}
+
+// auditSourceFrom returns who makes the writes of the context, the service itself when unknown
+func auditSourceFrom(ctx context.Context) auditSource {
+	source, ok := ctx.Value(auditSourceKey{}).(auditSource)
+	if !ok || source.actor == "" {
+		return auditSource{actor: models.AuditSystem, requestID: source.requestID}
+	}
+	return source
+}
+
+// AuditStore specifies the methods to read and prune the audit log, which the other stores
+// write along with their writes
+type AuditStore interface {
+	GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	ExportAuditEntries(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEntry) error) error
+	DeleteAuditEntries(ctx context.Context, before time.Time) (int64, error)
+}
+
+type auditStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewAuditStore(logger *log.Entry, db *sqlx.DB) AuditStore {
+	return &auditStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// GetAuditEntries returns the entries matching the filter, latest first
+func (s *auditStore) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	entries := make([]models.AuditEntry, 0)
+	err := s.ExportAuditEntries(ctx, filter, func(entry models.AuditEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExportAuditEntries calls fn with every entry matching the filter, latest first, as they
+// are read, and stops at the first error it returns. There is no limit unless the filter sets one.
+func (s *auditStore) ExportAuditEntries(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEntry) error) error {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Entity != "" {
+		where("entity = $%d", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		where("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until)
+	}
+
+	getEntriesSQL := fmt.Sprintf(`SELECT %s FROM %s`, auditColumns, tableAuditLog)
+	if len(conditions) > 0 {
+		getEntriesSQL += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	getEntriesSQL += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		getEntriesSQL += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, getEntriesSQL, args...)
+	if err != nil {
+		return mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+	for rows.Next() {
+		var (
+			entry               models.AuditEntry
+			before, after, diff []byte
+		)
+		err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.Entity, &entry.EntityID,
+			&before, &after, &diff, &entry.RequestID, &entry.CreatedAt)
+		if err != nil {
+			return mapError(fmt.Errorf("error getting audit entry: %w", err))
+		}
+		entry.Before, entry.After, entry.Diff = before, after, diff
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return mapError(rows.Err())
+}
+
+// DeleteAuditEntries deletes the entries written before the given time and returns their number
+func (s *auditStore) DeleteAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	deleteEntriesSQL := fmt.Sprintf(`DELETE FROM %s WHERE created_at < $1`, tableAuditLog)
+
+	result, err := s.db.ExecContext(ctx, deleteEntriesSQL, before)
+	if err != nil {
+		return 0, mapError(fmt.Errorf("error deleting audit entries: %w", err))
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error counting deleted audit entries: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (s *auditStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}
+
+// addAuditEntry records a write of the catalog in the audit log, in the transaction of the
+// write, as made by the source of the context. before is nil for the entities the write
+// creates, and after is nil for the ones it deletes.
+func addAuditEntry(ctx context.Context, tx *sql.Tx, action, entity string, entityID int64, before, after interface{}) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return fmt.Errorf("error encoding %s %d before %s: %w", entity, entityID, action, err)
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return fmt.Errorf("error encoding %s %d after %s: %w", entity, entityID, action, err)
+	}
+	diff, err := auditDiff(beforeJSON, afterJSON)
+	if err != nil {
+		return fmt.Errorf("error comparing %s %d: %w", entity, entityID, err)
+	}
+
+	source := auditSourceFrom(ctx)
+	addEntrySQL := fmt.Sprintf(`INSERT INTO %s (actor, action, entity, entity_id, before, after, diff, request_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`, tableAuditLog)
+	_, err = tx.ExecContext(ctx, addEntrySQL,
+		source.actor, action, entity, entityID, jsonArg(beforeJSON), jsonArg(afterJSON), diff, source.requestID)
+	if err != nil {
+		return mapError(fmt.Errorf("error adding audit entry: %w", err))
+	}
+
+	return nil
+}
+
+// auditJSON returns the JSON of the entity, nil when there is none
+func auditJSON(entity interface{}) ([]byte, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	return json.Marshal(entity)
+}
+
+// jsonArg returns the argument of a JSONB column, NULL when there is no JSON
+func jsonArg(value []byte) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+// auditDiff returns the JSON object holding, for every top-level field of the entities that
+// differs between them, an object with its before and after value, null when missing
+func auditDiff(before, after []byte) ([]byte, error) {
+	fields := func(entity []byte) (map[string]json.RawMessage, error) {
+		values := map[string]json.RawMessage{}
+		if entity == nil {
+			return values, nil
+		}
+		return values, json.Unmarshal(entity, &values)
+	}
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+
+	type change struct {
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}
+	diff := make(map[string]change)
+	for name := range names {
+		beforeValue, afterValue := beforeFields[name], afterFields[name]
+		if bytes.Equal(beforeValue, afterValue) {
+			continue
+		}
+		if beforeValue == nil {
+			beforeValue = json.RawMessage("null")
+		}
+		if afterValue == nil {
+			afterValue = json.RawMessage("null")
+		}
+		diff[name] = change{Before: beforeValue, After: afterValue}
+	}
+
+	return json.Marshal(diff)
+}
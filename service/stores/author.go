@@ -2,20 +2,28 @@ package stores
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/book-recommendations/service/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	tableAuthor = "author"
+	tableAuthor         = "author"
+	tableAuthorRedirect = "author_redirect"
 )
 
-// AuthorStore specifies the methods to get authors
+// AuthorStore specifies the methods to get, deduplicate and merge authors
 type AuthorStore interface {
 	GetAllAuthors(ctx context.Context) ([]models.Author, error)
+	GetAuthor(ctx context.Context, id int64) (models.AuthorDetail, error)
+	GetDuplicateAuthors(ctx context.Context, filter models.DuplicateAuthorsFilter) ([]models.DuplicateAuthors, error)
+	MergeAuthors(ctx context.Context, fromID, intoID int64) error
 }
 
 type authorStore struct {
@@ -35,7 +43,7 @@ func (s *authorStore) GetAllAuthors(ctx context.Context) ([]models.Author, error
 
 	rows, err := s.db.QueryContext(ctx, getAuthorsSQL)
 	if err != nil {
-		return nil, fmt.Errorf("error while building query: %w", err)
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
 	}
 	defer func() {
 		errClose := rows.Close()
@@ -69,3 +77,231 @@ func (s *authorStore) GetAllAuthors(ctx context.Context) ([]models.Author, error
 
 	return authors, nil
 }
+
+// GetAuthor returns the author with the given id along with the figures of their books,
+// whatever their role in them, or a not found error when there is none. The id of a merged
+// author returns the author they were merged into. The average rating weighs the books by
+// their number of ratings, or is their plain average when none of them was rated by users.
+func (s *authorStore) GetAuthor(ctx context.Context, id int64) (models.AuthorDetail, error) {
+	getAuthorSQL := fmt.Sprintf(`SELECT au.id, au.first_name, au.last_name, COUNT(bo.id),
+	COALESCE(SUM(bo.rating * bo.rating_count) / NULLIF(SUM(bo.rating_count), 0), AVG(bo.rating), 0),
+	MIN(bo.year_published), MAX(bo.year_published)
+	FROM %s AS au
+	LEFT JOIN %s AS bo ON bo.id IN (SELECT ba.book_id FROM %s AS ba WHERE ba.author_id = au.id) AND bo.deleted_at IS NULL
+	WHERE au.id = COALESCE((SELECT ar.to_id FROM %s AS ar WHERE ar.from_id = $1), $1)
+	GROUP BY au.id`, tableAuthor, tableBook, tableBookAuthor, tableAuthorRedirect)
+
+	var (
+		author    models.AuthorDetail
+		firstYear sql.NullInt64
+		lastYear  sql.NullInt64
+	)
+	err := s.db.QueryRowContext(ctx, getAuthorSQL, id).Scan(&author.ID, &author.FirstName, &author.LastName,
+		&author.BookCount, &author.AverageRating, &firstYear, &lastYear)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.AuthorDetail{}, models.NewError(models.KindNotFound, "author %d not found", id)
+	}
+	if err != nil {
+		return models.AuthorDetail{}, mapError(fmt.Errorf("error getting author: %w", err))
+	}
+	if firstYear.Valid {
+		author.YearsActive = &models.YearsActive{From: firstYear.Int64, To: lastYear.Int64}
+	}
+
+	// the main genres are the ones of the most books of the author
+	getGenresSQL := fmt.Sprintf(`SELECT ge.id, ge.title, COUNT(*) FROM %s AS ge
+	JOIN %s AS bg ON bg.genre_id = ge.id
+	JOIN %s AS bo ON bo.id = bg.book_id AND bo.deleted_at IS NULL
+	WHERE bg.book_id IN (SELECT ba.book_id FROM %s AS ba WHERE ba.author_id = $1)
+	GROUP BY ge.id, ge.title
+	ORDER BY 3 DESC, ge.title
+	LIMIT %d`, tableGenre, tableBookGenre, tableBook, tableBookAuthor, models.MainGenres)
+
+	rows, err := s.db.QueryContext(ctx, getGenresSQL, author.ID)
+	if err != nil {
+		return models.AuthorDetail{}, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	author.MainGenres = make([]models.GenreCount, 0, models.MainGenres)
+	for rows.Next() {
+		var genre models.GenreCount
+		if err := rows.Scan(&genre.ID, &genre.Title, &genre.BookCount); err != nil {
+			return models.AuthorDetail{}, fmt.Errorf("error getting author genres: %w", err)
+		}
+		author.MainGenres = append(author.MainGenres, genre)
+	}
+
+	return author, mapError(rows.Err())
+}
+
+// GetDuplicateAuthors returns the pairs of authors whose names are the same once normalized,
+// whose full names are at least filter.MinSimilarity similar, or who share the title of
+// different books, the most likely duplicates first. The similar names are found through the
+// trigram index of the full names rather than by comparing every pair of authors.
+func (s *authorStore) GetDuplicateAuthors(ctx context.Context, filter models.DuplicateAuthorsFilter) ([]models.DuplicateAuthors, error) {
+	// the threshold of the % operator, which set_limit sets for the whole session, is only set
+	// for the transaction so that it does not outlive the query on the pooled connection
+	setLimitSQL := `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`
+	getDuplicatesSQL := fmt.Sprintf(`WITH names AS (
+		SELECT au.id, au.first_name, au.last_name, au.first_name || ' ' || au.last_name AS full_name,
+		regexp_replace(lower(au.first_name || au.last_name), '[[:space:][:punct:]]+', '', 'g') AS normalized,
+		(SELECT COUNT(DISTINCT ba.book_id) FROM %[2]s AS ba
+			JOIN %[3]s AS bo ON bo.id = ba.book_id AND bo.deleted_at IS NULL
+			WHERE ba.author_id = au.id) AS book_count
+		FROM %[1]s AS au
+	), titles AS (
+		SELECT ba.author_id, ba.book_id, bo.title, lower(bo.title) AS title_key
+		FROM %[2]s AS ba JOIN %[3]s AS bo ON bo.id = ba.book_id AND bo.deleted_at IS NULL
+	), shared AS (
+		SELECT t1.author_id AS first_id, t2.author_id AS second_id, array_agg(DISTINCT t1.title) AS titles
+		FROM titles AS t1
+		JOIN titles AS t2 ON t2.title_key = t1.title_key AND t2.book_id <> t1.book_id AND t2.author_id > t1.author_id
+		GROUP BY 1, 2
+	), pairs AS (
+		SELECT a.id AS first_id, b.id AS second_id FROM names AS a
+		JOIN names AS b ON b.normalized = a.normalized AND b.id > a.id
+		UNION
+		SELECT a.id, b.id FROM %[1]s AS a
+		JOIN %[1]s AS b ON (b.first_name || ' ' || b.last_name) %% (a.first_name || ' ' || a.last_name) AND b.id > a.id
+		UNION
+		SELECT first_id, second_id FROM shared
+	)
+	SELECT a.id, a.first_name, a.last_name, a.book_count, b.id, b.first_name, b.last_name, b.book_count,
+	a.normalized = b.normalized, similarity(a.full_name, b.full_name), COALESCE(sh.titles, '{}')
+	FROM pairs AS p
+	JOIN names AS a ON a.id = p.first_id
+	JOIN names AS b ON b.id = p.second_id
+	LEFT JOIN shared AS sh ON sh.first_id = a.id AND sh.second_id = b.id
+	ORDER BY 9 DESC, 10 DESC, a.id, b.id
+	LIMIT $1`, tableAuthor, tableBookAuthor, tableBook)
+
+	duplicates := make([]models.DuplicateAuthors, 0)
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		threshold := strconv.FormatFloat(filter.MinSimilarity, 'f', -1, 64)
+		if _, err := tx.ExecContext(ctx, setLimitSQL, threshold); err != nil {
+			return mapError(fmt.Errorf("error setting the similarity threshold: %w", err))
+		}
+		rows, err := tx.QueryContext(ctx, getDuplicatesSQL, filter.Limit)
+		if err != nil {
+			return mapError(fmt.Errorf("error while building query: %w", err))
+		}
+		defer s.closeRows(rows)
+
+		for rows.Next() {
+			var pair models.DuplicateAuthors
+			err := rows.Scan(&pair.Keep.ID, &pair.Keep.FirstName, &pair.Keep.LastName, &pair.Keep.BookCount,
+				&pair.Merge.ID, &pair.Merge.FirstName, &pair.Merge.LastName, &pair.Merge.BookCount,
+				&pair.SameNormalizedName, &pair.NameSimilarity, pq.Array(&pair.SharedTitles))
+			if err != nil {
+				return mapError(fmt.Errorf("error getting duplicate authors: %w", err))
+			}
+			duplicates = append(duplicates, pair)
+		}
+		return mapError(rows.Err())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+// MergeAuthors moves every book of the author fromID to the author intoID, deletes the
+// author fromID and redirects their ID, and the IDs redirected to them, to intoID. It
+// returns models.ErrNotFound when either author does not exist.
+func (s *authorStore) MergeAuthors(ctx context.Context, fromID, intoID int64) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		authors, err := lockAuthors(ctx, tx, fromID, intoID)
+		if err != nil {
+			return err
+		}
+
+		// the primary author of the books follows their contributions through the
+		// book_author_primary trigger. A book both authors contributed to in the same role
+		// keeps the first of their positions.
+		mergeContributionsSQL := fmt.Sprintf(`UPDATE %[1]s AS kept SET position = LEAST(kept.position, merged.position)
+		FROM %[1]s AS merged
+		WHERE merged.author_id = $1 AND kept.author_id = $2 AND kept.book_id = merged.book_id AND kept.role = merged.role`, tableBookAuthor)
+		if _, err := tx.ExecContext(ctx, mergeContributionsSQL, fromID, intoID); err != nil {
+			return mapError(fmt.Errorf("error merging contributions of author: %w", err))
+		}
+		deleteContributionsSQL := fmt.Sprintf(`DELETE FROM %[1]s AS merged
+		WHERE merged.author_id = $1 AND EXISTS (SELECT 1 FROM %[1]s AS kept
+			WHERE kept.author_id = $2 AND kept.book_id = merged.book_id AND kept.role = merged.role)`, tableBookAuthor)
+		if _, err := tx.ExecContext(ctx, deleteContributionsSQL, fromID, intoID); err != nil {
+			return mapError(fmt.Errorf("error merging contributions of author: %w", err))
+		}
+		moveContributionsSQL := fmt.Sprintf(`UPDATE %s SET author_id = $2 WHERE author_id = $1`, tableBookAuthor)
+		if _, err := tx.ExecContext(ctx, moveContributionsSQL, fromID, intoID); err != nil {
+			return mapError(fmt.Errorf("error moving contributions of author: %w", err))
+		}
+
+		moveRedirectsSQL := fmt.Sprintf(`UPDATE %s SET to_id = $2 WHERE to_id = $1`, tableAuthorRedirect)
+		if _, err := tx.ExecContext(ctx, moveRedirectsSQL, fromID, intoID); err != nil {
+			return mapError(fmt.Errorf("error moving author redirects: %w", err))
+		}
+		addRedirectSQL := fmt.Sprintf(`INSERT INTO %s (from_id, to_id) VALUES ($1, $2)`, tableAuthorRedirect)
+		if _, err := tx.ExecContext(ctx, addRedirectSQL, fromID, intoID); err != nil {
+			return mapError(fmt.Errorf("error adding author redirect: %w", err))
+		}
+		deleteAuthorSQL := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, tableAuthor)
+		if _, err := tx.ExecContext(ctx, deleteAuthorSQL, fromID); err != nil {
+			return mapError(fmt.Errorf("error deleting merged author: %w", err))
+		}
+
+		if err := addAuditEntry(ctx, tx, models.AuditMerge, models.AuditAuthor, fromID, authors[fromID], authors[intoID]); err != nil {
+			return err
+		}
+		_, err = addChangeEvent(ctx, tx, models.ChangeAuthorDeleted, fromID, map[string]int64{"id": fromID, "mergedInto": intoID})
+		return err
+	})
+}
+
+// redirectedAuthors returns the query of the given authors, a comma-delimited list of IDs,
+// the IDs of the merged authors being resolved to the author they were merged into
+func redirectedAuthors(authors string) string {
+	return fmt.Sprintf(`SELECT COALESCE(rd.to_id, ids.id) FROM unnest(ARRAY[%s]::INTEGER[]) AS ids(id)
+		LEFT JOIN %s AS rd ON rd.from_id = ids.id`, authors, tableAuthorRedirect)
+}
+
+func (s *authorStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}
+
+// lockAuthors locks the authors with the given ids for the transaction and returns them by
+// id, or models.ErrNotFound when one of them does not exist
+func lockAuthors(ctx context.Context, tx *sql.Tx, ids ...int64) (map[int64]models.Author, error) {
+	lockAuthorsSQL := fmt.Sprintf(`SELECT id, first_name, last_name FROM %s WHERE id = ANY($1) ORDER BY id FOR UPDATE`, tableAuthor)
+	rows, err := tx.QueryContext(ctx, lockAuthorsSQL, pq.Array(ids))
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error locking authors: %w", err))
+	}
+	defer rows.Close()
+
+	authors := make(map[int64]models.Author, len(ids))
+	for rows.Next() {
+		var author models.Author
+		if err := rows.Scan(&author.ID, &author.FirstName, &author.LastName); err != nil {
+			return nil, fmt.Errorf("error locking authors: %w", err)
+		}
+		authors[author.ID] = author
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+
+	for _, id := range ids {
+		if _, ok := authors[id]; !ok {
+			return nil, models.NewError(models.KindNotFound, "author %d not found", id)
+		}
+	}
+	return authors, nil
+}
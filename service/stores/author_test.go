@@ -0,0 +1,64 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorStore_GetAuthor(t *testing.T) {
+	db := testDB(t)
+	seed(t, db,
+		`INSERT INTO author (id, first_name, last_name) VALUES (900001, 'Catalog', 'Only'), (900002, 'Rated', 'Users')`,
+		`INSERT INTO book (id, title, year_published, rating, pages) VALUES
+			(900001, 'First', 1990, 4.5, 100), (900002, 'Second', 2000, 3.5, 100),
+			(900003, 'Rated Once', 2000, 1, 100), (900004, 'Rated Thrice', 2010, 1, 100)`,
+		`INSERT INTO book_author (book_id, author_id) VALUES
+			(900001, 900001), (900002, 900001), (900003, 900002), (900004, 900002)`,
+		`INSERT INTO user_rating (user_id, book_id, rating) VALUES
+			(900001, 900003, 2), (900001, 900004, 4), (900002, 900004, 4), (900003, 900004, 4)`,
+	)
+
+	var cases = []struct {
+		name   string
+		id     int64
+		assert func(author models.AuthorDetail, err error)
+	}{
+		{
+			name: "catalog ratings",
+			id:   900001,
+			assert: func(author models.AuthorDetail, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), author.BookCount)
+				assert.InDelta(t, 4.0, author.AverageRating, 0.001)
+				assert.Equal(t, &models.YearsActive{From: 1990, To: 2000}, author.YearsActive)
+			},
+		},
+		{
+			name: "user ratings",
+			id:   900002,
+			assert: func(author models.AuthorDetail, err error) {
+				assert.NoError(t, err)
+				// (2 * 1 + 4 * 3) / 4
+				assert.InDelta(t, 3.5, author.AverageRating, 0.001)
+			},
+		},
+		{
+			name: "not found",
+			id:   900003,
+			assert: func(author models.AuthorDetail, err error) {
+				assert.ErrorIs(t, err, models.ErrNotFound)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := stores.NewAuthorStore(testLogger(), db)
+			author, err := store.GetAuthor(context.Background(), c.id)
+			c.assert(author, err)
+		})
+	}
+}
@@ -2,22 +2,54 @@ package stores
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/book-recommendations/service/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	tableBook = "book"
+	tableBook         = "book"
+	tableRankingPrior = "ranking_prior"
+	tableBookAuthor   = "book_author"
+	tableBookGenre    = "book_genre"
+	tableBookHistory  = "book_history"
+
+	// bookColumns are the columns read by scanBooks, they expect the bo alias for the book.
+	// The authors and the genres of each book are aggregated as JSON arrays, in order, and
+	// its series as a JSON object, NULL for books outside of any series.
+	bookColumns = `bo.id, bo.title, bo.year_published, bo.rating, bo.rating_count, bo.weighted_rating, bo.pages,
+	COALESCE(bo.isbn10, ''), COALESCE(bo.isbn13, ''), COALESCE(bo.language, ''),
+	COALESCE(bo.publisher, ''), COALESCE(bo.description, ''), COALESCE(bo.cover_url, ''),
+	COALESCE((SELECT json_agg(json_build_object('id', au.id, 'firstName', au.first_name, 'lastName', au.last_name, 'role', ba.role)
+		ORDER BY ba.position, ba.role)
+		FROM book_author AS ba JOIN author AS au ON au.id = ba.author_id WHERE ba.book_id = bo.id), '[]'),
+	COALESCE((SELECT json_agg(json_build_object('id', ge.id, 'title', ge.title) ORDER BY bg.position)
+		FROM book_genre AS bg JOIN genre AS ge ON ge.id = bg.genre_id WHERE bg.book_id = bo.id), '[]'),
+	(SELECT json_build_object('id', sr.id, 'name', sr.name, 'position', bo.series_position)
+		FROM series AS sr WHERE sr.id = bo.series_id)`
 )
 
 // BookStore specifies the methods to get books
 type BookStore interface {
 	GetBooks(ctx context.Context, req models.BookRequest) ([]models.Book, error)
+	CountBooks(ctx context.Context, req models.BookRequest) (int64, error)
+	GetFacets(ctx context.Context, req models.BookRequest, facets []string) (models.Facets, error)
+	GetBook(ctx context.Context, id int64) (models.Book, error)
+	GetBooksByIDs(ctx context.Context, ids []int64, req models.BookRequest) ([]models.Book, error)
+	GetBookHistory(ctx context.Context, id int64) ([]models.BookVersion, error)
+	DeleteBook(ctx context.Context, id int64) error
+	RestoreBook(ctx context.Context, id int64) (models.Book, error)
+	GetSimilarCandidates(ctx context.Context, book models.Book, yearWindow, pagesWindow, perCriterion int64) ([]models.Book, error)
+	UpdateRankingPrior(ctx context.Context, priorMean float64, priorWeight int64) error
 }
 
 type bookStore struct {
@@ -33,62 +65,442 @@ func NewBookStore(logger *log.Entry, db *sqlx.DB) BookStore {
 }
 
 func (s *bookStore) GetBooks(ctx context.Context, req models.BookRequest) ([]models.Book, error) {
-	var joins string
-	var wheres []string
-	var values []interface{}
+	var args queryArgs
 	var limit string
 
-	joins = joins + " JOIN author AS au ON au.id = bo.author_id"
-	if len(req.Authors) > 0 {
-		values = append(values, req.Authors)
-		wheres = append(wheres, fmt.Sprintf("au.id IN (%s)", req.Authors))
+	wheres := bookWheres(req, "", &args)
+
+	if req.Limit != "" {
+		limit = " LIMIT " + req.Limit
+	}
+
+	var whereConditions string
+	if len(wheres) > 0 {
+		whereConditions = ` WHERE ` + strings.Join(wheres[:], " AND ")
+	}
+
+	orderBy := ` ORDER BY bo.weighted_rating DESC, bo.rating DESC`
+	if req.Ranking == models.RankingRaw {
+		orderBy = ` ORDER BY bo.rating DESC`
+	}
+
+	query := `SELECT ` + bookColumns + ` FROM book AS bo` + whereConditions + orderBy + limit
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanBooks(rows)
+}
+
+// CountBooks counts the books matching the request, whatever its limit and offset
+func (s *bookStore) CountBooks(ctx context.Context, req models.BookRequest) (int64, error) {
+	var args queryArgs
+
+	var whereConditions string
+	if wheres := bookWheres(req, "", &args); len(wheres) > 0 {
+		whereConditions = ` WHERE ` + strings.Join(wheres, " AND ")
+	}
+
+	var total int64
+	query := `SELECT COUNT(*) FROM book AS bo` + whereConditions
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, mapError(fmt.Errorf("error counting books: %w", err))
+	}
+
+	return total, nil
+}
+
+// GetFacets counts the books matching the request for every value of the given facets.
+// The count of a facet applies every filter of the request but its own, so that it tells
+// how many books picking another value would give. The facets are counted in a single
+// query, the active sizes and eras without any book are counted as well.
+func (s *bookStore) GetFacets(ctx context.Context, req models.BookRequest, facets []string) (models.Facets, error) {
+	var args queryArgs
+	var queries []string
+
+	for _, facet := range facets {
+		wheres := bookWheres(req, facet, &args)
+		conditions := "TRUE"
+		if len(wheres) > 0 {
+			conditions = strings.Join(wheres, " AND ")
+		}
+
+		switch facet {
+		case models.FacetAuthors:
+			queries = append(queries, fmt.Sprintf(`SELECT '%s', ba.author_id, COUNT(DISTINCT bo.id) FROM %s AS bo
+			JOIN %s AS ba ON ba.book_id = bo.id WHERE %s GROUP BY ba.author_id`, facet, tableBook, tableBookAuthor, conditions))
+		case models.FacetGenres:
+			// a genre counts the books of its descendants, as its filter matches them
+			queries = append(queries, fmt.Sprintf(`SELECT '%s', tree.root, COUNT(DISTINCT bo.id) FROM %s AS bo
+			JOIN %s AS bg ON bg.book_id = bo.id JOIN (%s) AS tree ON tree.id = bg.genre_id
+			WHERE %s GROUP BY tree.root`, facet, tableBook, tableBookGenre, genreDescendants(""), conditions))
+		case models.FacetSizes:
+			queries = append(queries, fmt.Sprintf(`SELECT '%s', sz.id, COUNT(bo.id) FROM %s AS sz
+			LEFT JOIN %s AS bo ON bo.pages BETWEEN COALESCE(sz.min_pages, %d) AND COALESCE(sz.max_pages, %d) AND %s
+			WHERE sz.active GROUP BY sz.id`, facet, tableSize, tableBook, models.MinPages, models.MaxPages, conditions))
+		case models.FacetEras:
+			queries = append(queries, fmt.Sprintf(`SELECT '%s', er.id, COUNT(bo.id) FROM %s AS er
+			LEFT JOIN %s AS bo ON bo.year_published BETWEEN COALESCE(er.min_year, %d) AND COALESCE(er.max_year, %d) AND %s
+			WHERE er.active GROUP BY er.id`, facet, tableEra, tableBook, models.MinYear, models.MaxYear, conditions))
+		default:
+			return nil, fmt.Errorf("unknown facet %q", facet)
+		}
+	}
+
+	result := make(models.Facets, len(facets))
+	for _, facet := range facets {
+		result[facet] = make([]models.FacetCount, 0)
+	}
+	if len(queries) == 0 {
+		return result, nil
+	}
+
+	query := "(" + strings.Join(queries, ") UNION ALL (") + ") ORDER BY 1, 3 DESC, 2"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+	for rows.Next() {
+		var facet string
+		var count models.FacetCount
+		if err := rows.Scan(&facet, &count.ID, &count.Count); err != nil {
+			return nil, fmt.Errorf("error getting facets: %w", err)
+		}
+		result[facet] = append(result[facet], count)
+	}
+
+	return result, nil
+}
+
+// queryArgs collects the arguments of a query
+type queryArgs []interface{}
+
+// add adds an argument to the query and returns its placeholder
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// bookWheres returns the conditions on the bo alias of the filters of the request,
+// leaving out the filters of the skipped facet, if any. The deleted books never match.
+func bookWheres(req models.BookRequest, skip string, args *queryArgs) []string {
+	wheres := []string{"bo.deleted_at IS NULL"}
+
+	// a book matches the authors whatever their role, and the genres whatever their position.
+	// A merged author matches the books of the author they were merged into, and a genre the
+	// books of its descendants as well.
+	if len(req.Authors) > 0 && skip != models.FacetAuthors {
+		wheres = append(wheres, fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS ba WHERE ba.book_id = bo.id AND ba.author_id IN (%s))",
+			tableBookAuthor, redirectedAuthors(req.Authors)))
+	}
+
+	if skip != models.FacetGenres {
+		if len(req.Genres) > 0 && req.GenreMatch == models.GenreMatchAll {
+			wheres = append(wheres, fmt.Sprintf("(SELECT COUNT(DISTINCT tree.root) FROM (%s) AS tree JOIN %s AS bg ON bg.genre_id = tree.id WHERE bg.book_id = bo.id) = %d",
+				genreDescendants(req.Genres), tableBookGenre, countDistinct(req.Genres)))
+		} else if len(req.Genres) > 0 {
+			wheres = append(wheres, fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS bg WHERE bg.book_id = bo.id AND bg.genre_id IN (SELECT tree.id FROM (%s) AS tree))",
+				tableBookGenre, genreDescendants(req.Genres)))
+		}
 	}
 
-	joins = joins + " JOIN genre AS ge ON ge.id = bo.genre_id"
-	if len(req.Genres) > 0 {
-		values = append(values, req.Genres)
-		wheres = append(wheres, fmt.Sprintf("ge.id IN (%s)", req.Genres))
+	// exclusions win over inclusions, a book by an included and an excluded author is hidden
+	if len(req.ExcludeAuthors) > 0 {
+		wheres = append(wheres, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s AS ba WHERE ba.book_id = bo.id AND ba.author_id IN (%s))",
+			tableBookAuthor, redirectedAuthors(req.ExcludeAuthors)))
 	}
 
-	if req.MinYear != "" || req.MaxYear != "" {
+	if len(req.ExcludeGenres) > 0 {
+		wheres = append(wheres, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s AS bg WHERE bg.book_id = bo.id AND bg.genre_id IN (SELECT tree.id FROM (%s) AS tree))",
+			tableBookGenre, genreDescendants(req.ExcludeGenres)))
+	}
+
+	if req.MinRating != "" {
+		minRating, _ := strconv.ParseFloat(req.MinRating, 64)
+		wheres = append(wheres, "bo.rating >= "+args.add(minRating))
+	}
+
+	if (req.MinYear != "" || req.MaxYear != "") && skip != models.FacetEras {
 		if req.MinYear == "" {
 			req.MinYear = strconv.Itoa(models.MinYear)
 		}
 		if req.MaxYear == "" {
 			req.MaxYear = strconv.Itoa(models.MaxYear)
 		}
-		wheres = append(wheres, fmt.Sprintf(`year_published BETWEEN %s AND %s`, req.MinYear, req.MaxYear))
+		wheres = append(wheres, fmt.Sprintf(`bo.year_published BETWEEN %s AND %s`, req.MinYear, req.MaxYear))
 	}
 
-	if req.MinPages != "" || req.MaxPages != "" {
+	if (req.MinPages != "" || req.MaxPages != "") && skip != models.FacetSizes {
 		if req.MinPages == "" {
 			req.MinPages = strconv.Itoa(models.MinPages)
 		}
 		if req.MaxPages == "" {
 			req.MaxPages = strconv.Itoa(models.MaxPages)
 		}
-		wheres = append(wheres, fmt.Sprintf(`pages BETWEEN %s AND %s`, req.MinPages, req.MaxPages))
+		wheres = append(wheres, fmt.Sprintf(`bo.pages BETWEEN %s AND %s`, req.MinPages, req.MaxPages))
 	}
 
-	if req.Limit != "" {
-		limit = " LIMIT " + req.Limit
+	if req.ISBN != "" {
+		isbn := models.NormalizeISBN(req.ISBN)
+		isbn10, isbn13 := isbn, isbn
+		if len(isbn) == 10 {
+			isbn13 = models.ISBN10To13(isbn)
+		} else if converted, ok := models.ISBN13To10(isbn); ok {
+			isbn10 = converted
+		}
+		wheres = append(wheres, fmt.Sprintf("(bo.isbn10 = %s OR bo.isbn13 = %s)", args.add(isbn10), args.add(isbn13)))
 	}
 
-	var whereConditions string
-	if len(wheres) > 0 {
-		whereConditions = ` WHERE ` + strings.Join(wheres[:], " AND ")
+	// a language matches its own tag and the tags of its regional variants
+	if req.Languages != "" {
+		var languages []string
+		for _, language := range strings.Split(strings.ToLower(req.Languages), ",") {
+			languages = append(languages, fmt.Sprintf("(lower(bo.language) = %s OR lower(bo.language) LIKE %s)",
+				args.add(language), args.add(language+"-%")))
+		}
+		wheres = append(wheres, "("+strings.Join(languages, " OR ")+")")
 	}
 
-	query := `SELECT
-	bo.id, bo.title, bo.year_published, bo.rating, bo.pages,
-	au.id, au.first_name, au.last_name,
-	ge.id, ge.title
-	FROM book AS bo` + joins + whereConditions + ` ORDER BY rating DESC` + limit
+	// a book is the first of its series when no other book of the series comes before it
+	if req.FirstInSeriesOnly == "true" {
+		wheres = append(wheres, fmt.Sprintf(`(bo.series_id IS NULL OR NOT EXISTS (SELECT 1 FROM %s AS prev
+		WHERE prev.series_id = bo.series_id AND prev.series_position < bo.series_position AND prev.deleted_at IS NULL))`, tableBook))
+	}
 
-	rows, err := s.db.QueryContext(ctx, query)
+	if req.ExcludeRead == "true" && req.UserID > 0 {
+		wheres = append(wheres, fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM %s AS se JOIN %s AS sh ON sh.id = se.shelf_id
+		WHERE sh.user_id = %d AND sh.kind = '%s' AND se.book_id = bo.id)`, tableShelfEntry, tableShelf, req.UserID, models.ShelfRead))
+	}
+
+	return wheres
+}
+
+// GetBook returns the book with the given id, models.ErrGone when it was deleted, or
+// models.ErrNotFound when there is none
+func (s *bookStore) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	query := `SELECT ` + bookColumns + ` FROM book AS bo WHERE bo.id = $1 AND bo.deleted_at IS NULL`
+
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return models.Book{}, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	books, err := s.scanBooks(rows)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if len(books) == 0 {
+		return models.Book{}, missingBook(ctx, s.db, id)
+	}
+
+	return books[0], nil
+}
+
+// GetBooksByIDs returns the books with the given ids matching the filters of the request by
+// id, leaving out the deleted and missing ones
+func (s *bookStore) GetBooksByIDs(ctx context.Context, ids []int64, req models.BookRequest) ([]models.Book, error) {
+	var args queryArgs
+
+	wheres := bookWheres(req, "", &args)
+	wheres = append(wheres, "bo.id = ANY("+args.add(pq.Array(ids))+")")
+
+	query := `SELECT ` + bookColumns + ` FROM book AS bo WHERE ` + strings.Join(wheres, " AND ") + ` ORDER BY bo.id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanBooks(rows)
+}
+
+// GetBookHistory returns every version of the book, the current one first, whether it
+// was deleted or not, or models.ErrNotFound when there is none
+func (s *bookStore) GetBookHistory(ctx context.Context, id int64) ([]models.BookVersion, error) {
+	getHistorySQL := fmt.Sprintf(`SELECT bo.version, to_jsonb(bo), bo.updated_at, NULL::TIMESTAMPTZ FROM %s AS bo WHERE bo.id = $1
+	UNION ALL
+	SELECT bh.version, bh.data, bh.valid_from, bh.valid_until FROM %s AS bh WHERE bh.book_id = $1
+	ORDER BY 1 DESC`, tableBook, tableBookHistory)
+
+	rows, err := s.db.QueryContext(ctx, getHistorySQL, id)
 	if err != nil {
-		return nil, fmt.Errorf("error while building query: %w", err)
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
 	}
+	defer s.closeRows(rows)
+
+	versions := make([]models.BookVersion, 0)
+	for rows.Next() {
+		var (
+			version models.BookVersion
+			data    []byte
+		)
+		if err := rows.Scan(&version.Version, &data, &version.ValidFrom, &version.ValidUntil); err != nil {
+			return nil, fmt.Errorf("error getting book version: %w", err)
+		}
+		if err := scanBookVersion(data, &version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	if len(versions) == 0 {
+		return nil, models.NewError(models.KindNotFound, "book %d not found", id)
+	}
+
+	return versions, nil
+}
+
+// DeleteBook marks the book deleted, which records its last version in its history, and
+// records the deletion in the audit log and the outbox. It returns models.ErrGone when the
+// book was already deleted, and models.ErrNotFound when there is none.
+func (s *bookStore) DeleteBook(ctx context.Context, id int64) error {
+	deleteBookSQL := fmt.Sprintf(`UPDATE %s SET deleted_at = now() WHERE id = $1`, tableBook)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		deletedAt, err := lockBook(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if deletedAt.Valid {
+			return models.NewError(models.KindGone, "book %d was deleted", id)
+		}
+
+		before, err := s.getBookTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteBookSQL, id); err != nil {
+			return mapError(fmt.Errorf("error deleting book: %w", err))
+		}
+		if err := addAuditEntry(ctx, tx, models.AuditDelete, models.AuditBook, id, before, nil); err != nil {
+			return err
+		}
+		_, err = addChangeEvent(ctx, tx, models.ChangeBookDeleted, id, map[string]int64{"id": id})
+		return err
+	})
+}
+
+// RestoreBook restores a deleted book, which records its deleted version in its history,
+// and records the restoration in the audit log and the outbox. It returns models.ErrConflict
+// when the book is not deleted, and models.ErrNotFound when there is none.
+func (s *bookStore) RestoreBook(ctx context.Context, id int64) (models.Book, error) {
+	restoreBookSQL := fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = $1`, tableBook)
+
+	var restored models.Book
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		deletedAt, err := lockBook(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !deletedAt.Valid {
+			return models.NewError(models.KindConflict, "book %d is not deleted", id)
+		}
+
+		if _, err := tx.ExecContext(ctx, restoreBookSQL, id); err != nil {
+			return mapError(fmt.Errorf("error restoring book: %w", err))
+		}
+		if restored, err = s.getBookTx(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := addAuditEntry(ctx, tx, models.AuditRestore, models.AuditBook, id, nil, restored); err != nil {
+			return err
+		}
+		_, err = addChangeEvent(ctx, tx, models.ChangeBookRestored, id, restored)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	return restored, nil
+}
+
+// getBookTx returns the book with the given id in the transaction, whether it was deleted or not
+func (s *bookStore) getBookTx(ctx context.Context, tx *sql.Tx, id int64) (models.Book, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT `+bookColumns+` FROM book AS bo WHERE bo.id = $1`, id)
+	if err != nil {
+		return models.Book{}, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	books, err := s.scanBooks(rows)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if len(books) == 0 {
+		return models.Book{}, models.NewError(models.KindNotFound, "book %d not found", id)
+	}
+
+	return books[0], nil
+}
+
+// GetSimilarCandidates returns the books that share a genre or an author of the given
+// book, or that were published or have a number of pages within the given windows. Each
+// criterion brings at most perCriterion books, the closest ones in year and pages first,
+// so that a popular genre or a crowded window never loads the whole catalog.
+func (s *bookStore) GetSimilarCandidates(ctx context.Context, book models.Book, yearWindow, pagesWindow, perCriterion int64) ([]models.Book, error) {
+	genreIDs := make([]int64, 0, len(book.Genres))
+	for _, genre := range book.Genres {
+		genreIDs = append(genreIDs, genre.ID)
+	}
+	authorIDs := make([]int64, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		authorIDs = append(authorIDs, author.ID)
+	}
+
+	// the distance of a candidate is the sum of its year and pages distances to the book,
+	// each relative to its window
+	distance := `LEAST(ABS(bo.year_published - $4)::FLOAT / GREATEST($5, 1), 1) + LEAST(ABS(bo.pages - $6)::FLOAT / GREATEST($7, 1), 1)`
+	branch := func(condition string) string {
+		return `(SELECT bo.id FROM book AS bo WHERE bo.id <> $1 AND bo.deleted_at IS NULL AND ` + condition +
+			` ORDER BY ` + distance + `, bo.weighted_rating DESC, bo.id LIMIT $8)`
+	}
+	query := `SELECT ` + bookColumns + ` FROM book AS bo WHERE bo.id IN (` +
+		branch(`EXISTS (SELECT 1 FROM `+tableBookGenre+` AS bg WHERE bg.book_id = bo.id AND bg.genre_id = ANY($2))`) +
+		` UNION ` + branch(`EXISTS (SELECT 1 FROM `+tableBookAuthor+` AS ba WHERE ba.book_id = bo.id AND ba.author_id = ANY($3))`) +
+		` UNION ` + branch(`bo.year_published BETWEEN $4 - $5 AND $4 + $5`) +
+		` UNION ` + branch(`bo.pages BETWEEN $6 - $7 AND $6 + $7`) + `)`
+
+	rows, err := s.db.QueryContext(ctx, query,
+		book.ID,
+		pq.Array(genreIDs),
+		pq.Array(authorIDs),
+		book.YearPublished, yearWindow,
+		book.Pages, pagesWindow,
+		perCriterion,
+	)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanBooks(rows)
+}
+
+// countDistinct returns the number of distinct values of a comma-delimited list
+func countDistinct(list string) int {
+	distinct := make(map[string]bool)
+	for _, value := range strings.Split(list, ",") {
+		distinct[strings.TrimLeft(value, "0")] = true
+	}
+	return len(distinct)
+}
+
+// scanBooks reads every row selected with bookColumns and closes them
+func (s *bookStore) scanBooks(rows *sql.Rows) ([]models.Book, error) {
 	defer func() {
 		errClose := rows.Close()
 		errRows := rows.Err()
@@ -100,44 +512,151 @@ func (s *bookStore) GetBooks(ctx context.Context, req models.BookRequest) ([]mod
 		}
 	}()
 	books := make([]models.Book, 0)
-	count := 0
 	for rows.Next() {
-		count++
-		var (
-			id            int64
-			title         string
-			yearPublished int64
-			rating        float64
-			pages         int64
-			authorID      int64
-			authorFirst   string
-			authorLast    string
-			genreID       int64
-			genreTitle    string
-		)
-		if err := rows.Scan(&id, &title, &yearPublished, &rating, &pages, &authorID, &authorFirst, &authorLast, &genreID, &genreTitle); err != nil {
-			return nil, fmt.Errorf("error getting books: %w", err)
-		}
-		books = append(
-			books,
-			models.Book{
-				ID:            int64(count),
-				Title:         title,
-				YearPublished: yearPublished,
-				Rating:        rating,
-				Pages:         pages,
-				Genre: models.Genre{
-					ID:    genreID,
-					Title: genreTitle,
-				},
-				Author: models.Author{
-					ID:        authorID,
-					FirstName: authorFirst,
-					LastName:  authorLast,
-				},
-			},
-		)
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
 	}
 
 	return books, nil
 }
+
+// scanBook reads the bookColumns of the current row, after the given leading columns
+func scanBook(rows *sql.Rows, leading ...interface{}) (models.Book, error) {
+	var book models.Book
+	var authors, genres, series []byte
+	dest := append(leading,
+		&book.ID, &book.Title, &book.YearPublished, &book.Rating, &book.RatingCount, &book.WeightedRating, &book.Pages,
+		&book.ISBN10, &book.ISBN13, &book.Language, &book.Publisher, &book.Description, &book.CoverURL,
+		&authors, &genres, &series,
+	)
+	if err := rows.Scan(dest...); err != nil {
+		return models.Book{}, fmt.Errorf("error getting books: %w", err)
+	}
+	if err := json.Unmarshal(authors, &book.Authors); err != nil {
+		return models.Book{}, fmt.Errorf("error getting book authors: %w", err)
+	}
+	if err := json.Unmarshal(genres, &book.Genres); err != nil {
+		return models.Book{}, fmt.Errorf("error getting book genres: %w", err)
+	}
+	if series != nil {
+		if err := json.Unmarshal(series, &book.Series); err != nil {
+			return models.Book{}, fmt.Errorf("error getting book series: %w", err)
+		}
+	}
+
+	return book, nil
+}
+
+// rankingPrior is the prior of the weighted rating as recorded in the audit log
+type rankingPrior struct {
+	PriorMean   float64 `json:"priorMean"`
+	PriorWeight int64   `json:"priorWeight"`
+}
+
+// UpdateRankingPrior stores the prior used for the weighted rating and records it in the
+// audit log. Changing it makes the database refresh the weighted rating of every book, so
+// it is only written when it differs from the current one.
+func (s *bookStore) UpdateRankingPrior(ctx context.Context, priorMean float64, priorWeight int64) error {
+	lockPriorSQL := fmt.Sprintf(`SELECT prior_mean, prior_weight FROM %s FOR UPDATE`, tableRankingPrior)
+	updatePriorSQL := fmt.Sprintf(`UPDATE %s SET prior_mean = $1, prior_weight = $2`, tableRankingPrior)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var before rankingPrior
+		if err := tx.QueryRowContext(ctx, lockPriorSQL).Scan(&before.PriorMean, &before.PriorWeight); err != nil {
+			return fmt.Errorf("error getting ranking prior: %w", err)
+		}
+		after := rankingPrior{PriorMean: priorMean, PriorWeight: priorWeight}
+		if before == after {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, updatePriorSQL, priorMean, priorWeight); err != nil {
+			return fmt.Errorf("error updating ranking prior: %w", err)
+		}
+		// the prior is a single row, recorded under the ID 1
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditRankingPrior, 1, before, after)
+	})
+}
+
+func (s *bookStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}
+
+// missingBook returns why the book with the given id is not among the current ones:
+// models.ErrGone when it was deleted, models.ErrNotFound when there is none
+func missingBook(ctx context.Context, db *sqlx.DB, id int64) error {
+	deletedAtSQL := fmt.Sprintf(`SELECT deleted_at FROM %s WHERE id = $1`, tableBook)
+	var deletedAt sql.NullTime
+	err := db.QueryRowContext(ctx, deletedAtSQL, id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.NewError(models.KindNotFound, "book %d not found", id)
+	}
+	if err != nil {
+		return mapError(fmt.Errorf("error getting book: %w", err))
+	}
+	if deletedAt.Valid {
+		return models.NewError(models.KindGone, "book %d was deleted", id)
+	}
+	return models.NewError(models.KindNotFound, "book %d not found", id)
+}
+
+// lockBook locks the book with the given id for the transaction and returns when it was
+// deleted, if it was, or models.ErrNotFound when there is none
+func lockBook(ctx context.Context, tx *sql.Tx, id int64) (sql.NullTime, error) {
+	return lockBookFor(ctx, tx, id, "UPDATE")
+}
+
+// shareBook locks the book with the given id against its deletion only, enough for the rows
+// referencing it, and returns like lockBook
+func shareBook(ctx context.Context, tx *sql.Tx, id int64) (sql.NullTime, error) {
+	return lockBookFor(ctx, tx, id, "KEY SHARE")
+}
+
+func lockBookFor(ctx context.Context, tx *sql.Tx, id int64, strength string) (sql.NullTime, error) {
+	lockBookSQL := fmt.Sprintf(`SELECT deleted_at FROM %s WHERE id = $1 FOR %s`, tableBook, strength)
+	var deletedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, lockBookSQL, id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sql.NullTime{}, models.NewError(models.KindNotFound, "book %d not found", id)
+	}
+	if err != nil {
+		return sql.NullTime{}, mapError(fmt.Errorf("error locking book: %w", err))
+	}
+	return deletedAt, nil
+}
+
+// scanBookVersion reads the catalog fields of a version from the JSON of its book row
+func scanBookVersion(data []byte, version *models.BookVersion) error {
+	var row struct {
+		Title          string     `json:"title"`
+		YearPublished  int64      `json:"year_published"`
+		Pages          int64      `json:"pages"`
+		ISBN10         string     `json:"isbn10"`
+		ISBN13         string     `json:"isbn13"`
+		Language       string     `json:"language"`
+		Publisher      string     `json:"publisher"`
+		Description    string     `json:"description"`
+		CoverURL       string     `json:"cover_url"`
+		SeriesID       *int64     `json:"series_id"`
+		SeriesPosition *int64     `json:"series_position"`
+		DeletedAt      *time.Time `json:"deleted_at"`
+	}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return fmt.Errorf("error getting book version %d: %w", version.Version, err)
+	}
+
+	version.Title, version.YearPublished, version.Pages = row.Title, row.YearPublished, row.Pages
+	version.ISBN10, version.ISBN13, version.Language = row.ISBN10, row.ISBN13, row.Language
+	version.Publisher, version.Description, version.CoverURL = row.Publisher, row.Description, row.CoverURL
+	version.SeriesID, version.SeriesPosition, version.DeletedAt = row.SeriesID, row.SeriesPosition, row.DeletedAt
+	return nil
+}
@@ -0,0 +1,89 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookStore_GetBooks_Weighted(t *testing.T) {
+	db := testDB(t)
+	// both first books average 4 from the ratings of the users, the second one from more of
+	// them, and the third one has a single perfect rating
+	seed(t, db,
+		`INSERT INTO author (id, first_name, last_name) VALUES (900001, 'Test', 'Author')`,
+		`INSERT INTO book (id, title, year_published, rating, pages) VALUES
+			(900001, 'Few Ratings', 2000, 1, 100), (900002, 'Many Ratings', 2000, 1, 100),
+			(900003, 'Single Rating', 2000, 1, 100)`,
+		`INSERT INTO book_author (book_id, author_id) VALUES (900001, 900001), (900002, 900001), (900003, 900001)`,
+		`INSERT INTO user_rating (user_id, book_id, rating)
+			SELECT u, 900001, 4 FROM generate_series(900001, 900002) AS u`,
+		`INSERT INTO user_rating (user_id, book_id, rating)
+			SELECT u, 900002, 4 FROM generate_series(900001, 900040) AS u`,
+		`INSERT INTO user_rating (user_id, book_id, rating) VALUES (900001, 900003, 5)`,
+	)
+
+	var cases = []struct {
+		name    string
+		ranking string
+		assert  func(books []models.Book, err error)
+	}{
+		{
+			name:    "weighted",
+			ranking: models.RankingWeighted,
+			assert: func(books []models.Book, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, books, 3) {
+					// (10 * 3 + 40 * 4) / 50, (10 * 3 + 5) / 11 and (10 * 3 + 2 * 4) / 12
+					assert.Equal(t, []int64{900002, 900003, 900001}, []int64{books[0].ID, books[1].ID, books[2].ID})
+					assert.Equal(t, int64(40), books[0].RatingCount)
+					assert.Equal(t, int64(2), books[2].RatingCount)
+					assert.Equal(t, books[0].Rating, books[2].Rating)
+					assert.Greater(t, books[0].WeightedRating, books[2].WeightedRating)
+				}
+			},
+		},
+		{
+			name:    "raw",
+			ranking: models.RankingRaw,
+			assert: func(books []models.Book, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, books, 3) {
+					assert.Equal(t, int64(900003), books[0].ID)
+					assert.Equal(t, 5.0, books[0].Rating)
+				}
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := stores.NewBookStore(testLogger(), db)
+			books, err := store.GetBooks(context.Background(), models.BookRequest{Authors: "900001", Ranking: c.ranking})
+			c.assert(books, err)
+		})
+	}
+}
+
+func TestBookStore_GetSimilarCandidates(t *testing.T) {
+	db := testDB(t)
+	// every book shares the genre of the first one, the further its ID the further its year
+	seed(t, db,
+		`INSERT INTO genre (id, title) VALUES (900001, 'Test Genre')`,
+		`INSERT INTO book (id, title, year_published, rating, pages)
+			SELECT id, 'Candidate', id - 899000, 4, 100000 + id FROM generate_series(900001, 900010) AS id`,
+		`INSERT INTO book_genre (book_id, genre_id) SELECT id, 900001 FROM generate_series(900001, 900010) AS id`,
+	)
+	store := stores.NewBookStore(testLogger(), db)
+	book := models.Book{ID: 900001, YearPublished: 1001, Pages: 1000001, Genres: []models.Genre{{ID: 900001}}}
+
+	books, err := store.GetSimilarCandidates(context.Background(), book, 0, 0, 2)
+	assert.NoError(t, err)
+	ids := make([]int64, 0, len(books))
+	for _, candidate := range books {
+		ids = append(ids, candidate.ID)
+	}
+	assert.ElementsMatch(t, []int64{900002, 900003}, ids)
+}
@@ -0,0 +1,115 @@
+package stores
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+// dataQualityRule selects the id of every row breaking a rule of the data of the catalog,
+// along with what is wrong with it
+type dataQualityRule struct {
+	entity string
+	query  string
+}
+
+// dataQualityRules are the queries of models.DataQualityRules. The deleted books are left
+// out, but they still count as books of their authors and genres.
+var dataQualityRules = map[string]dataQualityRule{
+	models.RuleBookPages: {
+		entity: "book",
+		query: fmt.Sprintf(`SELECT id, 'has ' || pages || ' pages' FROM %s
+		WHERE deleted_at IS NULL AND (pages < %d OR pages > %d)`, tableBook, models.MinPages, models.MaxPages),
+	},
+	models.RuleBookYear: {
+		entity: "book",
+		query: fmt.Sprintf(`SELECT id, 'was published in ' || year_published FROM %s
+		WHERE deleted_at IS NULL AND (year_published < %d OR year_published > %d)`, tableBook, models.MinYear, models.MaxYear),
+	},
+	models.RuleBookRating: {
+		entity: "book",
+		query: fmt.Sprintf(`SELECT id, 'is rated ' || rating FROM %s
+		WHERE deleted_at IS NULL AND (rating < %d OR rating > %d)`, tableBook, models.MinRating, models.MaxRating),
+	},
+	models.RuleOrphanAuthor: {
+		entity: "author",
+		query: fmt.Sprintf(`SELECT au.id, au.first_name || ' ' || au.last_name || ' has no book' FROM %s AS au
+		WHERE NOT EXISTS (SELECT 1 FROM %s AS ba WHERE ba.author_id = au.id)`, tableAuthor, tableBookAuthor),
+	},
+	models.RuleOrphanGenre: {
+		entity: "genre",
+		query: fmt.Sprintf(`SELECT ge.id, ge.title || ' has no book nor subgenre' FROM %[1]s AS ge
+		WHERE NOT EXISTS (SELECT 1 FROM %[2]s AS bg WHERE bg.genre_id = ge.id)
+		AND NOT EXISTS (SELECT 1 FROM %[1]s AS child WHERE child.parent_id = ge.id)`, tableGenre, tableBookGenre),
+	},
+	models.RuleDuplicateTitle: {
+		entity: "book",
+		// books are the same when they have the same set of contributors, whatever their roles
+		query: fmt.Sprintf(`SELECT id, '"' || title || '" duplicates book ' || first_id FROM (
+			SELECT id, title, MIN(id) OVER (PARTITION BY author_ids, regexp_replace(lower(trim(title)), '\s+', ' ', 'g')) AS first_id
+			FROM (
+				SELECT bo.id, bo.title, (SELECT array_agg(DISTINCT ba.author_id ORDER BY ba.author_id)
+					FROM %[2]s AS ba WHERE ba.book_id = bo.id) AS author_ids
+				FROM %[1]s AS bo WHERE bo.deleted_at IS NULL
+			) AS authored
+		) AS titled WHERE id <> first_id`, tableBook, tableBookAuthor),
+	},
+}
+
+// DataQualityStore specifies the methods to check the data of the catalog
+type DataQualityStore interface {
+	FindViolations(ctx context.Context, rule string, limit int64) (int64, []models.DataQualityRow, error)
+}
+
+type dataQualityStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewDataQualityStore(logger *log.Entry, db *sqlx.DB) DataQualityStore {
+	return &dataQualityStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// FindViolations returns the number of rows breaking the rule, and the first limit of them by id
+func (s *dataQualityStore) FindViolations(ctx context.Context, rule string, limit int64) (int64, []models.DataQualityRow, error) {
+	check, ok := dataQualityRules[rule]
+	if !ok {
+		return 0, nil, models.NewError(models.KindInvalid, "unknown data-quality rule %q", rule)
+	}
+	findViolationsSQL := fmt.Sprintf(`SELECT id, detail, COUNT(*) OVER () FROM (%s) AS violation (id, detail)
+	ORDER BY id
+	LIMIT $1`, check.query)
+
+	rows, err := s.db.QueryContext(ctx, findViolationsSQL, limit)
+	if err != nil {
+		return 0, nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+
+	var total int64
+	violations := make([]models.DataQualityRow, 0)
+	for rows.Next() {
+		violation := models.DataQualityRow{Entity: check.entity}
+		if err := rows.Scan(&violation.ID, &violation.Detail, &total); err != nil {
+			return 0, nil, mapError(fmt.Errorf("error getting %s violations: %w", rule, err))
+		}
+		violations = append(violations, violation)
+	}
+
+	return total, violations, mapError(rows.Err())
+}
@@ -2,6 +2,8 @@ package stores
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/book-recommendations/service/models"
@@ -11,11 +13,16 @@ import (
 
 const (
 	tableEra = "era"
+
+	eraColumns = "id, title, min_year, max_year, sort_order, active"
 )
 
-// EraStore specifies the methods to get eras
+// EraStore specifies the methods to get and manage eras
 type EraStore interface {
-	GetAllEras(ctx context.Context) ([]models.Era, error)
+	GetAllEras(ctx context.Context, includeInactive bool) ([]models.Era, error)
+	CreateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error)
+	UpdateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error)
+	DeleteEra(ctx context.Context, id int64) error
 }
 
 type eraStore struct {
@@ -30,12 +37,13 @@ func NewEraStore(logger *log.Entry, db *sqlx.DB) EraStore {
 	}
 }
 
-func (s *eraStore) GetAllEras(ctx context.Context) ([]models.Era, error) {
-	getErasSQL := fmt.Sprintf(`SELECT id, title, min_year, max_year FROM %s`, tableEra)
+// GetAllEras returns the eras by sort order, only the active ones unless includeInactive is set
+func (s *eraStore) GetAllEras(ctx context.Context, includeInactive bool) ([]models.Era, error) {
+	getErasSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE active OR $1 ORDER BY sort_order, id`, eraColumns, tableEra)
 
-	rows, err := s.db.QueryContext(ctx, getErasSQL)
+	rows, err := s.db.QueryContext(ctx, getErasSQL, includeInactive)
 	if err != nil {
-		return nil, fmt.Errorf("error while building query: %w", err)
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
 	}
 	defer func() {
 		errClose := rows.Close()
@@ -49,26 +57,139 @@ func (s *eraStore) GetAllEras(ctx context.Context) ([]models.Era, error) {
 	}()
 	eras := make([]models.Era, 0)
 	for rows.Next() {
-		var (
-			id      int64
-			title   string
-			minYear *int64
-			maxYear *int64
-		)
-		if err := rows.Scan(&id, &title, &minYear, &maxYear); err != nil {
-			return nil, fmt.Errorf("error getting eras: %w", err)
+		era, err := scanEra(rows)
+		if err != nil {
+			return nil, err
 		}
-
-		eras = append(
-			eras,
-			models.Era{
-				ID:      id,
-				Title:   title,
-				MinYear: minYear,
-				MaxYear: maxYear,
-			},
-		)
+		eras = append(eras, era)
 	}
 
 	return eras, nil
 }
+
+// CreateEra creates the era and records it in the audit log
+func (s *eraStore) CreateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error) {
+	createEraSQL := fmt.Sprintf(`INSERT INTO %s (title, min_year, max_year, sort_order, active)
+	VALUES ($1, $2, $3, $4, $5) RETURNING %s`, tableEra, eraColumns)
+
+	var created models.Era
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.checkEras(ctx, tx, check); err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, createEraSQL, era.Title, era.MinYear, era.MaxYear, era.SortOrder, era.Active)
+		var err error
+		if created, err = scanEra(row); err != nil {
+			return mapError(fmt.Errorf("error creating era: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditCreate, models.AuditEra, created.ID, nil, created)
+	})
+	if err != nil {
+		return models.Era{}, err
+	}
+
+	return created, nil
+}
+
+// UpdateEra replaces every field of the era and records it in the audit log, or returns
+// models.ErrNotFound when it does not exist
+func (s *eraStore) UpdateEra(ctx context.Context, era models.Era, check func(active []models.Era) error) (models.Era, error) {
+	updateEraSQL := fmt.Sprintf(`UPDATE %s SET title = $2, min_year = $3, max_year = $4, sort_order = $5, active = $6
+	WHERE id = $1 RETURNING %s`, tableEra, eraColumns)
+
+	var updated models.Era
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.checkEras(ctx, tx, check); err != nil {
+			return err
+		}
+		before, err := s.lockEra(ctx, tx, era.ID)
+		if err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, updateEraSQL, era.ID, era.Title, era.MinYear, era.MaxYear, era.SortOrder, era.Active)
+		if updated, err = scanEra(row); err != nil {
+			return mapError(fmt.Errorf("error updating era: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditEra, updated.ID, before, updated)
+	})
+	if err != nil {
+		return models.Era{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteEra deletes the era and records it in the audit log, or returns models.ErrNotFound
+// when it does not exist
+func (s *eraStore) DeleteEra(ctx context.Context, id int64) error {
+	deleteEraSQL := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, tableEra)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := s.lockEra(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteEraSQL, id); err != nil {
+			return mapError(fmt.Errorf("error deleting era: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditDelete, models.AuditEra, id, before, nil)
+	})
+}
+
+// checkEras locks the eras against concurrent writes until the end of the transaction, so
+// that no other write can make check outdated, and calls it with the active eras
+func (s *eraStore) checkEras(ctx context.Context, tx *sql.Tx, check func(active []models.Era) error) error {
+	lockErasSQL := fmt.Sprintf(`LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE`, tableEra)
+	getActiveErasSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE active ORDER BY sort_order, id`, eraColumns, tableEra)
+
+	if _, err := tx.ExecContext(ctx, lockErasSQL); err != nil {
+		return mapError(fmt.Errorf("error locking eras: %w", err))
+	}
+	rows, err := tx.QueryContext(ctx, getActiveErasSQL)
+	if err != nil {
+		return mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			s.logger.WithField("errClose", errClose).Error("something went wrong while closing rows")
+		}
+	}()
+	active := make([]models.Era, 0)
+	for rows.Next() {
+		era, err := scanEra(rows)
+		if err != nil {
+			return err
+		}
+		active = append(active, era)
+	}
+	if err := rows.Err(); err != nil {
+		return mapError(fmt.Errorf("error reading eras: %w", err))
+	}
+
+	return check(active)
+}
+
+// lockEra returns the era, locked until the end of the transaction, or models.ErrNotFound
+// when it does not exist
+func (s *eraStore) lockEra(ctx context.Context, tx *sql.Tx, id int64) (models.Era, error) {
+	lockEraSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 FOR UPDATE`, eraColumns, tableEra)
+
+	era, err := scanEra(tx.QueryRowContext(ctx, lockEraSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Era{}, models.NewError(models.KindNotFound, "era %d not found", id)
+	}
+	if err != nil {
+		return models.Era{}, mapError(err)
+	}
+
+	return era, nil
+}
+
+func scanEra(row rowScanner) (models.Era, error) {
+	var era models.Era
+	err := row.Scan(&era.ID, &era.Title, &era.MinYear, &era.MaxYear, &era.SortOrder, &era.Active)
+	if err != nil {
+		return models.Era{}, fmt.Errorf("error getting era: %w", err)
+	}
+	return era, nil
+}
@@ -0,0 +1,63 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableExperimentEvent = "experiment_event"
+)
+
+// EventStore specifies the methods to record experiment events
+type EventStore interface {
+	CreateEvent(ctx context.Context, event models.Event) error
+}
+
+type eventStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewEventStore(logger *log.Entry, db *sqlx.DB) EventStore {
+	return &eventStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// CreateEvent records the event and adds it to the audit log
+func (s *eventStore) CreateEvent(ctx context.Context, event models.Event) error {
+	createEventSQL := fmt.Sprintf(`INSERT INTO %s (event_type, experiment, variant, subject, book_id, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, tableExperimentEvent)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var id int64
+		err := tx.QueryRowContext(ctx, createEventSQL,
+			event.Type,
+			event.Experiment,
+			event.Variant,
+			event.Subject,
+			event.BookID,
+			event.CreatedAt,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		return addAuditEntry(ctx, tx, models.AuditCreate, models.AuditEvent, id, nil, event)
+	})
+	if errors.Is(mapError(err), models.ErrNotFound) {
+		return models.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error creating event: %w", err)
+	}
+
+	return nil
+}
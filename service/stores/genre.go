@@ -2,6 +2,7 @@ package stores
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/book-recommendations/service/models"
@@ -16,6 +17,7 @@ const (
 // GenreStore specifies the methods to get genres
 type GenreStore interface {
 	GetAllGenres(ctx context.Context) ([]models.Genre, error)
+	GetGenreStats(ctx context.Context) ([]models.GenreStats, error)
 }
 
 type genreStore struct {
@@ -31,39 +33,95 @@ func NewGenreStore(logger *log.Entry, db *sqlx.DB) GenreStore {
 }
 
 func (s *genreStore) GetAllGenres(ctx context.Context) ([]models.Genre, error) {
-	getGenresSQL := fmt.Sprintf(`SELECT id, title FROM %s`, tableGenre)
+	getGenresSQL := fmt.Sprintf(`SELECT id, title, parent_id FROM %s ORDER BY id`, tableGenre)
 
 	rows, err := s.db.QueryContext(ctx, getGenresSQL)
 	if err != nil {
-		return nil, fmt.Errorf("error while building query: %w", err)
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
 	}
-	defer func() {
-		errClose := rows.Close()
-		errRows := rows.Err()
-		if errClose != nil || errRows != nil {
-			s.logger.WithFields(log.Fields{
-				"errClose": errClose,
-				"errRows":  errRows,
-			}).Error("something went wrong while closing rows")
-		}
-	}()
+	defer s.closeRows(rows)
+
 	genres := make([]models.Genre, 0)
 	for rows.Next() {
 		var (
-			id    int64
-			title string
+			genre    models.Genre
+			parentID sql.NullInt64
 		)
-		if err := rows.Scan(&id, &title); err != nil {
+		if err := rows.Scan(&genre.ID, &genre.Title, &parentID); err != nil {
 			return nil, fmt.Errorf("error getting genres: %w", err)
 		}
-		genres = append(
-			genres,
-			models.Genre{
-				ID:    id,
-				Title: title,
-			},
-		)
+		if parentID.Valid {
+			genre.ParentID = &parentID.Int64
+		}
+		genres = append(genres, genre)
 	}
 
 	return genres, nil
 }
+
+// GetGenreStats returns every genre along with the number and the average rating of its
+// books, the books of its descendants included. A book tagged with a genre and one of
+// its descendants counts once. The average rating weighs the books by their number of
+// ratings, or is their plain average when none of them was rated by users.
+func (s *genreStore) GetGenreStats(ctx context.Context) ([]models.GenreStats, error) {
+	getStatsSQL := fmt.Sprintf(`WITH RECURSIVE tree(root, id) AS (
+		SELECT id, id FROM %[1]s
+		UNION
+		SELECT tree.root, ge.id FROM %[1]s AS ge JOIN tree ON ge.parent_id = tree.id
+	)
+	SELECT ge.id, ge.title, ge.parent_id, COUNT(bo.id),
+	COALESCE(SUM(bo.rating * bo.rating_count) / NULLIF(SUM(bo.rating_count), 0), AVG(bo.rating), 0)
+	FROM %[1]s AS ge
+	LEFT JOIN %[2]s AS bo ON bo.id IN (SELECT bg.book_id FROM tree JOIN %[3]s AS bg ON bg.genre_id = tree.id WHERE tree.root = ge.id) AND bo.deleted_at IS NULL
+	GROUP BY ge.id
+	ORDER BY ge.id`, tableGenre, tableBook, tableBookGenre)
+
+	rows, err := s.db.QueryContext(ctx, getStatsSQL)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	stats := make([]models.GenreStats, 0)
+	for rows.Next() {
+		var (
+			genre    models.GenreStats
+			parentID sql.NullInt64
+		)
+		if err := rows.Scan(&genre.ID, &genre.Title, &parentID, &genre.BookCount, &genre.AverageRating); err != nil {
+			return nil, fmt.Errorf("error getting genre stats: %w", err)
+		}
+		if parentID.Valid {
+			genre.ParentID = &parentID.Int64
+		}
+		stats = append(stats, genre)
+	}
+
+	return stats, nil
+}
+
+// genreDescendants returns the query of the pairs of the given genres, a comma-delimited
+// list of IDs or every genre when empty, and of every one of their descendants, themselves
+// included, as the root and id columns
+func genreDescendants(genres string) string {
+	roots := "TRUE"
+	if genres != "" {
+		roots = fmt.Sprintf("id IN (%s)", genres)
+	}
+	return fmt.Sprintf(`WITH RECURSIVE tree(root, id) AS (
+		SELECT id, id FROM %[1]s WHERE %[2]s
+		UNION
+		SELECT tree.root, ge.id FROM %[1]s AS ge JOIN tree ON ge.parent_id = tree.id
+	) SELECT root, id FROM tree`, tableGenre, roots)
+}
+
+func (s *genreStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}
@@ -0,0 +1,65 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenreStore_GetGenreStats(t *testing.T) {
+	db := testDB(t)
+	seed(t, db,
+		`INSERT INTO genre (id, title) VALUES (900001, 'Test Parent')`,
+		`INSERT INTO genre (id, title, parent_id) VALUES (900002, 'Test Child', 900001), (900003, 'Test Empty', 900001)`,
+		`INSERT INTO book (id, title, year_published, rating, pages) VALUES
+			(900001, 'Parent Book', 2000, 4.5, 100), (900002, 'Child Book', 2000, 3.5, 100)`,
+		`INSERT INTO book_genre (book_id, genre_id) VALUES (900001, 900001), (900002, 900002)`,
+	)
+
+	var cases = []struct {
+		name   string
+		id     int64
+		assert func(genre models.GenreStats)
+	}{
+		{
+			name: "descendants included",
+			id:   900001,
+			assert: func(genre models.GenreStats) {
+				assert.Equal(t, int64(2), genre.BookCount)
+				assert.InDelta(t, 4.0, genre.AverageRating, 0.001)
+			},
+		},
+		{
+			name: "own books",
+			id:   900002,
+			assert: func(genre models.GenreStats) {
+				assert.Equal(t, int64(1), genre.BookCount)
+				assert.InDelta(t, 3.5, genre.AverageRating, 0.001)
+			},
+		},
+		{
+			name: "no book",
+			id:   900003,
+			assert: func(genre models.GenreStats) {
+				assert.Equal(t, int64(0), genre.BookCount)
+				assert.Equal(t, 0.0, genre.AverageRating)
+			},
+		},
+	}
+	stats, err := stores.NewGenreStore(testLogger(), db).GetGenreStats(context.Background())
+	assert.NoError(t, err)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, genre := range stats {
+				if genre.ID == c.id {
+					c.assert(genre)
+					return
+				}
+			}
+			t.Errorf("genre %d is missing", c.id)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableUserRating     = "user_rating"
+	tableBookSimilarity = "book_similarity"
+)
+
+// RatingStore specifies the methods to get the ratings of the users and the similarity between books
+type RatingStore interface {
+	GetUserRatings(ctx context.Context, userID int64) ([]models.UserRating, error)
+	GetAllRatings(ctx context.Context) ([]models.UserRating, error)
+	GetSimilarities(ctx context.Context, bookIDs []int64) ([]models.BookSimilarity, error)
+	ReplaceSimilarities(ctx context.Context, similarities []models.BookSimilarity) error
+}
+
+type ratingStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewRatingStore(logger *log.Entry, db *sqlx.DB) RatingStore {
+	return &ratingStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// GetUserRatings returns the ratings of the user, leaving out the ones of the deleted books
+func (s *ratingStore) GetUserRatings(ctx context.Context, userID int64) ([]models.UserRating, error) {
+	getRatingsSQL := fmt.Sprintf(`SELECT ur.user_id, ur.book_id, ur.rating FROM %s AS ur
+	JOIN %s AS bo ON bo.id = ur.book_id
+	WHERE ur.user_id = $1 AND bo.deleted_at IS NULL`, tableUserRating, tableBook)
+
+	rows, err := s.db.QueryContext(ctx, getRatingsSQL, userID)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanRatings(rows)
+}
+
+// GetAllRatings returns the ratings of every user, leaving out the ones of the deleted books
+func (s *ratingStore) GetAllRatings(ctx context.Context) ([]models.UserRating, error) {
+	getRatingsSQL := fmt.Sprintf(`SELECT ur.user_id, ur.book_id, ur.rating FROM %s AS ur
+	JOIN %s AS bo ON bo.id = ur.book_id
+	WHERE bo.deleted_at IS NULL`, tableUserRating, tableBook)
+
+	rows, err := s.db.QueryContext(ctx, getRatingsSQL)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanRatings(rows)
+}
+
+// GetSimilarities returns the similarities of the given books, leaving out the ones of and to
+// the books deleted since they were computed
+func (s *ratingStore) GetSimilarities(ctx context.Context, bookIDs []int64) ([]models.BookSimilarity, error) {
+	getSimilaritiesSQL := fmt.Sprintf(`SELECT bs.book_id, bs.similar_book_id, bs.score, bs.co_raters FROM %s AS bs
+	JOIN %s AS bo ON bo.id = bs.book_id
+	JOIN %s AS sb ON sb.id = bs.similar_book_id
+	WHERE bs.book_id = ANY($1) AND bo.deleted_at IS NULL AND sb.deleted_at IS NULL`,
+		tableBookSimilarity, tableBook, tableBook)
+
+	rows, err := s.db.QueryContext(ctx, getSimilaritiesSQL, pq.Array(bookIDs))
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+	similarities := make([]models.BookSimilarity, 0)
+	for rows.Next() {
+		var similarity models.BookSimilarity
+		if err := rows.Scan(&similarity.BookID, &similarity.SimilarBookID, &similarity.Score, &similarity.CoRaters); err != nil {
+			return nil, fmt.Errorf("error getting similarities: %w", err)
+		}
+		similarities = append(similarities, similarity)
+	}
+
+	return similarities, nil
+}
+
+// ReplaceSimilarities swaps every similarity for the given ones in a single transaction,
+// so readers never see a partially computed matrix, and records the rebuild in the audit log
+func (s *ratingStore) ReplaceSimilarities(ctx context.Context, similarities []models.BookSimilarity) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s`, tableBookSimilarity))
+	if err != nil {
+		return fmt.Errorf("error deleting similarities: %w", err)
+	}
+	replaced, err := deleted.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting similarities: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(tableBookSimilarity, "book_id", "similar_book_id", "score", "co_raters"))
+	if err != nil {
+		return fmt.Errorf("error preparing similarities copy: %w", err)
+	}
+	for _, similarity := range similarities {
+		if _, err := stmt.ExecContext(ctx, similarity.BookID, similarity.SimilarBookID, similarity.Score, similarity.CoRaters); err != nil {
+			stmt.Close()
+			return fmt.Errorf("error copying similarities: %w", err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("error copying similarities: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("error copying similarities: %w", err)
+	}
+	// the rebuild replaces the whole table, recorded as its number of pairs under the ID 0
+	before := map[string]int64{"pairs": replaced}
+	after := map[string]int64{"pairs": int64(len(similarities))}
+	if err := addAuditEntry(ctx, tx, models.AuditRebuild, models.AuditSimilarity, 0, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing similarities: %w", err)
+	}
+
+	return nil
+}
+
+// scanRatings reads every user_id, book_id and rating row and closes them
+func (s *ratingStore) scanRatings(rows *sql.Rows) ([]models.UserRating, error) {
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+	ratings := make([]models.UserRating, 0)
+	for rows.Next() {
+		var rating models.UserRating
+		if err := rows.Scan(&rating.UserID, &rating.BookID, &rating.Rating); err != nil {
+			return nil, fmt.Errorf("error getting ratings: %w", err)
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, nil
+}
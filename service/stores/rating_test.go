@@ -0,0 +1,62 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingStore_DeletedBooks(t *testing.T) {
+	db := testDB(t)
+	// the second book is deleted, its ratings and similarities are left out
+	seed(t, db,
+		`INSERT INTO book (id, title, year_published, rating, pages) VALUES
+			(900001, 'Kept', 2000, 4, 100), (900002, 'Deleted', 2000, 4, 100), (900003, 'Similar', 2000, 4, 100)`,
+		`INSERT INTO user_rating (user_id, book_id, rating) VALUES (900001, 900001, 4), (900001, 900002, 5)`,
+		`INSERT INTO book_similarity (book_id, similar_book_id, score, co_raters) VALUES
+			(900001, 900002, 0.5, 1), (900002, 900001, 0.5, 1), (900001, 900003, 0.8, 1)`,
+		`UPDATE book SET deleted_at = now() WHERE id = 900002`,
+	)
+	store := stores.NewRatingStore(testLogger(), db)
+
+	var cases = []struct {
+		name   string
+		assert func()
+	}{
+		{
+			name: "user ratings",
+			assert: func() {
+				ratings, err := store.GetUserRatings(context.Background(), 900001)
+				assert.NoError(t, err)
+				assert.Equal(t, []models.UserRating{{UserID: 900001, BookID: 900001, Rating: 4}}, ratings)
+			},
+		},
+		{
+			name: "all ratings",
+			assert: func() {
+				ratings, err := store.GetAllRatings(context.Background())
+				assert.NoError(t, err)
+				for _, rating := range ratings {
+					assert.NotEqual(t, int64(900002), rating.BookID)
+				}
+				assert.Contains(t, ratings, models.UserRating{UserID: 900001, BookID: 900001, Rating: 4})
+			},
+		},
+		{
+			name: "similarities",
+			assert: func() {
+				similarities, err := store.GetSimilarities(context.Background(), []int64{900001, 900002})
+				assert.NoError(t, err)
+				assert.Equal(t, []models.BookSimilarity{{BookID: 900001, SimilarBookID: 900003, Score: 0.8, CoRaters: 1}}, similarities)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.assert()
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableSeries = "series"
+
+	// seriesColumns are the columns read by scanSeries, they expect the sr alias
+	seriesColumns = `sr.id, sr.name, sr.description,
+	(SELECT COUNT(*) FROM book AS bo WHERE bo.series_id = sr.id AND bo.deleted_at IS NULL)`
+)
+
+// SeriesStore specifies the methods to get series of books
+type SeriesStore interface {
+	GetAllSeries(ctx context.Context) ([]models.Series, error)
+	GetSeries(ctx context.Context, id int64) (models.Series, error)
+	GetSeriesBooks(ctx context.Context, id int64) ([]models.Book, error)
+}
+
+type seriesStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewSeriesStore(logger *log.Entry, db *sqlx.DB) SeriesStore {
+	return &seriesStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (s *seriesStore) GetAllSeries(ctx context.Context) ([]models.Series, error) {
+	getSeriesSQL := fmt.Sprintf(`SELECT %s FROM %s AS sr ORDER BY sr.name`, seriesColumns, tableSeries)
+
+	rows, err := s.db.QueryContext(ctx, getSeriesSQL)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	series := make([]models.Series, 0)
+	for rows.Next() {
+		var item models.Series
+		if err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.BookCount); err != nil {
+			return nil, fmt.Errorf("error getting series: %w", err)
+		}
+		series = append(series, item)
+	}
+
+	return series, nil
+}
+
+// GetSeries returns the series with the given id, or models.ErrNotFound when there is none
+func (s *seriesStore) GetSeries(ctx context.Context, id int64) (models.Series, error) {
+	getSeriesSQL := fmt.Sprintf(`SELECT %s FROM %s AS sr WHERE sr.id = $1`, seriesColumns, tableSeries)
+
+	var series models.Series
+	err := s.db.QueryRowContext(ctx, getSeriesSQL, id).Scan(&series.ID, &series.Name, &series.Description, &series.BookCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Series{}, models.NewError(models.KindNotFound, "series %d not found", id)
+	}
+	if err != nil {
+		return models.Series{}, mapError(fmt.Errorf("error getting series: %w", err))
+	}
+
+	return series, nil
+}
+
+// GetSeriesBooks returns the books of the series in reading order
+func (s *seriesStore) GetSeriesBooks(ctx context.Context, id int64) ([]models.Book, error) {
+	getBooksSQL := `SELECT ` + bookColumns + ` FROM book AS bo
+	WHERE bo.series_id = $1 AND bo.deleted_at IS NULL
+	ORDER BY bo.series_position`
+
+	rows, err := s.db.QueryContext(ctx, getBooksSQL, id)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	books := make([]models.Book, 0)
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+
+	return books, nil
+}
+
+func (s *seriesStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}
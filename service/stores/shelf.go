@@ -0,0 +1,445 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableShelf      = "shelf"
+	tableShelfEntry = "shelf_entry"
+
+	// shelfColumns are the columns read by scanShelves, they expect the sh alias
+	shelfColumns = `sh.id, sh.name, sh.kind, sh.share_token, sh.created_at, sh.updated_at,
+	(SELECT COUNT(*) FROM shelf_entry AS se JOIN book AS bo ON bo.id = se.book_id AND bo.deleted_at IS NULL
+	WHERE se.shelf_id = sh.id)`
+	// auditedShelfColumns are the columns read by scanAuditedShelf
+	auditedShelfColumns = "id, user_id, name, kind, share_token IS NOT NULL"
+)
+
+// auditedShelf is a shelf as recorded in the audit log, which tells whether it is shared
+// but keeps its token out of the log
+type auditedShelf struct {
+	ID     int64  `json:"id"`
+	UserID int64  `json:"userId"`
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Shared bool   `json:"shared"`
+}
+
+// auditedShelfEntry is an entry of a shelf as recorded in the audit log, under the ID of
+// the shelf
+type auditedShelfEntry struct {
+	BookID   int64 `json:"bookId"`
+	Position int64 `json:"position"`
+}
+
+// ShelfStore specifies the methods to manage the shelves of the users
+type ShelfStore interface {
+	EnsureDefaultShelves(ctx context.Context, userID int64) error
+	GetShelves(ctx context.Context, userID int64) ([]models.Shelf, error)
+	GetShelf(ctx context.Context, userID, shelfID int64) (models.Shelf, error)
+	GetSharedShelf(ctx context.Context, token string) (models.Shelf, error)
+	GetShelfEntries(ctx context.Context, shelfID int64) ([]models.ShelfEntry, error)
+	CreateShelf(ctx context.Context, userID int64, name string) (models.Shelf, error)
+	RenameShelf(ctx context.Context, shelfID int64, name string) error
+	DeleteShelf(ctx context.Context, shelfID int64) error
+	SetShareToken(ctx context.Context, shelfID int64, token *string) error
+	AddEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error
+	MoveEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error
+	RemoveEntry(ctx context.Context, shelfID, bookID int64) error
+}
+
+type shelfStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewShelfStore(logger *log.Entry, db *sqlx.DB) ShelfStore {
+	return &shelfStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// EnsureDefaultShelves creates the built-in shelves the user does not have yet, and records
+// the created ones in the audit log
+func (s *shelfStore) EnsureDefaultShelves(ctx context.Context, userID int64) error {
+	ensureShelvesSQL := fmt.Sprintf(`INSERT INTO %s (user_id, name, kind) VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING RETURNING %s`, tableShelf, auditedShelfColumns)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		for _, kind := range []string{models.ShelfWantToRead, models.ShelfReading, models.ShelfRead} {
+			created, err := scanAuditedShelf(tx.QueryRowContext(ctx, ensureShelvesSQL, userID, models.DefaultShelves[kind], kind))
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error creating default shelves: %w", err)
+			}
+			if err := addAuditEntry(ctx, tx, models.AuditCreate, models.AuditShelf, created.ID, nil, created); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *shelfStore) GetShelves(ctx context.Context, userID int64) ([]models.Shelf, error) {
+	getShelvesSQL := `SELECT ` + shelfColumns + ` FROM shelf AS sh WHERE sh.user_id = $1
+	ORDER BY sh.kind = 'custom', sh.created_at, sh.id`
+
+	rows, err := s.db.QueryContext(ctx, getShelvesSQL, userID)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanShelves(rows)
+}
+
+// GetShelf returns a shelf of the user, or models.ErrNotFound when the user has no such shelf
+func (s *shelfStore) GetShelf(ctx context.Context, userID, shelfID int64) (models.Shelf, error) {
+	getShelfSQL := `SELECT ` + shelfColumns + ` FROM shelf AS sh WHERE sh.id = $1 AND sh.user_id = $2`
+
+	rows, err := s.db.QueryContext(ctx, getShelfSQL, shelfID, userID)
+	if err != nil {
+		return models.Shelf{}, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanShelf(rows)
+}
+
+// GetSharedShelf returns the shelf shared with the given token, or models.ErrNotFound
+func (s *shelfStore) GetSharedShelf(ctx context.Context, token string) (models.Shelf, error) {
+	getShelfSQL := `SELECT ` + shelfColumns + ` FROM shelf AS sh WHERE sh.share_token = $1`
+
+	rows, err := s.db.QueryContext(ctx, getShelfSQL, token)
+	if err != nil {
+		return models.Shelf{}, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+
+	return s.scanShelf(rows)
+}
+
+func (s *shelfStore) GetShelfEntries(ctx context.Context, shelfID int64) ([]models.ShelfEntry, error) {
+	getEntriesSQL := `SELECT se.position, se.added_at, ` + bookColumns + `
+	FROM shelf_entry AS se
+	JOIN book AS bo ON bo.id = se.book_id AND bo.deleted_at IS NULL
+	WHERE se.shelf_id = $1
+	ORDER BY se.position`
+
+	rows, err := s.db.QueryContext(ctx, getEntriesSQL, shelfID)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+	entries := make([]models.ShelfEntry, 0)
+	for rows.Next() {
+		var entry models.ShelfEntry
+		book, err := scanBook(rows, &entry.Position, &entry.AddedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.Book = book
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// CreateShelf creates a custom shelf and records it in the audit log, or returns
+// models.ErrConflict when the user already has one with that name
+func (s *shelfStore) CreateShelf(ctx context.Context, userID int64, name string) (models.Shelf, error) {
+	createShelfSQL := fmt.Sprintf(`INSERT INTO %s (user_id, name, kind) VALUES ($1, $2, $3)
+	RETURNING id, name, kind, share_token, created_at, updated_at`, tableShelf)
+
+	var shelf models.Shelf
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, createShelfSQL, userID, name, models.ShelfCustom).
+			Scan(&shelf.ID, &shelf.Name, &shelf.Kind, &shelf.ShareToken, &shelf.CreatedAt, &shelf.UpdatedAt)
+		if err != nil {
+			return mapError(fmt.Errorf("error creating shelf: %w", err))
+		}
+		created := auditedShelf{ID: shelf.ID, UserID: userID, Name: shelf.Name, Kind: shelf.Kind}
+		return addAuditEntry(ctx, tx, models.AuditCreate, models.AuditShelf, shelf.ID, nil, created)
+	})
+	if err != nil {
+		return models.Shelf{}, err
+	}
+
+	return shelf, nil
+}
+
+// RenameShelf renames the shelf and records it in the audit log, or returns models.ErrNotFound
+// when it does not exist
+func (s *shelfStore) RenameShelf(ctx context.Context, shelfID int64, name string) error {
+	renameShelfSQL := fmt.Sprintf(`UPDATE %s SET name = $2, updated_at = now() WHERE id = $1`, tableShelf)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := lockAuditedShelf(ctx, tx, shelfID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, renameShelfSQL, shelfID, name); err != nil {
+			return mapError(fmt.Errorf("error renaming shelf: %w", err))
+		}
+		after := before
+		after.Name = name
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditShelf, shelfID, before, after)
+	})
+}
+
+// DeleteShelf deletes the shelf along with its entries and records it in the audit log, or
+// returns models.ErrNotFound when it does not exist
+func (s *shelfStore) DeleteShelf(ctx context.Context, shelfID int64) error {
+	deleteShelfSQL := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, tableShelf)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := lockAuditedShelf(ctx, tx, shelfID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteShelfSQL, shelfID); err != nil {
+			return fmt.Errorf("error deleting shelf: %w", err)
+		}
+		return addAuditEntry(ctx, tx, models.AuditDelete, models.AuditShelf, shelfID, before, nil)
+	})
+}
+
+// SetShareToken shares the shelf with the given token, or stops sharing it when the token is
+// nil, and records it in the audit log. It returns models.ErrNotFound when the shelf does not exist.
+func (s *shelfStore) SetShareToken(ctx context.Context, shelfID int64, token *string) error {
+	shareShelfSQL := fmt.Sprintf(`UPDATE %s SET share_token = $2, updated_at = now() WHERE id = $1`, tableShelf)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := lockAuditedShelf(ctx, tx, shelfID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, shareShelfSQL, shelfID, token); err != nil {
+			return mapError(fmt.Errorf("error sharing shelf: %w", err))
+		}
+		after := before
+		after.Shared = token != nil
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditShelf, shelfID, before, after)
+	})
+}
+
+// AddEntry puts a book on the shelf at the given position, moving the following books
+// down, or at the end of the shelf when no position is given
+func (s *shelfStore) AddEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		last, err := lockShelf(ctx, tx, shelfID)
+		if err != nil {
+			return err
+		}
+		// the deleted books cannot be added, but the entries of the books deleted since stay
+		// in place for when they are restored
+		deletedAt, err := shareBook(ctx, tx, entry.BookID)
+		if err != nil {
+			return err
+		}
+		if deletedAt.Valid {
+			return models.NewError(models.KindGone, "book %d was deleted", entry.BookID)
+		}
+
+		position := entry.Position
+		if position <= 0 || position > last {
+			position = last + 1
+		}
+
+		shiftSQL := fmt.Sprintf(`UPDATE %s SET position = position + 1
+		WHERE shelf_id = $1 AND position >= $2`, tableShelfEntry)
+		if _, err := tx.ExecContext(ctx, shiftSQL, shelfID, position); err != nil {
+			return fmt.Errorf("error moving shelf entries: %w", err)
+		}
+
+		addEntrySQL := fmt.Sprintf(`INSERT INTO %s (shelf_id, book_id, position) VALUES ($1, $2, $3)`, tableShelfEntry)
+		if _, err := tx.ExecContext(ctx, addEntrySQL, shelfID, entry.BookID, position); err != nil {
+			return mapError(fmt.Errorf("error adding shelf entry: %w", err))
+		}
+
+		added := auditedShelfEntry{BookID: entry.BookID, Position: position}
+		if err := addAuditEntry(ctx, tx, models.AuditCreate, models.AuditShelfEntry, shelfID, nil, added); err != nil {
+			return err
+		}
+		return touchShelf(ctx, tx, shelfID)
+	})
+}
+
+// MoveEntry moves a book of the shelf to the given position, shifting the books in between
+func (s *shelfStore) MoveEntry(ctx context.Context, shelfID int64, entry models.ShelfEntryRequest) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		last, err := lockShelf(ctx, tx, shelfID)
+		if err != nil {
+			return err
+		}
+
+		var current int64
+		getPositionSQL := fmt.Sprintf(`SELECT position FROM %s WHERE shelf_id = $1 AND book_id = $2`, tableShelfEntry)
+		err = tx.QueryRowContext(ctx, getPositionSQL, shelfID, entry.BookID).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting shelf entry: %w", err)
+		}
+
+		target := min(max(entry.Position, 1), last)
+		shiftSQL := fmt.Sprintf(`UPDATE %s SET position = position + 1
+		WHERE shelf_id = $1 AND position >= $2 AND position < $3`, tableShelfEntry)
+		if target > current {
+			shiftSQL = fmt.Sprintf(`UPDATE %s SET position = position - 1
+			WHERE shelf_id = $1 AND position <= $2 AND position > $3`, tableShelfEntry)
+		}
+		if _, err := tx.ExecContext(ctx, shiftSQL, shelfID, target, current); err != nil {
+			return fmt.Errorf("error moving shelf entries: %w", err)
+		}
+
+		moveEntrySQL := fmt.Sprintf(`UPDATE %s SET position = $3 WHERE shelf_id = $1 AND book_id = $2`, tableShelfEntry)
+		if _, err := tx.ExecContext(ctx, moveEntrySQL, shelfID, entry.BookID, target); err != nil {
+			return fmt.Errorf("error moving shelf entry: %w", err)
+		}
+
+		before := auditedShelfEntry{BookID: entry.BookID, Position: current}
+		after := auditedShelfEntry{BookID: entry.BookID, Position: target}
+		if err := addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditShelfEntry, shelfID, before, after); err != nil {
+			return err
+		}
+		return touchShelf(ctx, tx, shelfID)
+	})
+}
+
+// RemoveEntry takes a book off the shelf, moving the following books up
+func (s *shelfStore) RemoveEntry(ctx context.Context, shelfID, bookID int64) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if _, err := lockShelf(ctx, tx, shelfID); err != nil {
+			return err
+		}
+
+		var position int64
+		removeEntrySQL := fmt.Sprintf(`DELETE FROM %s WHERE shelf_id = $1 AND book_id = $2 RETURNING position`, tableShelfEntry)
+		err := tx.QueryRowContext(ctx, removeEntrySQL, shelfID, bookID).Scan(&position)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error removing shelf entry: %w", err)
+		}
+
+		shiftSQL := fmt.Sprintf(`UPDATE %s SET position = position - 1
+		WHERE shelf_id = $1 AND position > $2`, tableShelfEntry)
+		if _, err := tx.ExecContext(ctx, shiftSQL, shelfID, position); err != nil {
+			return fmt.Errorf("error moving shelf entries: %w", err)
+		}
+
+		removed := auditedShelfEntry{BookID: bookID, Position: position}
+		if err := addAuditEntry(ctx, tx, models.AuditDelete, models.AuditShelfEntry, shelfID, removed, nil); err != nil {
+			return err
+		}
+		return touchShelf(ctx, tx, shelfID)
+	})
+}
+
+// lockShelf locks the shelf against concurrent reorderings and returns its last position
+func lockShelf(ctx context.Context, tx *sql.Tx, shelfID int64) (int64, error) {
+	lockShelfSQL := fmt.Sprintf(`SELECT id FROM %s WHERE id = $1 FOR UPDATE`, tableShelf)
+	var id int64
+	err := tx.QueryRowContext(ctx, lockShelfSQL, shelfID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error locking shelf: %w", err)
+	}
+
+	var last int64
+	lastPositionSQL := fmt.Sprintf(`SELECT COALESCE(MAX(position), 0) FROM %s WHERE shelf_id = $1`, tableShelfEntry)
+	if err := tx.QueryRowContext(ctx, lastPositionSQL, shelfID).Scan(&last); err != nil {
+		return 0, fmt.Errorf("error getting last shelf position: %w", err)
+	}
+
+	return last, nil
+}
+
+// lockAuditedShelf returns the shelf as recorded in the audit log, locked until the end of the
+// transaction, or models.ErrNotFound when it does not exist
+func lockAuditedShelf(ctx context.Context, tx *sql.Tx, shelfID int64) (auditedShelf, error) {
+	lockShelfSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 FOR UPDATE`, auditedShelfColumns, tableShelf)
+
+	shelf, err := scanAuditedShelf(tx.QueryRowContext(ctx, lockShelfSQL, shelfID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return auditedShelf{}, models.ErrNotFound
+	}
+	if err != nil {
+		return auditedShelf{}, fmt.Errorf("error locking shelf: %w", err)
+	}
+	return shelf, nil
+}
+
+func scanAuditedShelf(row rowScanner) (auditedShelf, error) {
+	var shelf auditedShelf
+	err := row.Scan(&shelf.ID, &shelf.UserID, &shelf.Name, &shelf.Kind, &shelf.Shared)
+	return shelf, err
+}
+
+// touchShelf records that the shelf was just updated
+func touchShelf(ctx context.Context, tx *sql.Tx, shelfID int64) error {
+	touchShelfSQL := fmt.Sprintf(`UPDATE %s SET updated_at = now() WHERE id = $1`, tableShelf)
+	if _, err := tx.ExecContext(ctx, touchShelfSQL, shelfID); err != nil {
+		return fmt.Errorf("error updating shelf: %w", err)
+	}
+	return nil
+}
+
+// scanShelf reads the single shelf of the rows, or returns models.ErrNotFound when there is none
+func (s *shelfStore) scanShelf(rows *sql.Rows) (models.Shelf, error) {
+	shelves, err := s.scanShelves(rows)
+	if err != nil {
+		return models.Shelf{}, err
+	}
+	if len(shelves) == 0 {
+		return models.Shelf{}, models.ErrNotFound
+	}
+
+	return shelves[0], nil
+}
+
+// scanShelves reads every row selected with shelfColumns and closes them
+func (s *shelfStore) scanShelves(rows *sql.Rows) ([]models.Shelf, error) {
+	defer func() {
+		errClose := rows.Close()
+		errRows := rows.Err()
+		if errClose != nil || errRows != nil {
+			s.logger.WithFields(log.Fields{
+				"errClose": errClose,
+				"errRows":  errRows,
+			}).Error("something went wrong while closing rows")
+		}
+	}()
+	shelves := make([]models.Shelf, 0)
+	for rows.Next() {
+		var shelf models.Shelf
+		if err := rows.Scan(&shelf.ID, &shelf.Name, &shelf.Kind, &shelf.ShareToken, &shelf.CreatedAt, &shelf.UpdatedAt, &shelf.BookCount); err != nil {
+			return nil, fmt.Errorf("error getting shelves: %w", err)
+		}
+		shelves = append(shelves, shelf)
+	}
+
+	return shelves, nil
+}
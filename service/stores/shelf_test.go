@@ -0,0 +1,54 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/book-recommendations/service/stores"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShelfStore_Audit(t *testing.T) {
+	db := testDB(t)
+	seed(t, db, `INSERT INTO book (id, title, year_published, rating, pages) VALUES (900001, 'Shelved', 2000, 4, 100)`)
+	ctx := stores.WithAuditSource(context.Background(), testActor, "")
+	store := stores.NewShelfStore(testLogger(), db)
+	auditStore := stores.NewAuditStore(testLogger(), db)
+
+	shelf, err := store.CreateShelf(ctx, 900001, "Favourites")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, store.AddEntry(ctx, shelf.ID, models.ShelfEntryRequest{BookID: 900001}))
+	assert.NoError(t, store.RenameShelf(ctx, shelf.ID, "Best"))
+
+	var cases = []struct {
+		name    string
+		entity  string
+		actions []string
+	}{
+		{
+			name:    "shelf",
+			entity:  models.AuditShelf,
+			actions: []string{models.AuditUpdate, models.AuditCreate},
+		},
+		{
+			name:    "entry",
+			entity:  models.AuditShelfEntry,
+			actions: []string{models.AuditCreate},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entries, err := auditStore.GetAuditEntries(context.Background(),
+				models.AuditFilter{Entity: c.entity, EntityID: shelf.ID, Actor: testActor})
+			assert.NoError(t, err)
+			var actions []string
+			for _, entry := range entries {
+				actions = append(actions, entry.Action)
+			}
+			assert.Equal(t, c.actions, actions)
+		})
+	}
+}
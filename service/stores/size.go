@@ -2,6 +2,8 @@ package stores
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/book-recommendations/service/models"
@@ -11,11 +13,16 @@ import (
 
 const (
 	tableSize = "size"
+
+	sizeColumns = "id, title, min_pages, max_pages, sort_order, active"
 )
 
-// SizeStore specifies the methods to get sizes
+// SizeStore specifies the methods to get and manage sizes
 type SizeStore interface {
-	GetAllSizes(ctx context.Context) ([]models.Size, error)
+	GetAllSizes(ctx context.Context, includeInactive bool) ([]models.Size, error)
+	CreateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error)
+	UpdateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error)
+	DeleteSize(ctx context.Context, id int64) error
 }
 
 type sizeStore struct {
@@ -30,12 +37,13 @@ func NewSizeStore(logger *log.Entry, db *sqlx.DB) SizeStore {
 	}
 }
 
-func (s *sizeStore) GetAllSizes(ctx context.Context) ([]models.Size, error) {
-	getSizesSQL := fmt.Sprintf(`SELECT id, title, min_pages, max_pages FROM %s`, tableSize)
+// GetAllSizes returns the sizes by sort order, only the active ones unless includeInactive is set
+func (s *sizeStore) GetAllSizes(ctx context.Context, includeInactive bool) ([]models.Size, error) {
+	getSizesSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE active OR $1 ORDER BY sort_order, id`, sizeColumns, tableSize)
 
-	rows, err := s.db.QueryContext(ctx, getSizesSQL)
+	rows, err := s.db.QueryContext(ctx, getSizesSQL, includeInactive)
 	if err != nil {
-		return nil, fmt.Errorf("error while building query: %w", err)
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
 	}
 	defer func() {
 		errClose := rows.Close()
@@ -49,26 +57,144 @@ func (s *sizeStore) GetAllSizes(ctx context.Context) ([]models.Size, error) {
 	}()
 	sizes := make([]models.Size, 0)
 	for rows.Next() {
-		var (
-			id       int64
-			title    string
-			minPages *int64
-			maxPages *int64
-		)
-		if err := rows.Scan(&id, &title, &minPages, &maxPages); err != nil {
-			return nil, fmt.Errorf("error getting sizes: %w", err)
+		size, err := scanSize(rows)
+		if err != nil {
+			return nil, err
 		}
-
-		sizes = append(
-			sizes,
-			models.Size{
-				ID:       id,
-				Title:    title,
-				MinPages: minPages,
-				MaxPages: maxPages,
-			},
-		)
+		sizes = append(sizes, size)
 	}
 
 	return sizes, nil
 }
+
+// CreateSize creates the size and records it in the audit log
+func (s *sizeStore) CreateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error) {
+	createSizeSQL := fmt.Sprintf(`INSERT INTO %s (title, min_pages, max_pages, sort_order, active)
+	VALUES ($1, $2, $3, $4, $5) RETURNING %s`, tableSize, sizeColumns)
+
+	var created models.Size
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.checkSizes(ctx, tx, check); err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, createSizeSQL, size.Title, size.MinPages, size.MaxPages, size.SortOrder, size.Active)
+		var err error
+		if created, err = scanSize(row); err != nil {
+			return mapError(fmt.Errorf("error creating size: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditCreate, models.AuditSize, created.ID, nil, created)
+	})
+	if err != nil {
+		return models.Size{}, err
+	}
+
+	return created, nil
+}
+
+// UpdateSize replaces every field of the size and records it in the audit log, or returns
+// models.ErrNotFound when it does not exist
+func (s *sizeStore) UpdateSize(ctx context.Context, size models.Size, check func(active []models.Size) error) (models.Size, error) {
+	updateSizeSQL := fmt.Sprintf(`UPDATE %s SET title = $2, min_pages = $3, max_pages = $4, sort_order = $5, active = $6
+	WHERE id = $1 RETURNING %s`, tableSize, sizeColumns)
+
+	var updated models.Size
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.checkSizes(ctx, tx, check); err != nil {
+			return err
+		}
+		before, err := s.lockSize(ctx, tx, size.ID)
+		if err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, updateSizeSQL, size.ID, size.Title, size.MinPages, size.MaxPages, size.SortOrder, size.Active)
+		if updated, err = scanSize(row); err != nil {
+			return mapError(fmt.Errorf("error updating size: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditSize, updated.ID, before, updated)
+	})
+	if err != nil {
+		return models.Size{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteSize deletes the size and records it in the audit log, or returns models.ErrNotFound
+// when it does not exist
+func (s *sizeStore) DeleteSize(ctx context.Context, id int64) error {
+	deleteSizeSQL := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, tableSize)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := s.lockSize(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteSizeSQL, id); err != nil {
+			return mapError(fmt.Errorf("error deleting size: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditDelete, models.AuditSize, id, before, nil)
+	})
+}
+
+// checkSizes locks the sizes against concurrent writes until the end of the transaction, so
+// that no other write can make check outdated, and calls it with the active sizes
+func (s *sizeStore) checkSizes(ctx context.Context, tx *sql.Tx, check func(active []models.Size) error) error {
+	lockSizesSQL := fmt.Sprintf(`LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE`, tableSize)
+	getActiveSizesSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE active ORDER BY sort_order, id`, sizeColumns, tableSize)
+
+	if _, err := tx.ExecContext(ctx, lockSizesSQL); err != nil {
+		return mapError(fmt.Errorf("error locking sizes: %w", err))
+	}
+	rows, err := tx.QueryContext(ctx, getActiveSizesSQL)
+	if err != nil {
+		return mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			s.logger.WithField("errClose", errClose).Error("something went wrong while closing rows")
+		}
+	}()
+	active := make([]models.Size, 0)
+	for rows.Next() {
+		size, err := scanSize(rows)
+		if err != nil {
+			return err
+		}
+		active = append(active, size)
+	}
+	if err := rows.Err(); err != nil {
+		return mapError(fmt.Errorf("error reading sizes: %w", err))
+	}
+
+	return check(active)
+}
+
+// lockSize returns the size, locked until the end of the transaction, or models.ErrNotFound
+// when it does not exist
+func (s *sizeStore) lockSize(ctx context.Context, tx *sql.Tx, id int64) (models.Size, error) {
+	lockSizeSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 FOR UPDATE`, sizeColumns, tableSize)
+
+	size, err := scanSize(tx.QueryRowContext(ctx, lockSizeSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Size{}, models.NewError(models.KindNotFound, "size %d not found", id)
+	}
+	if err != nil {
+		return models.Size{}, mapError(err)
+	}
+
+	return size, nil
+}
+
+// rowScanner is a row of *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSize(row rowScanner) (models.Size, error) {
+	var size models.Size
+	err := row.Scan(&size.ID, &size.Title, &size.MinPages, &size.MaxPages, &size.SortOrder, &size.Active)
+	if err != nil {
+		return models.Size{}, fmt.Errorf("error getting size: %w", err)
+	}
+	return size, nil
+}
@@ -1,8 +1,24 @@
 package stores
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/book-recommendations/service/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	// foreignKeyViolation is the postgres error code raised when a row references a missing one
+	foreignKeyViolation = "23503"
+	// uniqueViolation is the postgres error code raised when a row duplicates a unique key
+	uniqueViolation = "23505"
+	// queryCanceled is the postgres error code raised when a query is canceled, by the
+	// statement timeout or because the context of the request is done
+	queryCanceled = "57014"
 )
 
 type Store struct {
@@ -26,3 +42,41 @@ func NewStore(databaseURL string) (Store, error) {
 func (s *Store) GetDB() *sqlx.DB {
 	return s.db
 }
+
+// withTx runs fn in a transaction, committed when fn succeeds and rolled back otherwise
+func withTx(ctx context.Context, db *sqlx.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// mapError turns the constraint violations and the canceled queries into the matching model errors
+func mapError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case foreignKeyViolation:
+			return models.ErrNotFound
+		case uniqueViolation:
+			return models.ErrConflict
+		case queryCanceled:
+			return models.WrapError(models.KindTimeout, err, "the query did not finish in time")
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.WrapError(models.KindTimeout, err, "the query did not finish in time")
+	}
+	return err
+}
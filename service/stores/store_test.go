@@ -0,0 +1,69 @@
+package stores_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+// testIDs is the first ID of the rows seeded by the tests, far above the ones of the sample data
+const testIDs = 900000
+
+// testActor is the actor of the writes of the tests, whose audit entries are deleted with the seeds
+const testActor = "test"
+
+// testDB connects to the migrated database of TEST_DB_URL, and skips the test when it is not
+// set. The rows seeded by the test are deleted when it ends.
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DB_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	db, err := sqlx.Connect("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("error connecting to the test database: %v", err)
+	}
+	t.Cleanup(func() {
+		deleteSeeds(t, db)
+		db.Close()
+	})
+	deleteSeeds(t, db)
+
+	return db
+}
+
+// seed runs the statements seeding the rows of a test, failing it on the first error
+func seed(t *testing.T, db *sqlx.DB, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("error seeding %q: %v", statement, err)
+		}
+	}
+}
+
+func deleteSeeds(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+	for _, statement := range []string{
+		`DELETE FROM shelf WHERE user_id >= $1`,
+		`DELETE FROM user_rating WHERE book_id >= $1 OR user_id >= $1`,
+		`DELETE FROM book_similarity WHERE book_id >= $1 OR similar_book_id >= $1`,
+		`DELETE FROM book WHERE id >= $1`,
+		`DELETE FROM author WHERE id >= $1`,
+		`DELETE FROM genre WHERE id >= $1`,
+		`DELETE FROM outbox_event WHERE entity_id >= $1`,
+		`DELETE FROM audit_log WHERE entity_id >= $1 OR actor = '` + testActor + `'`,
+	} {
+		if _, err := db.Exec(statement, testIDs); err != nil {
+			t.Fatalf("error deleting the seeded rows with %q: %v", statement, err)
+		}
+	}
+}
+
+func testLogger() *log.Entry {
+	return log.NewEntry(log.New())
+}
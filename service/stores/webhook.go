@@ -0,0 +1,470 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/book-recommendations/service/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tableWebhookSubscription = "webhook_subscription"
+	tableOutboxEvent         = "outbox_event"
+	tableWebhookDelivery     = "webhook_delivery"
+	tableWebhookAttempt      = "webhook_attempt"
+
+	subscriptionColumns = "id, url, secret, event_types, active, created_at, updated_at"
+	// deliveryColumns are the columns read by scanDelivery, they expect the wd and ev aliases
+	deliveryColumns = "wd.id, wd.subscription_id, wd.event_id, ev.event_type, wd.status, wd.attempts, wd.next_attempt_at, wd.delivered_at, wd.created_at"
+)
+
+// WebhookStore specifies the methods to manage the webhook subscriptions and deliver
+// the events of the outbox
+type WebhookStore interface {
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int64) (models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, subscriptionID int64, status string, limit int64) ([]models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (models.WebhookDelivery, error)
+	Ping(ctx context.Context, subscriptionID int64) (models.WebhookDelivery, error)
+	FanOutEvents(ctx context.Context, limit int64) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]models.DueDelivery, error)
+	RecordAttempt(ctx context.Context, delivery models.WebhookDelivery, attempt models.WebhookAttempt) error
+}
+
+type webhookStore struct {
+	logger *log.Entry
+	db     *sqlx.DB
+}
+
+func NewWebhookStore(logger *log.Entry, db *sqlx.DB) WebhookStore {
+	return &webhookStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (s *webhookStore) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	getSubscriptionsSQL := fmt.Sprintf(`SELECT %s FROM %s ORDER BY id`, subscriptionColumns, tableWebhookSubscription)
+
+	rows, err := s.db.QueryContext(ctx, getSubscriptionsSQL)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	subscriptions := make([]models.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription returns the subscription, or models.ErrNotFound when it does not exist
+func (s *webhookStore) GetSubscription(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	getSubscriptionSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, subscriptionColumns, tableWebhookSubscription)
+
+	subscription, err := scanSubscription(s.db.QueryRowContext(ctx, getSubscriptionSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WebhookSubscription{}, models.NewError(models.KindNotFound, "webhook %d not found", id)
+	}
+	if err != nil {
+		return models.WebhookSubscription{}, mapError(err)
+	}
+
+	return subscription, nil
+}
+
+// CreateSubscription creates the subscription and records it in the audit log
+func (s *webhookStore) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	createSubscriptionSQL := fmt.Sprintf(`INSERT INTO %s (url, secret, event_types, active) VALUES ($1, $2, $3, $4)
+	RETURNING %s`, tableWebhookSubscription, subscriptionColumns)
+
+	var created models.WebhookSubscription
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, createSubscriptionSQL,
+			subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active)
+		var err error
+		if created, err = scanSubscription(row); err != nil {
+			return mapError(fmt.Errorf("error creating webhook: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditCreate, models.AuditWebhook, created.ID, nil, created)
+	})
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	return created, nil
+}
+
+// UpdateSubscription replaces every field of the subscription and records it in the audit log,
+// or returns models.ErrNotFound when it does not exist. The secret is never recorded.
+func (s *webhookStore) UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	updateSubscriptionSQL := fmt.Sprintf(`UPDATE %s SET url = $2, secret = $3, event_types = $4, active = $5, updated_at = now()
+	WHERE id = $1 RETURNING %s`, tableWebhookSubscription, subscriptionColumns)
+
+	var updated models.WebhookSubscription
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := s.lockSubscription(ctx, tx, subscription.ID)
+		if err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, updateSubscriptionSQL,
+			subscription.ID, subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active)
+		if updated, err = scanSubscription(row); err != nil {
+			return mapError(fmt.Errorf("error updating webhook: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditUpdate, models.AuditWebhook, updated.ID, before, updated)
+	})
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteSubscription deletes the subscription along with its deliveries and records it in the
+// audit log, or returns models.ErrNotFound when it does not exist
+func (s *webhookStore) DeleteSubscription(ctx context.Context, id int64) error {
+	deleteSubscriptionSQL := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, tableWebhookSubscription)
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := s.lockSubscription(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteSubscriptionSQL, id); err != nil {
+			return mapError(fmt.Errorf("error deleting webhook: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditDelete, models.AuditWebhook, id, before, nil)
+	})
+}
+
+// lockSubscription returns the subscription, locked until the end of the transaction, or
+// models.ErrNotFound when it does not exist
+func (s *webhookStore) lockSubscription(ctx context.Context, tx *sql.Tx, id int64) (models.WebhookSubscription, error) {
+	lockSubscriptionSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 FOR UPDATE`, subscriptionColumns, tableWebhookSubscription)
+
+	subscription, err := scanSubscription(tx.QueryRowContext(ctx, lockSubscriptionSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WebhookSubscription{}, models.NewError(models.KindNotFound, "webhook %d not found", id)
+	}
+	if err != nil {
+		return models.WebhookSubscription{}, mapError(err)
+	}
+
+	return subscription, nil
+}
+
+// GetDeliveries returns the latest deliveries of the subscription along with their
+// attempts, only the ones with the given status unless it is empty
+func (s *webhookStore) GetDeliveries(ctx context.Context, subscriptionID int64, status string, limit int64) ([]models.WebhookDelivery, error) {
+	getDeliveriesSQL := fmt.Sprintf(`SELECT %s FROM %s AS wd JOIN %s AS ev ON ev.id = wd.event_id
+	WHERE wd.subscription_id = $1 AND ($2 = '' OR wd.status = $2)
+	ORDER BY wd.id DESC LIMIT $3`, deliveryColumns, tableWebhookDelivery, tableOutboxEvent)
+
+	rows, err := s.db.QueryContext(ctx, getDeliveriesSQL, subscriptionID, status, limit)
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(fmt.Errorf("error getting deliveries: %w", err))
+	}
+
+	return deliveries, s.withAttempts(ctx, deliveries)
+}
+
+// withAttempts sets the attempts of the deliveries, latest first
+func (s *webhookStore) withAttempts(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.WebhookDelivery, len(deliveries))
+	ids := make([]int64, 0, len(deliveries))
+	for i := range deliveries {
+		deliveries[i].Log = make([]models.WebhookAttempt, 0)
+		byID[deliveries[i].ID] = &deliveries[i]
+		ids = append(ids, deliveries[i].ID)
+	}
+
+	getAttemptsSQL := fmt.Sprintf(`SELECT delivery_id, attempt, attempted_at, status_code, error, duration_ms FROM %s
+	WHERE delivery_id = ANY($1) ORDER BY delivery_id, attempt DESC`, tableWebhookAttempt)
+
+	rows, err := s.db.QueryContext(ctx, getAttemptsSQL, pq.Array(ids))
+	if err != nil {
+		return mapError(fmt.Errorf("error while building query: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	for rows.Next() {
+		var (
+			deliveryID int64
+			attempt    models.WebhookAttempt
+			message    *string
+		)
+		err := rows.Scan(&deliveryID, &attempt.Attempt, &attempt.AttemptedAt, &attempt.StatusCode, &message, &attempt.DurationMS)
+		if err != nil {
+			return fmt.Errorf("error getting attempts: %w", err)
+		}
+		if message != nil {
+			attempt.Error = *message
+		}
+		delivery := byID[deliveryID]
+		delivery.Log = append(delivery.Log, attempt)
+	}
+
+	return nil
+}
+
+// RetryDelivery makes a dead delivery of the subscription pending again, for a new round
+// of attempts, and records it in the audit log. It returns models.ErrNotFound when the
+// subscription has no such delivery, and models.ErrConflict when the delivery is not dead.
+func (s *webhookStore) RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (models.WebhookDelivery, error) {
+	lockDeliverySQL := fmt.Sprintf(`SELECT %s FROM %s AS wd JOIN %s AS ev ON ev.id = wd.event_id
+	WHERE wd.id = $1 AND wd.subscription_id = $2 FOR UPDATE OF wd`, deliveryColumns, tableWebhookDelivery, tableOutboxEvent)
+	retryDeliverySQL := fmt.Sprintf(`UPDATE %s AS wd SET status = '%s', attempts = 0, next_attempt_at = now()
+	FROM %s AS ev WHERE ev.id = wd.event_id AND wd.id = $1
+	RETURNING %s`, tableWebhookDelivery, models.DeliveryPending, tableOutboxEvent, deliveryColumns)
+
+	var retried models.WebhookDelivery
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		before, err := scanDelivery(tx.QueryRowContext(ctx, lockDeliverySQL, deliveryID, subscriptionID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.NewError(models.KindNotFound, "delivery %d of webhook %d not found", deliveryID, subscriptionID)
+		}
+		if err != nil {
+			return mapError(err)
+		}
+		if before.Status != models.DeliveryDead {
+			return models.NewError(models.KindConflict, "delivery %d is %s, only dead deliveries are retried", deliveryID, before.Status)
+		}
+		if retried, err = scanDelivery(tx.QueryRowContext(ctx, retryDeliverySQL, deliveryID)); err != nil {
+			return mapError(fmt.Errorf("error retrying delivery: %w", err))
+		}
+		return addAuditEntry(ctx, tx, models.AuditRetry, models.AuditWebhookDelivery, deliveryID, before, retried)
+	})
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	retried.Log = make([]models.WebhookAttempt, 0)
+
+	return retried, nil
+}
+
+// Ping writes a ping event to the outbox along with its delivery to the subscription,
+// which is the only one it is delivered to, and records it in the audit log, or returns
+// models.ErrNotFound when the subscription does not exist
+func (s *webhookStore) Ping(ctx context.Context, subscriptionID int64) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		eventID, err := addChangeEvent(ctx, tx, models.ChangePing, subscriptionID, map[string]int64{"webhookId": subscriptionID})
+		if err != nil {
+			return err
+		}
+
+		pingSQL := fmt.Sprintf(`WITH dispatched AS (UPDATE %s SET dispatched_at = now() WHERE id = $2 RETURNING id, event_type)
+		INSERT INTO %s AS wd (subscription_id, event_id) SELECT $1, id FROM dispatched
+		RETURNING wd.id, wd.subscription_id, wd.event_id, (SELECT event_type FROM dispatched), wd.status, wd.attempts,
+		wd.next_attempt_at, wd.delivered_at, wd.created_at`, tableOutboxEvent, tableWebhookDelivery)
+		if delivery, err = scanDelivery(tx.QueryRowContext(ctx, pingSQL, subscriptionID, eventID)); err != nil {
+			return err
+		}
+		return addAuditEntry(ctx, tx, models.AuditPing, models.AuditWebhook, subscriptionID, nil, delivery)
+	})
+	if err != nil {
+		if errors.Is(mapError(err), models.ErrNotFound) {
+			return models.WebhookDelivery{}, models.NewError(models.KindNotFound, "webhook %d not found", subscriptionID)
+		}
+		return models.WebhookDelivery{}, mapError(fmt.Errorf("error pinging webhook: %w", err))
+	}
+	delivery.Log = make([]models.WebhookAttempt, 0)
+
+	return delivery, nil
+}
+
+// FanOutEvents creates a delivery of the oldest events of the outbox not yet dispatched
+// for every active subscription to their type, and returns the number of events dispatched
+func (s *webhookStore) FanOutEvents(ctx context.Context, limit int64) (int64, error) {
+	fanOutSQL := fmt.Sprintf(`WITH events AS (
+		SELECT id, event_type FROM %s WHERE dispatched_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED
+	), deliveries AS (
+		INSERT INTO %s (subscription_id, event_id)
+		SELECT ws.id, ev.id FROM events AS ev JOIN %s AS ws ON ws.active AND ev.event_type = ANY(ws.event_types)
+		ON CONFLICT DO NOTHING
+	)
+	UPDATE %s SET dispatched_at = now() WHERE id IN (SELECT id FROM events)`,
+		tableOutboxEvent, tableWebhookDelivery, tableWebhookSubscription, tableOutboxEvent)
+
+	result, err := s.db.ExecContext(ctx, fanOutSQL, limit)
+	if err != nil {
+		return 0, mapError(fmt.Errorf("error fanning out events: %w", err))
+	}
+	dispatched, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error fanning out events: %w", err)
+	}
+
+	return dispatched, nil
+}
+
+// ClaimDeliveries returns the due deliveries of the active subscriptions, the ones waiting
+// the longest first. They are held for the dispatcher by postponing their next attempt
+// by lease, so that they are only attempted again if it stops before recording the attempt.
+func (s *webhookStore) ClaimDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]models.DueDelivery, error) {
+	claimSQL := fmt.Sprintf(`UPDATE %s AS wd SET next_attempt_at = now() + $2 * interval '1 millisecond'
+	FROM %s AS ws, %s AS ev
+	WHERE ws.id = wd.subscription_id AND ev.id = wd.event_id AND wd.id IN (
+		SELECT du.id FROM %s AS du JOIN %s AS su ON su.id = du.subscription_id
+		WHERE du.status = '%s' AND du.next_attempt_at <= now() AND su.active
+		ORDER BY du.next_attempt_at LIMIT $1 FOR UPDATE OF du SKIP LOCKED
+	)
+	RETURNING %s, ws.url, ws.secret, ev.entity_id, ev.payload, ev.created_at`,
+		tableWebhookDelivery, tableWebhookSubscription, tableOutboxEvent,
+		tableWebhookDelivery, tableWebhookSubscription, models.DeliveryPending, deliveryColumns)
+
+	rows, err := s.db.QueryContext(ctx, claimSQL, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, mapError(fmt.Errorf("error claiming deliveries: %w", err))
+	}
+	defer s.closeRows(rows)
+
+	due := make([]models.DueDelivery, 0)
+	for rows.Next() {
+		var (
+			d         models.DueDelivery
+			data      []byte
+			nextAt    *time.Time
+			delivered *time.Time
+		)
+		err := rows.Scan(&d.Delivery.ID, &d.Delivery.SubscriptionID, &d.Delivery.EventID, &d.Delivery.EventType,
+			&d.Delivery.Status, &d.Delivery.Attempts, &nextAt, &delivered, &d.Delivery.CreatedAt,
+			&d.Subscription.URL, &d.Subscription.Secret, &d.Event.EntityID, &data, &d.Event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error getting deliveries: %w", err)
+		}
+		d.Subscription.ID = d.Delivery.SubscriptionID
+		d.Event.ID = d.Delivery.EventID
+		d.Event.Type = d.Delivery.EventType
+		d.Event.Data = json.RawMessage(data)
+		due = append(due, d)
+	}
+	// the claim is an update, so a failure after the first rows must not pass for fewer due deliveries
+	if err := rows.Err(); err != nil {
+		return nil, mapError(fmt.Errorf("error claiming deliveries: %w", err))
+	}
+
+	return due, nil
+}
+
+// RecordAttempt logs an attempt of the delivery and saves its new status, attempts and
+// next attempt
+func (s *webhookStore) RecordAttempt(ctx context.Context, delivery models.WebhookDelivery, attempt models.WebhookAttempt) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var message *string
+		if attempt.Error != "" {
+			message = &attempt.Error
+		}
+		addAttemptSQL := fmt.Sprintf(`INSERT INTO %s (delivery_id, attempt, attempted_at, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6)`, tableWebhookAttempt)
+		_, err := tx.ExecContext(ctx, addAttemptSQL,
+			delivery.ID, attempt.Attempt, attempt.AttemptedAt, attempt.StatusCode, message, attempt.DurationMS)
+		if err != nil {
+			return mapError(fmt.Errorf("error logging attempt: %w", err))
+		}
+
+		nextAttemptAt := time.Now()
+		if delivery.NextAttemptAt != nil {
+			nextAttemptAt = *delivery.NextAttemptAt
+		}
+		updateDeliverySQL := fmt.Sprintf(`UPDATE %s SET status = $2, attempts = $3, next_attempt_at = $4, delivered_at = $5
+		WHERE id = $1`, tableWebhookDelivery)
+		_, err = tx.ExecContext(ctx, updateDeliverySQL,
+			delivery.ID, delivery.Status, delivery.Attempts, nextAttemptAt, delivery.DeliveredAt)
+		if err != nil {
+			return mapError(fmt.Errorf("error updating delivery: %w", err))
+		}
+
+		return nil
+	})
+}
+
+// addChangeEvent writes the event of a change of the catalog to the outbox, in the
+// transaction of the change, and returns its ID. The dispatcher delivers it once the
+// transaction is committed, and never if it is rolled back.
+func addChangeEvent(ctx context.Context, tx *sql.Tx, eventType string, entityID int64, data interface{}) (int64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding %s event: %w", eventType, err)
+	}
+
+	var id int64
+	addEventSQL := fmt.Sprintf(`INSERT INTO %s (event_type, entity_id, payload) VALUES ($1, $2, $3) RETURNING id`, tableOutboxEvent)
+	if err := tx.QueryRowContext(ctx, addEventSQL, eventType, entityID, payload).Scan(&id); err != nil {
+		return 0, mapError(fmt.Errorf("error adding %s event: %w", eventType, err))
+	}
+
+	return id, nil
+}
+
+func scanSubscription(row rowScanner) (models.WebhookSubscription, error) {
+	var (
+		subscription models.WebhookSubscription
+		eventTypes   pq.StringArray
+	)
+	err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &eventTypes,
+		&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return models.WebhookSubscription{}, fmt.Errorf("error getting webhook: %w", err)
+	}
+	subscription.EventTypes = []string(eventTypes)
+	return subscription, nil
+}
+
+// scanDelivery scans the deliveryColumns, the next attempt is only kept for the pending deliveries
+func scanDelivery(row rowScanner) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &delivery.DeliveredAt, &delivery.CreatedAt)
+	if err != nil {
+		return models.WebhookDelivery{}, fmt.Errorf("error getting delivery: %w", err)
+	}
+	if delivery.Status != models.DeliveryPending {
+		delivery.NextAttemptAt = nil
+	}
+	return delivery, nil
+}
+
+func (s *webhookStore) closeRows(rows *sql.Rows) {
+	errClose := rows.Close()
+	errRows := rows.Err()
+	if errClose != nil || errRows != nil {
+		s.logger.WithFields(log.Fields{
+			"errClose": errClose,
+			"errRows":  errRows,
+		}).Error("something went wrong while closing rows")
+	}
+}